@@ -2,6 +2,7 @@ package config
 
 import (
 	"net"
+	"runtime"
 
 	"github.com/rs/zerolog"
 )
@@ -13,6 +14,57 @@ type ServerConfig struct {
 	Brand      string
 	MOTD       string
 	Logger     zerolog.Logger
+
+	// LanguageDir holds <locale>.json files translating the keys the server
+	// defines, or overriding vanilla keys in a locale. Keys the server defines
+	// fall back to en_us; other vanilla keys are translated by the client.
+	LanguageDir string
+
+	// BlockReport is the path of a blocks.json report replacing the bundled
+	// vanilla 1.21 one.
+	BlockReport string
+
+	// ItemReport is the path of a vanilla registries.json report, which holds
+	// the item registry, replacing the bundled vanilla 1.21 one.
+	ItemReport string
+
+	// RecipeDir is a data pack folder, holding data/, whose recipes and the
+	// item tags they use are added to the bundled vanilla ones. Recipes using
+	// items the item registry doesn't have are skipped.
+	RecipeDir string
+
+	// WorldDir is a vanilla world folder chunks are loaded from and saved to.
+	// When empty chunks are generated and only kept in memory.
+	WorldDir string
+
+	// Generator creates the chunks missing from the world folder, "flat" or
+	// "noise".
+	Generator string
+
+	// Seed makes the noise generator produce the same world every time.
+	Seed int64
+
+	// GenerationWorkers is how many chunks are loaded or generated at once.
+	GenerationWorkers int
+
+	// MaxPlayers is how many players may be online at once.
+	MaxPlayers int
+
+	// ViewDistance is the furthest, in chunks, chunks are sent to players.
+	// Players asking for less get less.
+	ViewDistance int
+
+	// SimulationDistance is the distance, in chunks, around players in which
+	// the world is ticked.
+	SimulationDistance int
+
+	// SpawnProtection is the radius, in blocks, around the spawn point in which
+	// players can't break or place blocks. 0 turns it off, as by default.
+	SpawnProtection int
+
+	// FlatPreset is the layers of the flat generator in vanilla's preset
+	// format, bottom layer first followed by the biome.
+	FlatPreset string
 }
 
 func NewServerConfig(ip_str string, port int, log zerolog.Logger) *ServerConfig {
@@ -30,5 +82,12 @@ func NewServerConfig(ip_str string, port int, log zerolog.Logger) *ServerConfig
 		Brand:      "algernon",
 		MOTD:       "algernon dev server",
 		Logger:     log,
+		Generator:  "flat",
+		FlatPreset: "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains",
+
+		GenerationWorkers:  runtime.NumCPU(),
+		MaxPlayers:         20,
+		ViewDistance:       10,
+		SimulationDistance: 10,
 	}
 }
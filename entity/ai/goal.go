@@ -0,0 +1,142 @@
+// Package ai makes mobs act on their own: the goals they pick from by
+// priority, and the paths taking them where their goals want to go.
+package ai
+
+import (
+	"slices"
+)
+
+// Flag is a part of a mob a goal takes control of. Goals needing the same flag
+// don't run at the same time.
+type Flag uint8
+
+const (
+	FlagMove Flag = 1 << iota
+	FlagLook
+	FlagJump
+	FlagTarget
+)
+
+// Goal is something a mob may do, like wandering around or attacking players.
+type Goal interface {
+	// Flags are the parts of the mob the goal controls while running.
+	Flags() Flag
+	// CanStart reports whether the goal should start now. Goals may pick what
+	// they are about, like a target, when they can.
+	CanStart(mob *Mob) bool
+	// CanContinue reports whether the running goal should keep running.
+	CanContinue(mob *Mob) bool
+	Start(mob *Mob)
+	Stop(mob *Mob)
+	// Tick runs every tick while the goal is running.
+	Tick(mob *Mob)
+}
+
+type prioritizedGoal struct {
+	priority int
+	goal     Goal
+	running  bool
+}
+
+// Selector picks the goals of a mob, as vanilla's goal selector. A goal starts
+// when it can and each of its flags is free or held by a goal of a lower
+// priority, which it interrupts. Lower numbers are higher priorities.
+type Selector struct {
+	goals   []*prioritizedGoal
+	holders map[Flag]*prioritizedGoal
+}
+
+// Add adds a goal at a priority, after the goals of the same priority.
+func (s *Selector) Add(priority int, g Goal) {
+	i, _ := slices.BinarySearchFunc(s.goals, priority+1, func(e *prioritizedGoal, p int) int {
+		return e.priority - p
+	})
+	s.goals = slices.Insert(s.goals, i, &prioritizedGoal{priority: priority, goal: g})
+}
+
+// Running returns the running goals by priority.
+func (s *Selector) Running() []Goal {
+	var running []Goal
+	for _, e := range s.goals {
+		if e.running {
+			running = append(running, e.goal)
+		}
+	}
+	return running
+}
+
+// StopAll stops every running goal.
+func (s *Selector) StopAll(mob *Mob) {
+	for _, e := range s.goals {
+		if e.running {
+			s.stop(mob, e)
+		}
+	}
+}
+
+func (s *Selector) tick(mob *Mob) {
+	for _, e := range s.goals {
+		if e.running && !e.goal.CanContinue(mob) {
+			s.stop(mob, e)
+		}
+	}
+	for _, e := range s.goals {
+		if e.running || !s.available(e) || !e.goal.CanStart(mob) {
+			continue
+		}
+		for _, f := range flags(e.goal.Flags()) {
+			if holder := s.holders[f]; holder != nil {
+				s.stop(mob, holder)
+			}
+		}
+		s.start(mob, e)
+	}
+	for _, e := range s.goals {
+		if e.running {
+			e.goal.Tick(mob)
+		}
+	}
+}
+
+// available reports whether each flag of a goal is free or held by a goal it
+// may interrupt.
+func (s *Selector) available(e *prioritizedGoal) bool {
+	for _, f := range flags(e.goal.Flags()) {
+		if holder := s.holders[f]; holder != nil && holder.priority <= e.priority {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Selector) start(mob *Mob, e *prioritizedGoal) {
+	if s.holders == nil {
+		s.holders = make(map[Flag]*prioritizedGoal)
+	}
+	for _, f := range flags(e.goal.Flags()) {
+		s.holders[f] = e
+	}
+	e.running = true
+	e.goal.Start(mob)
+}
+
+func (s *Selector) stop(mob *Mob, e *prioritizedGoal) {
+	for _, f := range flags(e.goal.Flags()) {
+		if s.holders[f] == e {
+			delete(s.holders, f)
+		}
+	}
+	e.running = false
+	e.goal.Stop(mob)
+}
+
+// flags returns the single flags set in f.
+func flags(f Flag) []Flag {
+	var set []Flag
+	for bit := Flag(1); bit != 0; bit <<= 1 {
+		if f&bit != 0 {
+			set = append(set, bit)
+		}
+	}
+	return set
+}
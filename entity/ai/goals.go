@@ -0,0 +1,312 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hunterros-s/algernon/entity"
+)
+
+// RandomStroll wanders to random places nearby every now and then.
+type RandomStroll struct {
+	Speed float64
+	// Interval is how many ticks mobs wait between strolls on average.
+	Interval int
+
+	x, y, z float64
+}
+
+func (*RandomStroll) Flags() Flag {
+	return FlagMove
+}
+
+func (g *RandomStroll) CanStart(mob *Mob) bool {
+	if !mob.Navigator.Done() || rand.Intn(g.Interval) != 0 {
+		return false
+	}
+	var ok bool
+	g.x, g.y, g.z, ok = randomPosition(mob, 10, 7, nil)
+	return ok
+}
+
+func (*RandomStroll) CanContinue(mob *Mob) bool {
+	return !mob.Navigator.Done()
+}
+
+func (g *RandomStroll) Start(mob *Mob) {
+	mob.Navigator.MoveTo(mob, g.x, g.y, g.z, g.Speed)
+}
+
+func (*RandomStroll) Stop(mob *Mob) {
+	mob.Navigator.Stop()
+}
+
+func (*RandomStroll) Tick(*Mob) {}
+
+// LookAtPlayer looks at a nearby target for a few seconds every now and then.
+type LookAtPlayer struct {
+	Range float64
+	// Probability is the chance of starting every tick.
+	Probability float64
+
+	target int32
+	ticks  int
+}
+
+func (*LookAtPlayer) Flags() Flag {
+	return FlagLook
+}
+
+func (g *LookAtPlayer) CanStart(mob *Mob) bool {
+	if rand.Float64() >= g.Probability {
+		return false
+	}
+	t, ok := mob.NearestTarget(g.Range, false)
+	g.target = t.EntityID
+	return ok
+}
+
+func (g *LookAtPlayer) CanContinue(mob *Mob) bool {
+	t, ok := mob.Target(g.target)
+	return ok && g.ticks > 0 && mob.Distance(t.X, t.Y, t.Z) <= g.Range
+}
+
+func (g *LookAtPlayer) Start(*Mob) {
+	g.ticks = 40 + rand.Intn(40)
+}
+
+func (*LookAtPlayer) Stop(*Mob) {}
+
+func (g *LookAtPlayer) Tick(mob *Mob) {
+	if t, ok := mob.Target(g.target); ok {
+		mob.LookAtTarget(t)
+	}
+	g.ticks--
+}
+
+// RandomLookAround looks in a random direction every now and then.
+type RandomLookAround struct {
+	dx, dz float64
+	ticks  int
+}
+
+func (*RandomLookAround) Flags() Flag {
+	return FlagMove | FlagLook
+}
+
+func (*RandomLookAround) CanStart(*Mob) bool {
+	return rand.Float64() < 0.02
+}
+
+func (g *RandomLookAround) CanContinue(*Mob) bool {
+	return g.ticks > 0
+}
+
+func (g *RandomLookAround) Start(*Mob) {
+	angle := rand.Float64() * 2 * math.Pi
+	g.dx, g.dz = math.Cos(angle), math.Sin(angle)
+	g.ticks = 20 + rand.Intn(20)
+}
+
+func (*RandomLookAround) Stop(*Mob) {}
+
+func (g *RandomLookAround) Tick(mob *Mob) {
+	pos := mob.Position()
+	mob.LookAt(pos.X+g.dx, pos.Y+mob.EyeHeight(), pos.Z+g.dz)
+	g.ticks--
+}
+
+// Follow walks after the nearest target within Range until within Distance
+// of it.
+type Follow struct {
+	Speed           float64
+	Distance, Range float64
+
+	target  int32
+	repaths int
+}
+
+func (*Follow) Flags() Flag {
+	return FlagMove | FlagLook
+}
+
+func (g *Follow) CanStart(mob *Mob) bool {
+	t, ok := mob.NearestTarget(g.Range, false)
+	g.target = t.EntityID
+	return ok && mob.Distance(t.X, t.Y, t.Z) > g.Distance
+}
+
+func (g *Follow) CanContinue(mob *Mob) bool {
+	t, ok := mob.Target(g.target)
+	if !ok {
+		return false
+	}
+	d := mob.Distance(t.X, t.Y, t.Z)
+	return d > g.Distance && d <= g.Range
+}
+
+func (g *Follow) Start(*Mob) {
+	g.repaths = 0
+}
+
+func (*Follow) Stop(mob *Mob) {
+	mob.Navigator.Stop()
+}
+
+func (g *Follow) Tick(mob *Mob) {
+	t, ok := mob.Target(g.target)
+	if !ok {
+		return
+	}
+	mob.LookAtTarget(t)
+	if g.repaths--; g.repaths <= 0 {
+		g.repaths = 10
+		mob.Navigator.MoveTo(mob, t.X, t.Y, t.Z, g.Speed)
+	}
+}
+
+// Flee runs away from targets coming within Distance.
+type Flee struct {
+	Speed    float64
+	Distance float64
+
+	x, y, z float64
+}
+
+func (*Flee) Flags() Flag {
+	return FlagMove
+}
+
+func (g *Flee) CanStart(mob *Mob) bool {
+	t, ok := mob.NearestTarget(g.Distance, false)
+	if !ok {
+		return false
+	}
+	g.x, g.y, g.z, ok = randomPosition(mob, 16, 7, func(x, y, z float64) bool {
+		pos := mob.Position()
+		return distance(x, y, z, t.X, t.Y, t.Z) > distance(pos.X, pos.Y, pos.Z, t.X, t.Y, t.Z)
+	})
+	return ok
+}
+
+func (*Flee) CanContinue(mob *Mob) bool {
+	return !mob.Navigator.Done()
+}
+
+func (g *Flee) Start(mob *Mob) {
+	mob.Navigator.MoveTo(mob, g.x, g.y, g.z, g.Speed)
+}
+
+func (*Flee) Stop(mob *Mob) {
+	mob.Navigator.Stop()
+}
+
+func (*Flee) Tick(*Mob) {}
+
+// MeleeAttack chases the nearest attackable target within Range and hits it
+// once it is close enough.
+type MeleeAttack struct {
+	Speed  float64
+	Damage float64
+	Range  float64
+
+	target   int32
+	repaths  int
+	cooldown int
+}
+
+// attackInterval is how many ticks mobs wait between hits.
+const attackInterval = 20
+
+func (*MeleeAttack) Flags() Flag {
+	return FlagMove | FlagLook
+}
+
+func (g *MeleeAttack) CanStart(mob *Mob) bool {
+	t, ok := mob.NearestTarget(g.Range, true)
+	g.target = t.EntityID
+	return ok
+}
+
+func (g *MeleeAttack) CanContinue(mob *Mob) bool {
+	t, ok := mob.Target(g.target)
+	return ok && t.Attackable && mob.Distance(t.X, t.Y, t.Z) <= g.Range
+}
+
+func (g *MeleeAttack) Start(mob *Mob) {
+	g.repaths, g.cooldown = 0, 0
+	setAggressive(mob, true)
+}
+
+func (*MeleeAttack) Stop(mob *Mob) {
+	setAggressive(mob, false)
+	mob.Navigator.Stop()
+}
+
+func (g *MeleeAttack) Tick(mob *Mob) {
+	t, ok := mob.Target(g.target)
+	if !ok {
+		return
+	}
+	mob.LookAtTarget(t)
+	d := mob.Distance(t.X, t.Y, t.Z)
+	if g.repaths--; g.repaths <= 0 {
+		// far targets move less between repaths than the mob can tell
+		g.repaths = 4 + rand.Intn(7)
+		if d > 32 {
+			g.repaths += 10
+		} else if d > 16 {
+			g.repaths += 5
+		}
+		mob.Navigator.MoveTo(mob, t.X, t.Y, t.Z, g.Speed)
+	}
+
+	if g.cooldown > 0 {
+		g.cooldown--
+	}
+	box, _ := mob.Manager.BoundingBox(mob.ID)
+	reach := math.Sqrt(box.Width*2*box.Width*2 + 0.6)
+	if d <= reach && g.cooldown == 0 {
+		g.cooldown = attackInterval
+		mob.World.Attack(mob.ID, t, g.Damage)
+	}
+}
+
+func setAggressive(mob *Mob, aggressive bool) {
+	if md, ok := mob.Manager.Metadata(mob.ID); ok && md.Schema().Has(entity.MobFlags) {
+		md.SetFlag(entity.MobFlags, entity.MobFlagAggressive, aggressive)
+	}
+}
+
+// randomPositionTries is how many random positions are tried before giving
+// up on finding one to walk to.
+const randomPositionTries = 10
+
+// randomPosition returns a random position a mob can stand at, within
+// horizontal blocks sideways and vertical blocks up or down, passing accept if
+// given.
+func randomPosition(mob *Mob, horizontal, vertical int, accept func(x, y, z float64) bool) (x, y, z float64, ok bool) {
+	pos := mob.Position()
+	start := NodeAt(pos.X, pos.Y, pos.Z)
+	size := mob.size()
+	for range randomPositionTries {
+		n := Node{
+			start.X + rand.Intn(2*horizontal+1) - horizontal,
+			start.Y + rand.Intn(2*vertical+1) - vertical,
+			start.Z + rand.Intn(2*horizontal+1) - horizontal,
+		}
+		if !mob.Navigator.paths.Standable(n, size) {
+			continue
+		}
+		x, y, z = float64(n.X)+0.5, float64(n.Y), float64(n.Z)+0.5
+		if accept == nil || accept(x, y, z) {
+			return x, y, z, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+func distance(x1, y1, z1, x2, y2, z2 float64) float64 {
+	dx, dy, dz := x2-x1, y2-y1, z2-z1
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
@@ -0,0 +1,192 @@
+package ai
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/entity"
+)
+
+// Target is an entity mobs may look at, follow or attack, like a player.
+type Target struct {
+	EntityID int32
+	X, Y, Z  float64
+	// EyeHeight is how high above its position the target sees from.
+	EyeHeight float64
+	// Attackable is whether mobs may attack the target, which players in
+	// creative mode aren't.
+	Attackable bool
+}
+
+// World is what mobs act in: the blocks they move through and the entities
+// they target.
+type World interface {
+	entity.Blocks
+	// Targets returns the entities mobs may target.
+	Targets() []Target
+	// Attack hits a target for damage on behalf of a mob.
+	Attack(attacker entity.ID, target Target, damage float64)
+}
+
+// Vanilla's limits on how fast mobs turn, in degrees per tick
+const (
+	maxBodyTurn  = 90
+	maxHeadTurn  = 10
+	maxPitchTurn = 40
+	// maxHeadAngle is how far the head turns away from the body.
+	maxHeadAngle = 75
+)
+
+// Mob is a living entity acting on its own, as its goals see it.
+type Mob struct {
+	Manager   *entity.Manager
+	ID        entity.ID
+	World     World
+	Navigator *Navigator
+
+	// where the mob looks this tick, if anywhere
+	looking             bool
+	lookX, lookY, lookZ float64
+}
+
+// Position returns where the mob is.
+func (mob *Mob) Position() *entity.Position {
+	pos, _ := mob.Manager.Position(mob.ID)
+	return pos
+}
+
+// Info returns what is built into the type of the mob.
+func (mob *Mob) Info() entity.TypeInfo {
+	identity, _ := mob.Manager.Identity(mob.ID)
+	return entity.TypeOf(identity.Type)
+}
+
+// EyeHeight returns how high above its position the mob sees from.
+func (mob *Mob) EyeHeight() float64 {
+	box, _ := mob.Manager.BoundingBox(mob.ID)
+	return box.Height * 0.85
+}
+
+// LookAt turns the head of the mob towards a point this tick. Goals looking
+// at something call it every tick.
+func (mob *Mob) LookAt(x, y, z float64) {
+	mob.looking = true
+	mob.lookX, mob.lookY, mob.lookZ = x, y, z
+}
+
+// LookAtTarget turns the head of the mob towards the eyes of a target this
+// tick.
+func (mob *Mob) LookAtTarget(t Target) {
+	mob.LookAt(t.X, t.Y+t.EyeHeight, t.Z)
+}
+
+// Distance returns how far the mob is from a point.
+func (mob *Mob) Distance(x, y, z float64) float64 {
+	pos := mob.Position()
+	dx, dy, dz := x-pos.X, y-pos.Y, z-pos.Z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// NearestTarget returns the closest target within a distance, counting only
+// attackable ones if attackable is set.
+func (mob *Mob) NearestTarget(maxDistance float64, attackable bool) (Target, bool) {
+	var nearest Target
+	found := false
+	for _, t := range mob.World.Targets() {
+		if attackable && !t.Attackable {
+			continue
+		}
+		if d := mob.Distance(t.X, t.Y, t.Z); d <= maxDistance {
+			nearest, maxDistance, found = t, d, true
+		}
+	}
+	return nearest, found
+}
+
+// Target returns a target by entity ID, where it is now.
+func (mob *Mob) Target(entityID int32) (Target, bool) {
+	for _, t := range mob.World.Targets() {
+		if t.EntityID == entityID {
+			return t, true
+		}
+	}
+	return Target{}, false
+}
+
+// turn turns the head of the mob towards where it looks, or back in line with
+// its body.
+func (mob *Mob) turn() {
+	pos := mob.Position()
+	if mob.looking {
+		dx, dy, dz := mob.lookX-pos.X, mob.lookY-(pos.Y+mob.EyeHeight()), mob.lookZ-pos.Z
+		yaw := float32(yawTowards(dx, dz))
+		pitch := float32(-math.Atan2(dy, math.Hypot(dx, dz)) * 180 / math.Pi)
+		pos.HeadYaw = approachAngle(pos.HeadYaw, yaw, maxHeadTurn)
+		pos.Pitch = approachAngle(pos.Pitch, pitch, maxPitchTurn)
+		mob.looking = false
+	} else {
+		pos.HeadYaw = approachAngle(pos.HeadYaw, pos.Yaw, maxHeadTurn)
+		pos.Pitch = approachAngle(pos.Pitch, 0, maxPitchTurn)
+	}
+	if d := angleDifference(pos.Yaw, pos.HeadYaw); d > maxHeadAngle {
+		pos.Yaw = wrapDegrees(pos.HeadYaw - maxHeadAngle)
+	} else if d < -maxHeadAngle {
+		pos.Yaw = wrapDegrees(pos.HeadYaw + maxHeadAngle)
+	}
+}
+
+// yawTowards returns the yaw facing a horizontal direction, 0 being south.
+func yawTowards(dx, dz float64) float64 {
+	return math.Atan2(dz, dx)*180/math.Pi - 90
+}
+
+// wrapDegrees wraps an angle to -180 to 180 degrees.
+func wrapDegrees(a float32) float32 {
+	a = float32(math.Mod(float64(a), 360))
+	if a >= 180 {
+		a -= 360
+	} else if a < -180 {
+		a += 360
+	}
+	return a
+}
+
+// angleDifference returns how far to turn from one angle to get to another,
+// the short way.
+func angleDifference(from, to float32) float32 {
+	return wrapDegrees(to - from)
+}
+
+// approachAngle turns an angle towards another by up to limit degrees.
+func approachAngle(from, to, limit float32) float32 {
+	d := angleDifference(from, to)
+	d = float32(math.Max(-float64(limit), math.Min(float64(d), float64(limit))))
+	return wrapDegrees(from + d)
+}
+
+// Brain is the AI of a mob: its goals, and the navigator walking it where
+// they want.
+type Brain struct {
+	Goals Selector
+	mob   Mob
+}
+
+var _ entity.AI = (*Brain)(nil)
+
+// NewBrain returns a brain without goals, for a mob in world finding paths
+// with paths.
+func NewBrain(world World, paths *Pathfinder) *Brain {
+	return &Brain{mob: Mob{World: world, Navigator: &Navigator{paths: paths}}}
+}
+
+// Tick picks and runs the goals of the mob, then walks and turns it. Mobs in
+// chunks that aren't loaded do nothing.
+func (b *Brain) Tick(m *entity.Manager, id entity.ID) {
+	b.mob.Manager, b.mob.ID = m, id
+	pos := b.mob.Position()
+	if !b.mob.World.Loaded(int(math.Floor(pos.X)), int(math.Floor(pos.Z))) {
+		return
+	}
+	b.Goals.tick(&b.mob)
+	b.mob.Navigator.tick(&b.mob)
+	b.mob.turn()
+}
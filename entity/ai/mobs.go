@@ -0,0 +1,49 @@
+package ai
+
+// goals are vanilla's goals of mob types, by name in the entity_type registry.
+// Creepers wander without swelling, as nothing explodes yet.
+var goals = map[string]func(b *Brain){
+	"minecraft:zombie": func(b *Brain) {
+		b.Goals.Add(2, &MeleeAttack{Speed: 1, Damage: 3, Range: 35})
+		monster(b, 1)
+	},
+	"minecraft:skeleton": func(b *Brain) {
+		b.Goals.Add(4, &MeleeAttack{Speed: 1.2, Damage: 2, Range: 16})
+		monster(b, 1)
+	},
+	"minecraft:spider": func(b *Brain) {
+		b.Goals.Add(4, &MeleeAttack{Speed: 1, Damage: 2, Range: 16})
+		monster(b, 0.8)
+	},
+	"minecraft:creeper": func(b *Brain) {
+		monster(b, 0.8)
+	},
+	"minecraft:pig":     animal,
+	"minecraft:cow":     animal,
+	"minecraft:sheep":   animal,
+	"minecraft:chicken": animal,
+}
+
+func monster(b *Brain, strollSpeed float64) {
+	b.Goals.Add(5, &RandomStroll{Speed: strollSpeed, Interval: 120})
+	b.Goals.Add(6, &LookAtPlayer{Range: 8, Probability: 0.02})
+	b.Goals.Add(6, &RandomLookAround{})
+}
+
+func animal(b *Brain) {
+	b.Goals.Add(6, &RandomStroll{Speed: 1, Interval: 120})
+	b.Goals.Add(7, &LookAtPlayer{Range: 6, Probability: 0.02})
+	b.Goals.Add(8, &RandomLookAround{})
+}
+
+// BrainFor returns a brain with vanilla's goals for a mob type, or nil for
+// types that don't act on their own.
+func BrainFor(entityType string, world World, paths *Pathfinder) *Brain {
+	add, ok := goals[entityType]
+	if !ok {
+		return nil
+	}
+	b := NewBrain(world, paths)
+	add(b)
+	return b
+}
@@ -0,0 +1,117 @@
+package ai
+
+import (
+	"math"
+)
+
+// Movement of walking mobs, as vanilla's move control
+const (
+	// inputDecay is how much of its input a mob moves by, as vanilla's zza.
+	inputDecay = 0.98
+	// airControl is the share of their speed mobs move by in the air.
+	airControl   = 0.02
+	jumpVelocity = 0.42
+	// stuckTicks is how long mobs try to get to the next node of a path
+	// before giving up on it.
+	stuckTicks = 60
+)
+
+// Navigator walks a mob along a path to where its goals send it, requesting
+// the path from the pathfinder and waiting for it.
+type Navigator struct {
+	paths   *Pathfinder
+	request *Request
+	dest    Node
+	path    []Node
+	speed   float64
+	stuck   int
+}
+
+// MoveTo walks the mob to a point at speed times its movement speed. Moving to
+// the node it is already going to keeps the path it has.
+func (n *Navigator) MoveTo(mob *Mob, x, y, z, speed float64) {
+	n.speed = speed
+	dest := NodeAt(x, y, z)
+	if dest == n.dest && !n.Done() {
+		return
+	}
+	pos := mob.Position()
+	n.dest = dest
+	n.request = n.paths.Find(NodeAt(pos.X, pos.Y, pos.Z), dest, mob.size())
+	n.path = nil
+	n.stuck = 0
+}
+
+// Stop stops walking.
+func (n *Navigator) Stop() {
+	n.request = nil
+	n.path = nil
+}
+
+// Done reports whether the mob isn't walking anywhere nor waiting for a path.
+func (n *Navigator) Done() bool {
+	return n.request == nil && len(n.path) == 0
+}
+
+// Destination returns where the mob is walking to.
+func (n *Navigator) Destination() Node {
+	return n.dest
+}
+
+func (n *Navigator) tick(mob *Mob) {
+	if n.request != nil {
+		if !n.request.Done() {
+			return
+		}
+		n.path = n.request.Path().Nodes
+		n.request = nil
+	}
+
+	pos := mob.Position()
+	info := mob.Info()
+	box, _ := mob.Manager.BoundingBox(mob.ID)
+	// how close to the middle of a node mobs get before heading to the next
+	reach := 0.75 - box.Width/2
+	if box.Width > 0.75 {
+		reach = box.Width / 2
+	}
+	for len(n.path) > 0 {
+		next := n.path[0]
+		dx, dz := float64(next.X)+0.5-pos.X, float64(next.Z)+0.5-pos.Z
+		if math.Hypot(dx, dz) >= reach || math.Abs(pos.Y-float64(next.Y)) >= 1 {
+			break
+		}
+		n.path = n.path[1:]
+		n.stuck = 0
+	}
+	if len(n.path) == 0 {
+		return
+	}
+	if n.stuck++; n.stuck > stuckTicks {
+		n.Stop()
+		return
+	}
+
+	next := n.path[0]
+	dx, dz := float64(next.X)+0.5-pos.X, float64(next.Z)+0.5-pos.Z
+	pos.Yaw = approachAngle(pos.Yaw, float32(yawTowards(dx, dz)), maxBodyTurn)
+
+	speed := info.MovementSpeed * n.speed
+	input := speed * inputDecay
+	if !pos.OnGround {
+		speed = airControl
+	}
+	yaw := float64(pos.Yaw) * math.Pi / 180
+	v, _ := mob.Manager.Velocity(mob.ID)
+	v.X += -math.Sin(yaw) * input * speed
+	v.Z += math.Cos(yaw) * input * speed
+	if pos.OnGround && float64(next.Y)-pos.Y > info.StepHeight && math.Hypot(dx, dz) < math.Max(1, box.Width) {
+		v.Y = jumpVelocity
+	}
+}
+
+// size returns the size of the mob's bounding box.
+func (mob *Mob) size() Size {
+	box, _ := mob.Manager.BoundingBox(mob.ID)
+	return Size{box.Width, box.Height}
+}
@@ -0,0 +1,383 @@
+package ai
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// Node is a block a mob stands in, at its feet.
+type Node struct {
+	X, Y, Z int
+}
+
+// NodeAt returns the node containing a position.
+func NodeAt(x, y, z float64) Node {
+	return Node{int(math.Floor(x)), int(math.Floor(y)), int(math.Floor(z))}
+}
+
+func (n Node) distance(o Node) float64 {
+	dx, dy, dz := float64(n.X-o.X), float64(n.Y-o.Y), float64(n.Z-o.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// Size is the width and height of a mob's bounding box, which paths have to
+// leave room for.
+type Size struct {
+	Width, Height float64
+}
+
+// box returns the bounding box of a mob standing in the middle of a node.
+func (s Size) box(n Node) block.Box {
+	half := s.Width / 2
+	x, z := float64(n.X)+0.5, float64(n.Z)+0.5
+	return block.Box{
+		MinX: x - half, MinY: float64(n.Y), MinZ: z - half,
+		MaxX: x + half, MaxY: float64(n.Y) + s.Height, MaxZ: z + half,
+	}
+}
+
+// Path is the nodes a mob walks through to get somewhere, not counting the one
+// it starts from.
+type Path struct {
+	Nodes []Node
+	// Reached is whether the path ends at the destination. Paths that can't
+	// reach it end as close to it as the search got.
+	Reached bool
+}
+
+// Costs of moving between nodes, on top of the distance walked
+const (
+	jumpCost = 0.5
+	// dropCost is per block fallen.
+	dropCost = 0.5
+)
+
+// maxDrop is how many blocks mobs jump down on their own.
+const maxDrop = 3
+
+// collisionEpsilon shrinks boxes for collision checks so touching a block
+// isn't being in it.
+const collisionEpsilon = 1e-5
+
+// dangerousBlocks are the blocks paths go around even when mobs could stand in
+// or on them.
+var dangerousBlocks = map[string]bool{
+	"minecraft:lava":             true,
+	"minecraft:fire":             true,
+	"minecraft:soul_fire":        true,
+	"minecraft:magma_block":      true,
+	"minecraft:cactus":           true,
+	"minecraft:campfire":         true,
+	"minecraft:soul_campfire":    true,
+	"minecraft:sweet_berry_bush": true,
+	"minecraft:powder_snow":      true,
+}
+
+// Request is a path being searched for. The search runs over however many
+// ticks the pathfinder's budget needs.
+type Request struct {
+	key    requestKey
+	open   nodeHeap
+	nodes  map[Node]*pathNode
+	best   *pathNode
+	search int // nodes searched
+	path   *Path
+}
+
+type requestKey struct {
+	from, to Node
+	size     Size
+}
+
+// Done reports whether the search is over.
+func (r *Request) Done() bool {
+	return r.path != nil
+}
+
+// Path returns the path found, or nil while the search isn't over.
+func (r *Request) Path() *Path {
+	return r.path
+}
+
+type cachedPath struct {
+	path    *Path
+	expires int
+}
+
+// Pathfinder finds paths through the blocks of a world with A*, searching a
+// limited number of nodes every tick so pathing can't starve the tick loop.
+// Found paths are reused for a while by mobs going the same way. It belongs to
+// the game loop.
+type Pathfinder struct {
+	blocks   entity.Blocks
+	registry *block.Registry
+
+	// Budget is how many nodes are searched every tick, over all requests.
+	Budget int
+	// MaxNodes is how many nodes a request searches before settling for the
+	// closest it got to the destination.
+	MaxNodes int
+	// CacheTicks is how many ticks found paths are reused for.
+	CacheTicks int
+
+	ticks   int
+	queue   []*Request
+	pending map[requestKey]*Request
+	cache   map[requestKey]cachedPath
+}
+
+func NewPathfinder(blocks entity.Blocks) *Pathfinder {
+	return &Pathfinder{
+		blocks:     blocks,
+		registry:   block.Default(),
+		Budget:     4000,
+		MaxNodes:   1000,
+		CacheTicks: 40,
+		pending:    make(map[requestKey]*Request),
+		cache:      make(map[requestKey]cachedPath),
+	}
+}
+
+// Find requests a path between two nodes for a mob of a size. Paths found
+// recently and searches already running for the same way are shared.
+func (p *Pathfinder) Find(from, to Node, size Size) *Request {
+	key := requestKey{from, to, size}
+	if cached, ok := p.cache[key]; ok && cached.expires > p.ticks {
+		return &Request{key: key, path: cached.path}
+	}
+	if r, ok := p.pending[key]; ok {
+		return r
+	}
+
+	start := &pathNode{node: from, heuristic: from.distance(to)}
+	r := &Request{key: key, nodes: map[Node]*pathNode{from: start}, best: start}
+	heap.Push(&r.open, start)
+	p.queue = append(p.queue, r)
+	p.pending[key] = r
+	return r
+}
+
+// Tick is the system searching the requested paths, in the order they were
+// requested, until the budget runs out.
+func (p *Pathfinder) Tick(*entity.Manager) {
+	p.ticks++
+	for key, cached := range p.cache {
+		if cached.expires <= p.ticks {
+			delete(p.cache, key)
+		}
+	}
+
+	budget := p.Budget
+	for len(p.queue) > 0 && budget > 0 {
+		r := p.queue[0]
+		budget -= p.search(r, budget)
+		if r.Done() {
+			p.queue = p.queue[1:]
+			delete(p.pending, r.key)
+			p.cache[r.key] = cachedPath{r.path, p.ticks + p.CacheTicks}
+		}
+	}
+}
+
+// search expands up to budget nodes of a request, returning how many it did.
+func (p *Pathfinder) search(r *Request, budget int) int {
+	searched := 0
+	for searched < budget {
+		if r.open.Len() == 0 || r.search >= p.MaxNodes {
+			r.path = r.best.path(false)
+			break
+		}
+		current := heap.Pop(&r.open).(*pathNode)
+		current.closed = true
+		if current.node == r.key.to {
+			r.path = current.path(true)
+			break
+		}
+		searched++
+		r.search++
+
+		for _, next := range p.neighbors(current.node, r.key.size) {
+			cost := current.cost + next.cost
+			n, ok := r.nodes[next.node]
+			if !ok {
+				n = &pathNode{node: next.node, heuristic: next.node.distance(r.key.to), index: -1}
+				r.nodes[next.node] = n
+			} else if n.closed || cost >= n.cost {
+				continue
+			}
+			n.cost, n.parent = cost, current
+			if n.index < 0 {
+				heap.Push(&r.open, n)
+			} else {
+				heap.Fix(&r.open, n.index)
+			}
+			if n.heuristic < r.best.heuristic || n.heuristic == r.best.heuristic && n.cost < r.best.cost {
+				r.best = n
+			}
+		}
+	}
+	return searched
+}
+
+type step struct {
+	node Node
+	cost float64
+}
+
+// horizontal are the directions mobs walk in, straight ones first.
+var horizontal = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// neighbors returns the nodes a mob walks, jumps or drops to from a node, and
+// what it costs. The mob's box has to fit everywhere it passes, so wide mobs
+// don't squeeze through gaps and closed doors block only the side they are on.
+func (p *Pathfinder) neighbors(n Node, size Size) []step {
+	var steps []step
+	here := size.box(n)
+	straight := make(map[[2]int]bool, 4)
+	for _, d := range horizontal {
+		dx, dz := float64(d[0]), float64(d[1])
+		next := Node{n.X + d[0], n.Y, n.Z + d[1]}
+		walk := here.Expand(dx, 0, dz)
+		if d[0] != 0 && d[1] != 0 {
+			// diagonals only along the ground, without cutting corners
+			if straight[[2]int{d[0], 0}] && straight[[2]int{0, d[1]}] && p.Standable(next, size) && p.fits(walk) {
+				steps = append(steps, step{next, math.Sqrt2})
+			}
+			continue
+		}
+
+		if p.Standable(next, size) && p.fits(walk) {
+			straight[d] = true
+			steps = append(steps, step{next, 1})
+			continue
+		}
+		up := Node{next.X, next.Y + 1, next.Z}
+		if p.Standable(up, size) && p.fits(here.Expand(0, 1, 0)) && p.fits(here.Offset(0, 1, 0).Expand(dx, 0, dz)) {
+			steps = append(steps, step{up, 1 + jumpCost})
+			continue
+		}
+		if !p.fits(walk) {
+			continue
+		}
+		for drop := 1; drop <= maxDrop; drop++ {
+			down := Node{next.X, next.Y - drop, next.Z}
+			if p.Standable(down, size) {
+				steps = append(steps, step{down, 1 + float64(drop)*dropCost})
+				break
+			}
+			if !p.fits(size.box(down)) {
+				break
+			}
+		}
+	}
+	return steps
+}
+
+// Standable reports whether a mob of a size can stand at a node: on a floor,
+// with room for its box, in a loaded chunk.
+func (p *Pathfinder) Standable(n Node, size Size) bool {
+	if !p.blocks.Loaded(n.X, n.Z) {
+		return false
+	}
+	box := size.box(n)
+	return p.fits(box) && p.floor(box)
+}
+
+// fits reports whether a box is clear of blocks mobs collide with or avoid.
+// Open doors are walked through, closed ones only at the side they are on.
+func (p *Pathfinder) fits(box block.Box) bool {
+	if len(p.blocks.CollisionBoxes(deflate(box))) > 0 {
+		return false
+	}
+	return !p.dangerous(box)
+}
+
+// floor reports whether the blocks under a box hold it up, without anything
+// harmful among them. Lower blocks like slabs count, blocks sticking up into
+// the box don't fit it in the first place.
+func (p *Pathfinder) floor(box block.Box) bool {
+	below := box
+	below.MinY -= 1 - collisionEpsilon
+	below.MaxY = box.MinY
+	if len(p.blocks.CollisionBoxes(below)) == 0 {
+		return false
+	}
+	return !p.dangerous(below)
+}
+
+// dangerous reports whether a box overlaps blocks paths go around.
+func (p *Pathfinder) dangerous(box block.Box) bool {
+	box = deflate(box)
+	for x := int(math.Floor(box.MinX)); x <= int(math.Floor(box.MaxX)); x++ {
+		for y := int(math.Floor(box.MinY)); y <= int(math.Floor(box.MaxY)); y++ {
+			for z := int(math.Floor(box.MinZ)); z <= int(math.Floor(box.MaxZ)); z++ {
+				s, ok := p.registry.State(p.blocks.Block(x, y, z))
+				if !ok || dangerousBlocks[s.Block.Name] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func deflate(b block.Box) block.Box {
+	return block.Box{
+		MinX: b.MinX + collisionEpsilon, MinY: b.MinY + collisionEpsilon, MinZ: b.MinZ + collisionEpsilon,
+		MaxX: b.MaxX - collisionEpsilon, MaxY: b.MaxY - collisionEpsilon, MaxZ: b.MaxZ - collisionEpsilon,
+	}
+}
+
+// pathNode is a node found by the search.
+type pathNode struct {
+	node      Node
+	cost      float64 // of the cheapest way found from the start
+	heuristic float64 // distance left to the destination
+	parent    *pathNode
+	closed    bool
+	index     int // in the open heap, -1 when not in it
+}
+
+// path returns the path leading to the node.
+func (n *pathNode) path(reached bool) *Path {
+	var nodes []Node
+	for ; n.parent != nil; n = n.parent {
+		nodes = append(nodes, n.node)
+	}
+	for i, j := 0, len(nodes)-1; i < j; i, j = i+1, j-1 {
+		nodes[i], nodes[j] = nodes[j], nodes[i]
+	}
+	return &Path{Nodes: nodes, Reached: reached}
+}
+
+// nodeHeap is the open set of a search, cheapest estimate first.
+type nodeHeap []*pathNode
+
+func (h nodeHeap) Len() int { return len(h) }
+
+func (h nodeHeap) Less(i, j int) bool {
+	return h[i].cost+h[i].heuristic < h[j].cost+h[j].heuristic
+}
+
+func (h nodeHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *nodeHeap) Push(x any) {
+	n := x.(*pathNode)
+	n.index = len(*h)
+	*h = append(*h, n)
+}
+
+func (h *nodeHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	n.index = -1
+	*h = old[:len(old)-1]
+	return n
+}
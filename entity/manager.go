@@ -0,0 +1,228 @@
+package entity
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/registry"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// ID identifies an entity to clients. Players and other entities share the
+// same IDs, all allocated by the Manager.
+type ID int32
+
+// Identity is what an entity is. Every entity has one.
+type Identity struct {
+	UUID uuid.UUID
+	// Type is the name of the entity type and TypeID its ID in the entity_type
+	// registry.
+	Type   string
+	TypeID int32
+	// Data is the type specific field of Spawn Entity, like the state of a
+	// falling block.
+	Data int32
+}
+
+// Position is where an entity is and where it looks, in degrees.
+type Position struct {
+	X, Y, Z             float64
+	Yaw, Pitch, HeadYaw float32
+	OnGround            bool
+}
+
+// Velocity is how many blocks an entity moves every tick.
+type Velocity struct {
+	X, Y, Z float64
+}
+
+// BoundingBox is the size of an entity, whose position is at the middle of
+// the bottom of its box.
+type BoundingBox struct {
+	Width, Height float64
+}
+
+// At returns the box of an entity at a position.
+func (b BoundingBox) At(x, y, z float64) block.Box {
+	return block.Box{
+		MinX: x - b.Width/2, MinY: y, MinZ: z - b.Width/2,
+		MaxX: x + b.Width/2, MaxY: y + b.Height, MaxZ: z + b.Width/2,
+	}
+}
+
+// AI decides what an entity does every tick.
+type AI interface {
+	Tick(m *Manager, id ID)
+}
+
+// System updates the entities with the components it works on, once a tick.
+type System func(m *Manager)
+
+// Manager holds the entities other than players, as components stored by
+// entity ID, and runs the systems updating them. It belongs to the game loop.
+type Manager struct {
+	nextID ID
+	ids    []ID // in the order they spawned
+
+	identities map[ID]*Identity
+	positions  map[ID]*Position
+	velocities map[ID]*Velocity
+	boxes      map[ID]*BoundingBox
+	metadata   map[ID]*Metadata
+	ais        map[ID]AI
+
+	systems   []System
+	onSpawn   []func(id ID)
+	onDespawn []func(id ID)
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		identities: make(map[ID]*Identity),
+		positions:  make(map[ID]*Position),
+		velocities: make(map[ID]*Velocity),
+		boxes:      make(map[ID]*BoundingBox),
+		metadata:   make(map[ID]*Metadata),
+		ais:        make(map[ID]AI),
+	}
+}
+
+// AllocateID returns an unused entity ID, for players and entities alike.
+func (m *Manager) AllocateID() ID {
+	m.nextID++
+	return m.nextID
+}
+
+// AddSystem adds a system, run after the ones added before it.
+func (m *Manager) AddSystem(s System) {
+	m.systems = append(m.systems, s)
+}
+
+// OnSpawn calls f with every entity spawned from now on.
+func (m *Manager) OnSpawn(f func(id ID)) {
+	m.onSpawn = append(m.onSpawn, f)
+}
+
+// OnDespawn calls f with every entity despawned from now on, before its
+// components are removed.
+func (m *Manager) OnDespawn(f func(id ID)) {
+	m.onDespawn = append(m.onDespawn, f)
+}
+
+// Spawn creates an entity of a type at a position, with the size and
+// metadata of its type and no velocity. Players can't be spawned, they join.
+func (m *Manager) Spawn(entityType string, pos Position) (ID, error) {
+	typeID, ok := registry.EntityTypes().ID(entityType)
+	if !ok || entityType == "minecraft:player" {
+		return 0, fmt.Errorf("unknown entity type %s", entityType)
+	}
+
+	info := TypeOf(entityType)
+	id := m.AllocateID()
+	m.ids = append(m.ids, id)
+	m.identities[id] = &Identity{UUID: uuid.New(), Type: entityType, TypeID: typeID}
+	m.positions[id] = &pos
+	m.velocities[id] = &Velocity{}
+	m.boxes[id] = &BoundingBox{Width: info.Width, Height: info.Height}
+	m.metadata[id] = NewMetadata(SchemaOf(entityType))
+
+	for _, f := range m.onSpawn {
+		f(id)
+	}
+	return id, nil
+}
+
+// Despawn removes an entity and its components.
+func (m *Manager) Despawn(id ID) {
+	i := slices.Index(m.ids, id)
+	if i < 0 {
+		return
+	}
+	for _, f := range m.onDespawn {
+		f(id)
+	}
+
+	m.ids = slices.Delete(m.ids, i, i+1)
+	delete(m.identities, id)
+	delete(m.positions, id)
+	delete(m.velocities, id)
+	delete(m.boxes, id)
+	delete(m.metadata, id)
+	delete(m.ais, id)
+}
+
+// Tick runs the systems.
+func (m *Manager) Tick() {
+	for _, s := range m.systems {
+		s(m)
+	}
+}
+
+// Entities returns the entities in the order they spawned. Systems may spawn
+// and despawn entities while going through them.
+func (m *Manager) Entities() []ID {
+	return slices.Clone(m.ids)
+}
+
+// Len returns how many entities there are.
+func (m *Manager) Len() int {
+	return len(m.ids)
+}
+
+// Exists reports whether an entity hasn't despawned.
+func (m *Manager) Exists(id ID) bool {
+	_, ok := m.identities[id]
+	return ok
+}
+
+func (m *Manager) Identity(id ID) (*Identity, bool) {
+	c, ok := m.identities[id]
+	return c, ok
+}
+
+func (m *Manager) Position(id ID) (*Position, bool) {
+	c, ok := m.positions[id]
+	return c, ok
+}
+
+func (m *Manager) Velocity(id ID) (*Velocity, bool) {
+	c, ok := m.velocities[id]
+	return c, ok
+}
+
+func (m *Manager) BoundingBox(id ID) (*BoundingBox, bool) {
+	c, ok := m.boxes[id]
+	return c, ok
+}
+
+func (m *Manager) Metadata(id ID) (*Metadata, bool) {
+	c, ok := m.metadata[id]
+	return c, ok
+}
+
+func (m *Manager) AI(id ID) (AI, bool) {
+	c, ok := m.ais[id]
+	return c, ok
+}
+
+// SetAI gives an entity an AI, or takes it away with nil.
+func (m *Manager) SetAI(id ID, ai AI) {
+	if !m.Exists(id) {
+		return
+	}
+	if ai == nil {
+		delete(m.ais, id)
+		return
+	}
+	m.ais[id] = ai
+}
+
+// RunAI is the system ticking the AI of entities.
+func RunAI(m *Manager) {
+	for _, id := range m.Entities() {
+		if ai, ok := m.ais[id]; ok {
+			ai.Tick(m, id)
+		}
+	}
+}
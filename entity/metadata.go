@@ -0,0 +1,412 @@
+// Package entity holds the entities of the world as components updated by
+// systems, and the metadata clients are kept in sync with.
+package entity
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/text"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// Serializer identifies the type of a metadata value on the wire.
+//
+// https://wiki.vg/Entity_metadata#Entity_Metadata_Format
+type Serializer int32
+
+// Serializers and the Go types their values are held in
+const (
+	SerializerByte                  Serializer = 0  // int8
+	SerializerVarInt                Serializer = 1  // int32
+	SerializerVarLong               Serializer = 2  // int64
+	SerializerFloat                 Serializer = 3  // float32
+	SerializerString                Serializer = 4  // string
+	SerializerTextComponent         Serializer = 5  // text.TextComponent
+	SerializerOptionalTextComponent Serializer = 6  // *text.TextComponent
+	SerializerSlot                  Serializer = 7  // Slot
+	SerializerBoolean               Serializer = 8  // bool
+	SerializerRotations             Serializer = 9  // Rotations
+	SerializerPosition              Serializer = 10 // BlockPos
+	SerializerOptionalPosition      Serializer = 11 // *BlockPos
+	SerializerDirection             Serializer = 12 // Direction
+	SerializerOptionalUUID          Serializer = 13 // *uuid.UUID
+	SerializerBlockState            Serializer = 14 // block.StateID
+	SerializerOptionalBlockState    Serializer = 15 // block.StateID, air for none
+	SerializerNBT                   Serializer = 16 // nbt.Compound
+	SerializerParticle              Serializer = 17 // Particle
+	SerializerParticles             Serializer = 18 // []Particle
+	SerializerVillagerData          Serializer = 19 // VillagerData
+	SerializerOptionalVarInt        Serializer = 20 // *int32
+	SerializerPose                  Serializer = 21 // Pose
+	SerializerCatVariant            Serializer = 22 // int32
+	SerializerWolfVariant           Serializer = 23 // int32
+	SerializerFrogVariant           Serializer = 24 // int32
+	SerializerOptionalGlobalPos     Serializer = 25 // *GlobalPos
+	SerializerPaintingVariant       Serializer = 26 // int32
+	SerializerSnifferState          Serializer = 27 // int32
+	SerializerArmadilloState        Serializer = 28 // int32
+	SerializerVector3               Serializer = 29 // Vector3
+	SerializerQuaternion            Serializer = 30 // Quaternion
+)
+
+// goTypes are the types values of each serializer must have.
+var goTypes = map[Serializer]reflect.Type{
+	SerializerByte:                  reflect.TypeFor[int8](),
+	SerializerVarInt:                reflect.TypeFor[int32](),
+	SerializerVarLong:               reflect.TypeFor[int64](),
+	SerializerFloat:                 reflect.TypeFor[float32](),
+	SerializerString:                reflect.TypeFor[string](),
+	SerializerTextComponent:         reflect.TypeFor[text.TextComponent](),
+	SerializerOptionalTextComponent: reflect.TypeFor[*text.TextComponent](),
+	SerializerSlot:                  reflect.TypeFor[Slot](),
+	SerializerBoolean:               reflect.TypeFor[bool](),
+	SerializerRotations:             reflect.TypeFor[Rotations](),
+	SerializerPosition:              reflect.TypeFor[BlockPos](),
+	SerializerOptionalPosition:      reflect.TypeFor[*BlockPos](),
+	SerializerDirection:             reflect.TypeFor[Direction](),
+	SerializerOptionalUUID:          reflect.TypeFor[*uuid.UUID](),
+	SerializerBlockState:            reflect.TypeFor[block.StateID](),
+	SerializerOptionalBlockState:    reflect.TypeFor[block.StateID](),
+	SerializerNBT:                   reflect.TypeFor[nbt.Compound](),
+	SerializerParticle:              reflect.TypeFor[Particle](),
+	SerializerParticles:             reflect.TypeFor[[]Particle](),
+	SerializerVillagerData:          reflect.TypeFor[VillagerData](),
+	SerializerOptionalVarInt:        reflect.TypeFor[*int32](),
+	SerializerPose:                  reflect.TypeFor[Pose](),
+	SerializerCatVariant:            reflect.TypeFor[int32](),
+	SerializerWolfVariant:           reflect.TypeFor[int32](),
+	SerializerFrogVariant:           reflect.TypeFor[int32](),
+	SerializerOptionalGlobalPos:     reflect.TypeFor[*GlobalPos](),
+	SerializerPaintingVariant:       reflect.TypeFor[int32](),
+	SerializerSnifferState:          reflect.TypeFor[int32](),
+	SerializerArmadilloState:        reflect.TypeFor[int32](),
+	SerializerVector3:               reflect.TypeFor[Vector3](),
+	SerializerQuaternion:            reflect.TypeFor[Quaternion](),
+}
+
+// Slot is an item stack held in metadata, like the item of a dropped item.
+// A nil Slot is empty.
+type Slot interface {
+	WriteSlot(w *io.Writer)
+}
+
+// Rotations are the rotations of an armor stand's body parts, in degrees.
+type Rotations struct {
+	X, Y, Z float32
+}
+
+type BlockPos struct {
+	X, Y, Z int32
+}
+
+// GlobalPos is a block position in a dimension.
+type GlobalPos struct {
+	Dimension string
+	Pos       BlockPos
+}
+
+type Vector3 struct {
+	X, Y, Z float32
+}
+
+type Quaternion struct {
+	X, Y, Z, W float32
+}
+
+// Particle is a particle type without options, like the ones of potion
+// effects.
+type Particle struct {
+	Type int32
+}
+
+type VillagerData struct {
+	Type       int32
+	Profession int32
+	Level      int32
+}
+
+// Direction is a block face.
+type Direction int32
+
+const (
+	Down Direction = iota
+	Up
+	North
+	South
+	West
+	East
+)
+
+// Pose is how an entity's model is posed, which also gives it its size.
+type Pose int32
+
+const (
+	PoseStanding Pose = iota
+	PoseFallFlying
+	PoseSleeping
+	PoseSwimming
+	PoseSpinAttack
+	PoseSneaking
+	PoseLongJumping
+	PoseDying
+	PoseCroaking
+	PoseUsingTongue
+	PoseSitting
+	PoseRoaring
+	PoseSniffing
+	PoseEmerging
+	PoseDigging
+	PoseSliding
+	PoseShooting
+	PoseInhaling
+)
+
+// Field is an entry of metadata: where it is, how it is sent and the value
+// entities start with. Fields are compared by identity, so fields of different
+// entity types at the same index aren't mixed up.
+type Field struct {
+	Index      uint8
+	Serializer Serializer
+	Default    any
+}
+
+// Entry is the value of a field, as sent in Set Entity Metadata.
+type Entry struct {
+	Index      uint8
+	Serializer Serializer
+	Value      any
+}
+
+// Metadata is the metadata of an entity, remembering which fields changed
+// since they were last sent.
+type Metadata struct {
+	schema Schema
+	values []any
+	dirty  []bool
+	// whether any field is dirty
+	changed bool
+}
+
+// NewMetadata creates metadata with the default values of a schema.
+func NewMetadata(schema Schema) *Metadata {
+	m := &Metadata{schema: schema}
+	for _, f := range schema {
+		for len(m.values) <= int(f.Index) {
+			m.values = append(m.values, nil)
+			m.dirty = append(m.dirty, false)
+		}
+		m.values[f.Index] = f.Default
+	}
+	return m
+}
+
+// Schema returns the fields the metadata has.
+func (m *Metadata) Schema() Schema {
+	return m.schema
+}
+
+// field checks that a field is in the schema.
+func (m *Metadata) field(f *Field) *Field {
+	if !m.schema.Has(f) {
+		panic(fmt.Sprintf("entity: field %d isn't in the schema", f.Index))
+	}
+	return f
+}
+
+// Get returns the value of a field. The field must be in the schema.
+func (m *Metadata) Get(f *Field) any {
+	return m.values[m.field(f).Index]
+}
+
+// Set changes the value of a field, marking it to be sent if it differs. The
+// field must be in the schema and the value of its serializer's type.
+func (m *Metadata) Set(f *Field, value any) {
+	f = m.field(f)
+	value, ok := convert(goTypes[f.Serializer], value)
+	if !ok {
+		panic(fmt.Sprintf("entity: field %d can't hold a %T", f.Index, value))
+	}
+	if reflect.DeepEqual(m.values[f.Index], value) {
+		return
+	}
+	m.values[f.Index] = value
+	m.dirty[f.Index] = true
+	m.changed = true
+}
+
+// convert checks that a value has a type, turning an untyped nil into the
+// nil of pointer types.
+func convert(t reflect.Type, value any) (any, bool) {
+	switch {
+	case value == nil:
+		if t.Kind() == reflect.Pointer {
+			return reflect.Zero(t).Interface(), true
+		}
+		return nil, t.Kind() == reflect.Interface
+	case t.Kind() == reflect.Interface:
+		return value, reflect.TypeOf(value).Implements(t)
+	}
+	return value, reflect.TypeOf(value) == t
+}
+
+// Flag reports whether a bit of a byte field is set.
+func (m *Metadata) Flag(f *Field, bit int8) bool {
+	return m.Get(f).(int8)&bit != 0
+}
+
+// SetFlag sets or clears a bit of a byte field.
+func (m *Metadata) SetFlag(f *Field, bit int8, on bool) {
+	flags := m.Get(f).(int8)
+	if on {
+		flags |= bit
+	} else {
+		flags &^= bit
+	}
+	m.Set(f, flags)
+}
+
+// Dirty reports whether fields changed since Changes was last called.
+func (m *Metadata) Dirty() bool {
+	return m.changed
+}
+
+// Changes returns the fields that changed since it was last called.
+func (m *Metadata) Changes() []Entry {
+	if !m.changed {
+		return nil
+	}
+	var entries []Entry
+	for _, f := range m.schema {
+		if m.dirty[f.Index] {
+			entries = append(entries, Entry{f.Index, f.Serializer, m.values[f.Index]})
+			m.dirty[f.Index] = false
+		}
+	}
+	m.changed = false
+	return entries
+}
+
+// NonDefault returns the fields that don't have their default value, which is
+// what players seeing the entity for the first time need.
+func (m *Metadata) NonDefault() []Entry {
+	var entries []Entry
+	for _, f := range m.schema {
+		if !reflect.DeepEqual(m.values[f.Index], f.Default) {
+			entries = append(entries, Entry{f.Index, f.Serializer, m.values[f.Index]})
+		}
+	}
+	return entries
+}
+
+// metadataEnd terminates the entries of Set Entity Metadata.
+const metadataEnd = 0xFF
+
+// WriteMetadata writes entries in the format of Set Entity Metadata.
+//
+// https://wiki.vg/Entity_metadata#Entity_Metadata_Format
+func WriteMetadata(w *io.Writer, entries []Entry) {
+	for _, e := range entries {
+		w.WriteUbyte(e.Index)
+		w.WriteVarInt(int32(e.Serializer))
+		writeValue(w, e.Serializer, e.Value)
+	}
+	w.WriteUbyte(metadataEnd)
+}
+
+func writeValue(w *io.Writer, s Serializer, value any) {
+	switch s {
+	case SerializerByte:
+		w.WriteByteInt8(value.(int8))
+	case SerializerVarInt, SerializerCatVariant, SerializerWolfVariant, SerializerFrogVariant,
+		SerializerSnifferState, SerializerArmadilloState:
+		w.WriteVarInt(value.(int32))
+	case SerializerPaintingVariant:
+		// 0 is reserved for variants sent inline
+		w.WriteVarInt(value.(int32) + 1)
+	case SerializerVarLong:
+		w.WriteVarLong(value.(int64))
+	case SerializerFloat:
+		w.WriteFloat(value.(float32))
+	case SerializerString:
+		w.WriteString(value.(string))
+	case SerializerTextComponent:
+		w.WriteTextComponent(value.(text.TextComponent))
+	case SerializerOptionalTextComponent:
+		comp := value.(*text.TextComponent)
+		w.WriteBool(comp != nil)
+		if comp != nil {
+			w.WriteTextComponent(*comp)
+		}
+	case SerializerSlot:
+		if value == nil {
+			// an empty slot has no items
+			w.WriteVarInt(0)
+		} else {
+			value.(Slot).WriteSlot(w)
+		}
+	case SerializerBoolean:
+		w.WriteBool(value.(bool))
+	case SerializerRotations:
+		r := value.(Rotations)
+		w.WriteFloat(r.X).WriteFloat(r.Y).WriteFloat(r.Z)
+	case SerializerPosition:
+		pos := value.(BlockPos)
+		w.WritePosition(pos.X, pos.Y, pos.Z)
+	case SerializerOptionalPosition:
+		pos := value.(*BlockPos)
+		w.WriteBool(pos != nil)
+		if pos != nil {
+			w.WritePosition(pos.X, pos.Y, pos.Z)
+		}
+	case SerializerDirection:
+		w.WriteVarInt(int32(value.(Direction)))
+	case SerializerOptionalUUID:
+		id := value.(*uuid.UUID)
+		w.WriteBool(id != nil)
+		if id != nil {
+			w.WriteUUID(*id)
+		}
+	case SerializerBlockState, SerializerOptionalBlockState:
+		w.WriteVarInt(int32(value.(block.StateID)))
+	case SerializerNBT:
+		w.WriteNBT(value.(nbt.Compound))
+	case SerializerParticle:
+		w.WriteVarInt(value.(Particle).Type)
+	case SerializerParticles:
+		particles := value.([]Particle)
+		w.WriteVarInt(int32(len(particles)))
+		for _, p := range particles {
+			w.WriteVarInt(p.Type)
+		}
+	case SerializerVillagerData:
+		v := value.(VillagerData)
+		w.WriteVarInt(v.Type).WriteVarInt(v.Profession).WriteVarInt(v.Level)
+	case SerializerOptionalVarInt:
+		// 0 is absent, other values are shifted by one
+		v := value.(*int32)
+		if v == nil {
+			w.WriteVarInt(0)
+		} else {
+			w.WriteVarInt(*v + 1)
+		}
+	case SerializerPose:
+		w.WriteVarInt(int32(value.(Pose)))
+	case SerializerOptionalGlobalPos:
+		pos := value.(*GlobalPos)
+		w.WriteBool(pos != nil)
+		if pos != nil {
+			w.WriteIdentifier(pos.Dimension)
+			w.WritePosition(pos.Pos.X, pos.Pos.Y, pos.Pos.Z)
+		}
+	case SerializerVector3:
+		v := value.(Vector3)
+		w.WriteFloat(v.X).WriteFloat(v.Y).WriteFloat(v.Z)
+	case SerializerQuaternion:
+		q := value.(Quaternion)
+		w.WriteFloat(q.X).WriteFloat(q.Y).WriteFloat(q.Z).WriteFloat(q.W)
+	}
+}
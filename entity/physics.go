@@ -0,0 +1,151 @@
+package entity
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// Blocks is the world physics moves entities through.
+type Blocks interface {
+	// Loaded reports whether the chunk containing a block column is loaded.
+	Loaded(x, z int) bool
+	Block(x, y, z int) block.StateID
+	// CollisionBoxes returns the collision shapes of the blocks overlapping a
+	// box.
+	CollisionBoxes(box block.Box) []block.Box
+}
+
+// Physics constants shared by every entity type, as in vanilla
+const (
+	// airFriction is how much of their horizontal speed living entities keep
+	// every tick, multiplied by the slipperiness of the ground when on it.
+	airFriction = 0.91
+	// minVelocity is the speed below which entities stop moving on an axis.
+	minVelocity = 0.003
+)
+
+// Physics returns the system moving entities by their velocity through the
+// blocks, pulling them down and slowing them down the way vanilla does for
+// their type. Entities in chunks that aren't loaded are left as they are, and
+// entities falling below voidY despawn.
+func Physics(blocks Blocks, voidY float64) System {
+	registry := block.Default()
+	return func(m *Manager) {
+		for _, id := range m.Entities() {
+			identity, ok := m.Identity(id)
+			if !ok {
+				continue
+			}
+			info := TypeOf(identity.Type)
+			pos, _ := m.Position(id)
+			if info.Motion == MotionNone || !blocks.Loaded(int(math.Floor(pos.X)), int(math.Floor(pos.Z))) {
+				continue
+			}
+			if md, _ := m.Metadata(id); md.Get(NoGravity).(bool) {
+				info.Gravity = 0
+			}
+			v, _ := m.Velocity(id)
+			box, _ := m.BoundingBox(id)
+
+			switch info.Motion {
+			case MotionLiving:
+				move(blocks, pos, v, *box, info.StepHeight)
+				friction := airFriction
+				if pos.OnGround {
+					friction *= registry.Slipperiness(groundBelow(blocks, pos))
+				}
+				v.Y = (v.Y - info.Gravity) * info.Drag
+				v.X *= friction
+				v.Z *= friction
+			case MotionFalling:
+				v.Y -= info.Gravity
+				move(blocks, pos, v, *box, info.StepHeight)
+				friction := info.Drag
+				if pos.OnGround {
+					friction *= registry.Slipperiness(groundBelow(blocks, pos))
+				}
+				v.X *= friction
+				v.Y *= info.Drag
+				v.Z *= friction
+			case MotionProjectile:
+				move(blocks, pos, v, *box, 0)
+				v.X *= info.Drag
+				v.Y = v.Y*info.Drag - info.Gravity
+				v.Z *= info.Drag
+			}
+			settle(v)
+
+			if pos.Y < voidY {
+				m.Despawn(id)
+			}
+		}
+	}
+}
+
+// move moves an entity by its velocity, stopping at blocks in the way and
+// stepping up ones no higher than stepHeight when on the ground. The velocity
+// is stopped on the axes the entity hit something.
+func move(blocks Blocks, pos *Position, v *Velocity, size BoundingBox, stepHeight float64) {
+	if v.X == 0 && v.Y == 0 && v.Z == 0 {
+		return
+	}
+	box := size.At(pos.X, pos.Y, pos.Z)
+	obstacles := blocks.CollisionBoxes(box.Expand(v.X, v.Y, v.Z))
+	dx, dy, dz := block.Sweep(box, obstacles, v.X, v.Y, v.Z)
+
+	landed := v.Y < 0 && dy != v.Y
+	blocked := dx != v.X || dz != v.Z
+	if stepHeight > 0 && blocked && (pos.OnGround || landed) {
+		if sx, sy, sz, ok := step(blocks, box, v.X, v.Z, stepHeight); ok && sx*sx+sz*sz > dx*dx+dz*dz {
+			dx, dy, dz = sx, sy, sz
+			landed = true
+		}
+	}
+
+	pos.X += dx
+	pos.Y += dy
+	pos.Z += dz
+	pos.OnGround = landed
+	if dx != v.X {
+		v.X = 0
+	}
+	if dy != v.Y {
+		v.Y = 0
+	}
+	if dz != v.Z {
+		v.Z = 0
+	}
+}
+
+// step tries moving a box horizontally after lifting it by up to stepHeight,
+// then lowers it back onto whatever it stepped on.
+func step(blocks Blocks, box block.Box, dx, dz, stepHeight float64) (float64, float64, float64, bool) {
+	obstacles := blocks.CollisionBoxes(box.Expand(dx, stepHeight, dz))
+	_, up, _ := block.Sweep(box, obstacles, 0, stepHeight, 0)
+	if up <= 0 {
+		return 0, 0, 0, false
+	}
+	lifted := box.Offset(0, up, 0)
+	dx, _, dz = block.Sweep(lifted, obstacles, dx, 0, dz)
+	_, down, _ := block.Sweep(lifted.Offset(dx, 0, dz), obstacles, 0, -up, 0)
+	return dx, up + down, dz, true
+}
+
+// groundBelow returns the block an entity stands on.
+func groundBelow(blocks Blocks, pos *Position) block.StateID {
+	return blocks.Block(int(math.Floor(pos.X)), int(math.Floor(pos.Y-0.5)), int(math.Floor(pos.Z)))
+}
+
+// settle stops the velocity on the axes it became too small on.
+func settle(v *Velocity) {
+	if math.Abs(v.X) < minVelocity {
+		v.X = 0
+	}
+	if math.Abs(v.Y) < minVelocity {
+		v.Y = 0
+	}
+	if math.Abs(v.Z) < minVelocity {
+		v.Z = 0
+	}
+}
@@ -0,0 +1,165 @@
+package entity
+
+import (
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// Schema is the metadata fields of an entity type, in the order of their
+// indices. Entity types inherit the fields of the types they extend.
+type Schema []*Field
+
+// Has reports whether a field is part of the schema.
+func (s Schema) Has(f *Field) bool {
+	for _, sf := range s {
+		if sf == f {
+			return true
+		}
+	}
+	return false
+}
+
+func extend(parent Schema, fields ...*Field) Schema {
+	return append(append(Schema{}, parent...), fields...)
+}
+
+// Fields of all entities
+//
+// https://wiki.vg/Entity_metadata#Entity
+var (
+	Flags             = &Field{0, SerializerByte, int8(0)}
+	AirTicks          = &Field{1, SerializerVarInt, int32(300)}
+	CustomName        = &Field{2, SerializerOptionalTextComponent, (*text.TextComponent)(nil)}
+	CustomNameVisible = &Field{3, SerializerBoolean, false}
+	Silent            = &Field{4, SerializerBoolean, false}
+	NoGravity         = &Field{5, SerializerBoolean, false}
+	EntityPose        = &Field{6, SerializerPose, PoseStanding}
+	TicksFrozen       = &Field{7, SerializerVarInt, int32(0)}
+)
+
+// Bits of Flags
+const (
+	FlagOnFire    int8 = 0x01
+	FlagSneaking  int8 = 0x02
+	FlagSprinting int8 = 0x08
+	FlagSwimming  int8 = 0x10
+	FlagInvisible int8 = 0x20
+	FlagGlowing   int8 = 0x40
+	// 0x80 as a signed byte
+	FlagFallFlying int8 = -0x80
+)
+
+// Fields of living entities
+//
+// https://wiki.vg/Entity_metadata#Living_Entity
+var (
+	HandStates        = &Field{8, SerializerByte, int8(0)}
+	Health            = &Field{9, SerializerFloat, float32(1)}
+	EffectParticles   = &Field{10, SerializerParticles, []Particle(nil)}
+	EffectAmbient     = &Field{11, SerializerBoolean, false}
+	ArrowsInBody      = &Field{12, SerializerVarInt, int32(0)}
+	BeeStingersInBody = &Field{13, SerializerVarInt, int32(0)}
+	SleepingPosition  = &Field{14, SerializerOptionalPosition, (*BlockPos)(nil)}
+)
+
+// Fields of players
+//
+// https://wiki.vg/Entity_metadata#Player
+var (
+	AdditionalHearts   = &Field{15, SerializerFloat, float32(0)}
+	Score              = &Field{16, SerializerVarInt, int32(0)}
+	DisplayedSkinParts = &Field{17, SerializerByte, int8(0)}
+	MainHand           = &Field{18, SerializerByte, int8(1)}
+	LeftShoulder       = &Field{19, SerializerNBT, nbt.Compound{}}
+	RightShoulder      = &Field{20, SerializerNBT, nbt.Compound{}}
+)
+
+// Fields of mobs
+//
+// https://wiki.vg/Entity_metadata#Mob
+var (
+	MobFlags = &Field{15, SerializerByte, int8(0)}
+	// of ageable mobs, like animals
+	Baby = &Field{16, SerializerBoolean, false}
+)
+
+// Bits of MobFlags
+const (
+	MobFlagNoAI       int8 = 0x01
+	MobFlagLeftHanded int8 = 0x02
+	MobFlagAggressive int8 = 0x04
+)
+
+// Fields of other entity types
+var (
+	// https://wiki.vg/Entity_metadata#Item_Entity
+	Item = &Field{8, SerializerSlot, Slot(nil)}
+	// https://wiki.vg/Entity_metadata#Falling_Block
+	FallingBlockOrigin = &Field{8, SerializerPosition, BlockPos{}}
+
+	// https://wiki.vg/Entity_metadata#Armor_Stand
+	ArmorStandFlags    = &Field{15, SerializerByte, int8(0)}
+	ArmorStandHead     = &Field{16, SerializerRotations, Rotations{}}
+	ArmorStandBody     = &Field{17, SerializerRotations, Rotations{}}
+	ArmorStandLeftArm  = &Field{18, SerializerRotations, Rotations{-10, 0, -10}}
+	ArmorStandRightArm = &Field{19, SerializerRotations, Rotations{-15, 0, 10}}
+	ArmorStandLeftLeg  = &Field{20, SerializerRotations, Rotations{-1, 0, -1}}
+	ArmorStandRightLeg = &Field{21, SerializerRotations, Rotations{1, 0, 1}}
+
+	// https://wiki.vg/Entity_metadata#Pig
+	PigSaddled   = &Field{17, SerializerBoolean, false}
+	PigBoostTime = &Field{18, SerializerVarInt, int32(0)}
+	// https://wiki.vg/Entity_metadata#Sheep
+	SheepWool = &Field{17, SerializerByte, int8(0)}
+
+	// https://wiki.vg/Entity_metadata#Zombie
+	ZombieBaby              = &Field{16, SerializerBoolean, false}
+	ZombieType              = &Field{17, SerializerVarInt, int32(0)}
+	ZombieConvertingDrowned = &Field{18, SerializerBoolean, false}
+	// https://wiki.vg/Entity_metadata#Creeper
+	CreeperState   = &Field{16, SerializerVarInt, int32(-1)}
+	CreeperCharged = &Field{17, SerializerBoolean, false}
+	CreeperIgnited = &Field{18, SerializerBoolean, false}
+	// https://wiki.vg/Entity_metadata#Skeleton
+	SkeletonConvertingStray = &Field{16, SerializerBoolean, false}
+	// https://wiki.vg/Entity_metadata#Spider
+	SpiderClimbing = &Field{16, SerializerByte, int8(0)}
+)
+
+// Schemas of entity types and the types they extend
+var (
+	EntitySchema     = Schema{Flags, AirTicks, CustomName, CustomNameVisible, Silent, NoGravity, EntityPose, TicksFrozen}
+	LivingSchema     = extend(EntitySchema, HandStates, Health, EffectParticles, EffectAmbient, ArrowsInBody, BeeStingersInBody, SleepingPosition)
+	PlayerSchema     = extend(LivingSchema, AdditionalHearts, Score, DisplayedSkinParts, MainHand, LeftShoulder, RightShoulder)
+	MobSchema        = extend(LivingSchema, MobFlags)
+	AgeableSchema    = extend(MobSchema, Baby)
+	ItemSchema       = extend(EntitySchema, Item)
+	ArmorStandSchema = extend(LivingSchema, ArmorStandFlags, ArmorStandHead, ArmorStandBody,
+		ArmorStandLeftArm, ArmorStandRightArm, ArmorStandLeftLeg, ArmorStandRightLeg)
+)
+
+// schemas are the schemas of entity types with fields of their own, by name
+// in the entity_type registry.
+var schemas = map[string]Schema{
+	"minecraft:player":        PlayerSchema,
+	"minecraft:item":          ItemSchema,
+	"minecraft:falling_block": extend(EntitySchema, FallingBlockOrigin),
+	"minecraft:armor_stand":   ArmorStandSchema,
+	"minecraft:pig":           extend(AgeableSchema, PigSaddled, PigBoostTime),
+	"minecraft:sheep":         extend(AgeableSchema, SheepWool),
+	"minecraft:cow":           AgeableSchema,
+	"minecraft:chicken":       AgeableSchema,
+	"minecraft:zombie":        extend(MobSchema, ZombieBaby, ZombieType, ZombieConvertingDrowned),
+	"minecraft:creeper":       extend(MobSchema, CreeperState, CreeperCharged, CreeperIgnited),
+	"minecraft:skeleton":      extend(MobSchema, SkeletonConvertingStray),
+	"minecraft:spider":        extend(MobSchema, SpiderClimbing),
+}
+
+// SchemaOf returns the schema of an entity type. Types without one of their
+// own only get the fields all entities have.
+func SchemaOf(entityType string) Schema {
+	if s, ok := schemas[entityType]; ok {
+		return s
+	}
+	return EntitySchema
+}
@@ -0,0 +1,90 @@
+package entity
+
+// Motion is how an entity type moves, following one of vanilla's orders of
+// applying gravity, drag and collisions.
+type Motion int
+
+const (
+	// MotionNone is for entities that don't move on their own, like markers.
+	MotionNone Motion = iota
+	// MotionLiving moves before gravity pulls, with friction from the ground.
+	MotionLiving
+	// MotionFalling is for items and blocks, which gravity pulls before they
+	// move.
+	MotionFalling
+	// MotionProjectile slows down in the air before gravity pulls.
+	MotionProjectile
+)
+
+// TypeInfo is what is built into an entity type: its size, how it moves and
+// how it is tracked.
+type TypeInfo struct {
+	Width, Height float64
+	// TrackingRange is how many chunks away players see entities of the type,
+	// as vanilla's client tracking range.
+	TrackingRange int
+
+	Motion Motion
+	// Gravity is how much falling speed is gained every tick, in blocks per
+	// tick, and Drag how much of their speed entities keep every tick.
+	Gravity, Drag float64
+	// StepHeight is how high entities walk up without jumping.
+	StepHeight float64
+	// MovementSpeed is how fast living entities walk on their own, as the
+	// movement_speed attribute.
+	MovementSpeed float64
+	// TrackVelocity is whether clients are sent the velocity, to predict the
+	// motion of entities whose path is easy to tell.
+	TrackVelocity bool
+}
+
+// Motion of common kinds of entities
+var (
+	living     = TypeInfo{Motion: MotionLiving, Gravity: 0.08, Drag: 0.98, StepHeight: 0.6}
+	falling    = TypeInfo{Motion: MotionFalling, Gravity: 0.04, Drag: 0.98, TrackVelocity: true}
+	projectile = TypeInfo{Motion: MotionProjectile, Gravity: 0.03, Drag: 0.99, TrackVelocity: true}
+)
+
+// sized returns type info with the size and tracking range of a type.
+func sized(info TypeInfo, width, height float64, trackingRange int) TypeInfo {
+	info.Width, info.Height, info.TrackingRange = width, height, trackingRange
+	return info
+}
+
+// walking returns type info with the movement speed of a mob.
+func walking(info TypeInfo, speed float64) TypeInfo {
+	info.MovementSpeed = speed
+	return info
+}
+
+// defaultType is vanilla's defaults for entity types.
+var defaultType = sized(TypeInfo{}, 0.6, 1.8, 5)
+
+var types = map[string]TypeInfo{
+	"minecraft:player":         sized(living, 0.6, 1.8, 32),
+	"minecraft:item":           sized(falling, 0.25, 0.25, 6),
+	"minecraft:experience_orb": sized(TypeInfo{Motion: MotionFalling, Gravity: 0.03, Drag: 0.98, TrackVelocity: true}, 0.5, 0.5, 6),
+	"minecraft:falling_block":  sized(falling, 0.98, 0.98, 10),
+	"minecraft:tnt":            sized(falling, 0.98, 0.98, 10),
+	"minecraft:armor_stand":    sized(TypeInfo{Motion: MotionLiving, Gravity: 0.08, Drag: 0.98}, 0.5, 1.975, 10),
+	"minecraft:arrow":          sized(TypeInfo{Motion: MotionProjectile, Gravity: 0.05, Drag: 0.99, TrackVelocity: true}, 0.5, 0.5, 4),
+	"minecraft:snowball":       sized(projectile, 0.25, 0.25, 4),
+	"minecraft:egg":            sized(projectile, 0.25, 0.25, 4),
+	"minecraft:pig":            walking(sized(living, 0.9, 0.9, 10), 0.25),
+	"minecraft:sheep":          walking(sized(living, 0.9, 1.3, 10), 0.23),
+	"minecraft:cow":            walking(sized(living, 0.9, 1.4, 10), 0.2),
+	"minecraft:chicken":        walking(sized(living, 0.4, 0.7, 10), 0.25),
+	"minecraft:zombie":         walking(sized(living, 0.6, 1.95, 8), 0.23),
+	"minecraft:creeper":        walking(sized(living, 0.6, 1.7, 8), 0.25),
+	"minecraft:skeleton":       walking(sized(living, 0.6, 1.99, 8), 0.25),
+	"minecraft:spider":         walking(sized(living, 1.4, 0.9, 8), 0.3),
+}
+
+// TypeOf returns what is built into an entity type, by name in the
+// entity_type registry.
+func TypeOf(entityType string) TypeInfo {
+	if t, ok := types[entityType]; ok {
+		return t
+	}
+	return defaultType
+}
@@ -0,0 +1,473 @@
+package inventory
+
+import (
+	"slices"
+
+	"github.com/hunterros-s/algernon/item"
+)
+
+// Modes of clicks
+//
+// https://wiki.vg/Protocol#Click_Container
+const (
+	ModePickup = iota
+	ModeQuickMove
+	ModeSwap
+	ModeClone
+	ModeThrow
+	ModeDrag
+	ModePickupAll
+)
+
+// OutsideSlot is the slot clicked outside the window, to throw what the cursor
+// carries.
+const OutsideSlot = -999
+
+// Click is a click of a player in a window.
+type Click struct {
+	StateID int32
+	Slot    int
+	Button  int8
+	Mode    int32
+
+	// Changed are the slots the client changed predicting what the click
+	// does, and Carried what it predicts the cursor carries after it.
+	Changed map[int]*item.ItemStack
+	Carried *item.ItemStack
+}
+
+// drag is the state of a drag of the carried stack over slots, to spread it
+// evenly, one in each or, in creative, a full stack in each.
+type drag struct {
+	active bool
+	kind   int8
+	slots  []int
+}
+
+// Kinds of drags
+const (
+	dragEvenly = iota
+	dragOne
+	dragClone
+)
+
+// Click does what a click of a player in the window does in vanilla, returning
+// the stacks it throws out. Clicks by creative players may clone stacks.
+//
+// What the client predicted is taken as what it has, so Changes sends back
+// what it got wrong; when the client hadn't seen the latest state, the window
+// is resynced.
+func (w *Window) Click(c Click, creative bool) (thrown []*item.ItemStack) {
+	w.updateResult()
+	if c.Mode != ModeDrag && w.drag.active {
+		w.drag = drag{}
+	}
+	switch c.Mode {
+	case ModePickup:
+		if c.Button == 0 || c.Button == 1 {
+			thrown = w.pickup(c.Slot, c.Button)
+		}
+	case ModeQuickMove:
+		if c.Button == 0 || c.Button == 1 {
+			w.quickMove(c.Slot)
+		}
+	case ModeSwap:
+		w.swap(c.Slot, c.Button)
+	case ModeClone:
+		if creative && w.carried.Empty() && w.valid(c.Slot) {
+			if s := w.Slot(c.Slot); !s.Empty() {
+				w.carried = s.WithCount(s.MaxStackSize())
+			}
+		}
+	case ModeThrow:
+		if w.carried.Empty() && w.valid(c.Slot) && !w.Slot(c.Slot).Empty() {
+			n := int32(1)
+			if c.Button == 1 || w.slots[c.Slot].kind == kindOutput {
+				n = w.Slot(c.Slot).Count
+			}
+			thrown = append(thrown, w.take(c.Slot, n))
+		}
+	case ModeDrag:
+		w.dragClick(c.Slot, c.Button, creative)
+	case ModePickupAll:
+		if c.Button == 0 || c.Button == 1 {
+			w.pickupAll(c.Slot, c.Button == 1)
+		}
+	}
+
+	w.updateResult()
+	thrown = append(thrown, w.spilled...)
+	w.spilled = nil
+
+	for i, s := range c.Changed {
+		if w.valid(i) {
+			w.remote[i] = s
+		}
+	}
+	w.remoteCarried = c.Carried
+	if c.StateID != w.stateID {
+		w.desynced = true
+	}
+	return thrown
+}
+
+func (w *Window) valid(i int) bool {
+	return i >= 0 && i < len(w.slots)
+}
+
+// mayPlace reports whether a stack may be put in a slot.
+func (w *Window) mayPlace(i int, s *item.ItemStack) bool {
+	switch slot := w.slots[i]; slot.kind {
+	case kindOutput:
+		return false
+	case kindArmor:
+		piece, ok := ArmorPiece(s)
+		return ok && piece == slot.piece
+	case kindContainer:
+		return w.Type == nil || w.Type.MayPlace == nil || w.Type.MayPlace(i, s)
+	}
+	return true
+}
+
+// limit returns how many of a stack a slot holds.
+func (w *Window) limit(i int, s *item.ItemStack) int32 {
+	if w.slots[i].kind == kindArmor {
+		return 1
+	}
+	return s.MaxStackSize()
+}
+
+// take removes n items from a slot, returning them. Taking the result of a
+// crafting grid crafts it.
+func (w *Window) take(i int, n int32) *item.ItemStack {
+	s := w.Slot(i)
+	n = min(n, s.Count)
+	w.SetSlot(i, s.WithCount(s.Count-n))
+	if i == resultSlot && w.grid > 0 {
+		w.craft()
+	}
+	return s.WithCount(n)
+}
+
+// pickup picks up, puts down or swaps the stack in a slot with the carried one,
+// all of it with the left button and half or one with the right.
+func (w *Window) pickup(i int, button int8) []*item.ItemStack {
+	c := w.carried
+	if i == OutsideSlot {
+		if c.Empty() {
+			return nil
+		}
+		if button == 1 {
+			w.SetCarried(c.WithCount(c.Count - 1))
+			return []*item.ItemStack{c.WithCount(1)}
+		}
+		w.carried = nil
+		return []*item.ItemStack{c}
+	}
+	if !w.valid(i) {
+		return nil
+	}
+
+	s := w.Slot(i)
+	switch {
+	case s.Empty():
+		if c.Empty() || !w.mayPlace(i, c) {
+			return nil
+		}
+		n := c.Count
+		if button == 1 {
+			n = 1
+		}
+		n = min(n, w.limit(i, c))
+		w.SetSlot(i, c.WithCount(n))
+		w.SetCarried(c.WithCount(c.Count - n))
+	case c.Empty():
+		n := s.Count
+		if button == 1 && w.slots[i].kind != kindOutput {
+			n = (n + 1) / 2
+		}
+		w.carried = w.take(i, n)
+	case w.mayPlace(i, c):
+		if s.Stacks(c) {
+			n := c.Count
+			if button == 1 {
+				n = 1
+			}
+			n = min(n, w.limit(i, c)-s.Count)
+			if n > 0 {
+				w.SetSlot(i, s.WithCount(s.Count+n))
+				w.SetCarried(c.WithCount(c.Count - n))
+			}
+		} else if c.Count <= w.limit(i, c) {
+			w.SetSlot(i, c)
+			w.carried = s
+		}
+	case s.Stacks(c):
+		// taking from an output slot onto the carried stack
+		if c.Count+s.Count <= c.MaxStackSize() {
+			w.carried = c.WithCount(c.Count + w.take(i, s.Count).Count)
+		}
+	}
+	return nil
+}
+
+// quickMove moves the stack in a slot to where shift clicking does in vanilla.
+// Stacks from output slots move whole or not at all, and crafting results are
+// crafted again as long as they fit and the grid makes the same.
+func (w *Window) quickMove(i int) {
+	if !w.valid(i) || w.Slot(i).Empty() {
+		return
+	}
+	s := w.Slot(i)
+	if w.slots[i].kind != kindOutput {
+		w.SetSlot(i, w.merge(s.Clone(), w.quickMoveTargets(i, s)))
+		return
+	}
+	first := s
+	for !s.Empty() && s.Stacks(first) && w.moveWhole(i, s) {
+		s = w.Slot(i)
+	}
+}
+
+// moveWhole moves all of the stack in an output slot where shift clicking
+// does, or none of it, reporting whether it did.
+func (w *Window) moveWhole(i int, s *item.ItemStack) bool {
+	targets := w.quickMoveTargets(i, s)
+	before := make([]*item.ItemStack, len(targets))
+	for j, t := range targets {
+		before[j] = w.Slot(t)
+	}
+	if rest := w.merge(s.Clone(), targets); !rest.Empty() {
+		for j, t := range targets {
+			w.SetSlot(t, before[j])
+		}
+		return false
+	}
+	w.take(i, s.Count)
+	return true
+}
+
+// quickMoveTargets returns the slots shift clicking a slot moves its stack to,
+// in the order they are filled.
+func (w *Window) quickMoveTargets(i int, s *item.ItemStack) []int {
+	player := w.playerStart()
+	kind := w.slots[i].kind
+	switch {
+	case kind == kindOutput:
+		return w.span(len(w.slots)-1, player-1)
+	case kind == kindContainer || kind == kindArmor || kind == kindOffhand:
+		if w.Type != nil {
+			return w.span(len(w.slots)-1, player-1)
+		}
+		return w.span(player, player+MainSize)
+	case w.Type != nil:
+		var targets []int
+		for j := range player {
+			if w.slots[j].kind == kindContainer {
+				targets = append(targets, j)
+			}
+		}
+		return targets
+	}
+
+	// the player's own window
+	if piece, ok := ArmorPiece(s); ok {
+		armor := PlayerArmorStart + Head - piece
+		if w.Slot(armor).Empty() {
+			return []int{armor}
+		}
+	}
+	if kind == kindMain {
+		return w.span(PlayerHotbarStart, PlayerHotbarStart+HotbarSize)
+	}
+	return w.span(PlayerMainStart, PlayerHotbarStart)
+}
+
+// playerStart returns the first slot of the window holding the main inventory.
+func (w *Window) playerStart() int {
+	if w.Type == nil {
+		return PlayerMainStart
+	}
+	return w.Type.Size
+}
+
+// span returns the slots from start up to end, counting down when end is
+// before start.
+func (w *Window) span(start, end int) []int {
+	var slots []int
+	for i := start; i != end; {
+		slots = append(slots, i)
+		if start < end {
+			i++
+		} else {
+			i--
+		}
+	}
+	return slots
+}
+
+// merge adds as much of a stack as fits to the stacks of the same item in
+// slots of the window, then to the empty ones, in the order given. It returns
+// what didn't fit.
+func (w *Window) merge(s *item.ItemStack, slots []int) *item.ItemStack {
+	for _, i := range slots {
+		if s.Empty() {
+			return nil
+		}
+		if existing := w.Slot(i); existing.Stacks(s) {
+			n := min(s.Count, w.limit(i, s)-existing.Count)
+			if n > 0 {
+				w.SetSlot(i, existing.WithCount(existing.Count+n))
+				s.Count -= n
+			}
+		}
+	}
+	for _, i := range slots {
+		if s.Empty() {
+			return nil
+		}
+		if w.Slot(i).Empty() && w.mayPlace(i, s) {
+			n := min(s.Count, w.limit(i, s))
+			w.SetSlot(i, s.WithCount(n))
+			s.Count -= n
+		}
+	}
+	if s.Empty() {
+		return nil
+	}
+	return s
+}
+
+// swap swaps the stack in a slot with a hotbar slot, or the offhand with
+// button 40.
+func (w *Window) swap(i int, button int8) {
+	var hotbar int
+	switch {
+	case button >= 0 && button < HotbarSize:
+		hotbar = int(button)
+	case button == Offhand:
+		hotbar = Offhand
+	default:
+		return
+	}
+	if !w.valid(i) {
+		return
+	}
+
+	s, h := w.Slot(i), w.player.Slot(hotbar)
+	switch {
+	case s.Empty() && h.Empty():
+	case s.Empty():
+		if w.mayPlace(i, h) {
+			n := min(h.Count, w.limit(i, h))
+			w.SetSlot(i, h.WithCount(n))
+			w.player.SetSlot(hotbar, h.WithCount(h.Count-n))
+		}
+	case h.Empty():
+		w.player.SetSlot(hotbar, w.take(i, s.Count))
+	case w.mayPlace(i, h) && h.Count <= w.limit(i, h):
+		w.SetSlot(i, h)
+		w.player.SetSlot(hotbar, s)
+	}
+}
+
+// dragClick handles the clicks of a drag: starting it, adding slots to it and
+// ending it, which spreads the carried stack over the slots.
+func (w *Window) dragClick(i int, button int8, creative bool) {
+	stage, kind := button&3, button>>2
+	switch stage {
+	case 0:
+		if w.carried.Empty() || kind > dragClone || kind == dragClone && !creative {
+			w.drag = drag{}
+			return
+		}
+		w.drag = drag{active: true, kind: kind}
+	case 1:
+		if !w.drag.active || kind != w.drag.kind {
+			w.drag = drag{}
+			return
+		}
+		if w.valid(i) && w.canDragInto(i) && !slices.Contains(w.drag.slots, i) &&
+			(kind == dragClone || int(w.carried.Count) > len(w.drag.slots)) {
+			w.drag.slots = append(w.drag.slots, i)
+		}
+	case 2:
+		d := w.drag
+		w.drag = drag{}
+		if !d.active || kind != d.kind || w.carried.Empty() {
+			return
+		}
+		if len(d.slots) == 1 {
+			if kind != dragClone {
+				w.pickup(d.slots[0], kind)
+			}
+			return
+		}
+		w.spread(d)
+	}
+}
+
+// canDragInto reports whether a drag of the carried stack may add to a slot.
+func (w *Window) canDragInto(i int) bool {
+	s := w.Slot(i)
+	return w.mayPlace(i, w.carried) && (s.Empty() || s.Stacks(w.carried) && s.Count < w.limit(i, s))
+}
+
+// spread spreads the carried stack over the slots of a drag.
+func (w *Window) spread(d drag) {
+	c := w.carried
+	remaining := c.Count
+	for _, i := range d.slots {
+		if !w.canDragInto(i) || remaining <= 0 && d.kind != dragClone {
+			continue
+		}
+		var existing int32
+		if s := w.Slot(i); !s.Empty() {
+			existing = s.Count
+		}
+		var add int32
+		switch d.kind {
+		case dragEvenly:
+			add = c.Count / int32(len(d.slots))
+		case dragOne:
+			add = 1
+		case dragClone:
+			add = c.MaxStackSize()
+		}
+		n := min(existing+add, w.limit(i, c))
+		if d.kind != dragClone {
+			n = min(n, existing+remaining)
+			remaining -= n - existing
+		}
+		w.SetSlot(i, c.WithCount(n))
+	}
+	if d.kind != dragClone {
+		w.SetCarried(c.WithCount(remaining))
+	}
+}
+
+// pickupAll collects stacks of the carried item into it, first those that
+// aren't full, searching the window backward for the right button.
+func (w *Window) pickupAll(i int, backward bool) {
+	if w.carried.Empty() || w.valid(i) && !w.Slot(i).Empty() {
+		return
+	}
+	c := w.carried.Clone()
+	w.carried = c
+	order := w.span(0, len(w.slots))
+	if backward {
+		slices.Reverse(order)
+	}
+	for pass := range 2 {
+		for _, j := range order {
+			if c.Count >= c.MaxStackSize() {
+				return
+			}
+			s := w.Slot(j)
+			if w.slots[j].kind == kindOutput || !s.Stacks(c) || pass == 0 && s.Count == s.MaxStackSize() {
+				continue
+			}
+			c.Count += w.take(j, c.MaxStackSize()-c.Count).Count
+		}
+	}
+}
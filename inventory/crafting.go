@@ -0,0 +1,165 @@
+package inventory
+
+import (
+	"slices"
+
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/recipe"
+)
+
+// resultSlot is the slot of windows with a crafting grid holding what it
+// crafts, followed by the grid row by row.
+const resultSlot = 0
+
+// maxPlaced is the most times the recipe book places the ingredients of a
+// recipe, a stack of each.
+const maxPlaced = 64
+
+// inGrid reports whether a slot is one of the crafting grid.
+func (w *Window) inGrid(i int) bool {
+	return i > resultSlot && i <= w.grid*w.grid
+}
+
+// craftingGrid returns the items in the crafting grid.
+func (w *Window) craftingGrid() recipe.Grid {
+	g := recipe.Grid{Width: w.grid, Height: w.grid, Slots: make([]*item.ItemStack, w.grid*w.grid)}
+	for j := range g.Slots {
+		g.Slots[j] = w.Slot(resultSlot + 1 + j)
+	}
+	return g
+}
+
+// updateResult finds what the crafting grid crafts when it changed.
+func (w *Window) updateResult() {
+	if w.grid == 0 || !w.gridChanged {
+		return
+	}
+	w.gridChanged = false
+	var result *item.ItemStack
+	g := w.craftingGrid()
+	if r, ok := recipe.Default().Craft(g); ok {
+		result = r.Assemble(g)
+	}
+	w.SetSlot(resultSlot, result)
+}
+
+// craft uses up one of every stack in the crafting grid after its result was
+// taken, leaving what remains of them in their slot or else giving it to the
+// player.
+func (w *Window) craft() {
+	for j := resultSlot + 1; j <= w.grid*w.grid; j++ {
+		s := w.Slot(j)
+		if s.Empty() {
+			continue
+		}
+		rest := s.WithCount(s.Count - 1)
+		w.SetSlot(j, rest)
+		if remainder := recipe.Remainder(s); remainder != nil {
+			if rest.Empty() {
+				w.SetSlot(j, remainder)
+			} else if left := w.player.Add(remainder); left != nil {
+				w.spilled = append(w.spilled, left)
+			}
+		}
+	}
+	w.updateResult()
+}
+
+// PlaceRecipe fills the crafting grid of the window with the ingredients of a
+// recipe taken from the inventory of the player, as the recipe book does:
+// once, or with all as many times as the ingredients allow. What the grid
+// held goes back to the inventory first. It reports whether the player had
+// the ingredients, so the client can otherwise be shown what is missing.
+func (w *Window) PlaceRecipe(r recipe.CraftingRecipe, all bool) bool {
+	if w.grid == 0 || !w.carried.Empty() {
+		return false
+	}
+	layout := r.Layout(w.grid, w.grid)
+	if layout == nil || !w.clearGrid() {
+		return false
+	}
+
+	available := make(map[*item.Item]int32)
+	for _, s := range w.player.slots[:MainSize] {
+		if !s.Empty() {
+			available[s.Item] += s.Count
+		}
+	}
+	times := int32(1)
+	if all {
+		times = maxPlaced
+	}
+	for ; times > 0; times-- {
+		if choice, ok := choose(layout, available, times); ok {
+			for j, i := range choice {
+				if i != nil {
+					w.SetSlot(resultSlot+1+j, w.takeFromPlayer(i, times))
+				}
+			}
+			w.updateResult()
+			return true
+		}
+	}
+	return false
+}
+
+// clearGrid moves what is in the crafting grid to the inventory of the
+// player, reporting whether it all fit. Nothing is moved unless it does.
+func (w *Window) clearGrid() bool {
+	p := &PlayerInventory{Inventory: &Inventory{slots: slices.Clone(w.player.slots)}, Selected: w.player.Selected}
+	for j := resultSlot + 1; j <= w.grid*w.grid; j++ {
+		if s := w.Slot(j); !s.Empty() && p.Add(s) != nil {
+			return false
+		}
+	}
+	w.player.slots = p.slots
+	for j := resultSlot + 1; j <= w.grid*w.grid; j++ {
+		w.SetSlot(j, nil)
+	}
+	return true
+}
+
+// choose picks the item of each ingredient of a layout to place times from
+// what is available, reporting whether there is enough of them.
+func choose(layout []recipe.Ingredient, available map[*item.Item]int32, times int32) ([]*item.Item, bool) {
+	reserved := make(map[*item.Item]int32)
+	choice := make([]*item.Item, len(layout))
+	for j, in := range layout {
+		if len(in) == 0 {
+			continue
+		}
+		for _, i := range in {
+			if available[i]-reserved[i] >= times && times <= i.MaxStackSize() {
+				choice[j] = i
+				reserved[i] += times
+				break
+			}
+		}
+		if choice[j] == nil {
+			return nil, false
+		}
+	}
+	return choice, true
+}
+
+// takeFromPlayer takes n of an item from the main inventory of the player,
+// which must hold that many, as one stack.
+func (w *Window) takeFromPlayer(i *item.Item, n int32) *item.ItemStack {
+	var taken *item.ItemStack
+	for slot, s := range w.player.slots[:MainSize] {
+		if n == 0 {
+			break
+		}
+		if s.Empty() || s.Item != i {
+			continue
+		}
+		k := min(n, s.Count)
+		if taken == nil {
+			taken = s.WithCount(0)
+		}
+		taken.Count += k
+		w.player.SetSlot(slot, s.WithCount(s.Count-k))
+		n -= k
+	}
+	return taken
+}
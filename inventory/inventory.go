@@ -0,0 +1,91 @@
+// Package inventory holds the slots items are kept in and the windows players
+// move them around in.
+//
+// https://wiki.vg/Inventory
+package inventory
+
+import "github.com/hunterros-s/algernon/item"
+
+// Inventory is a number of slots each holding a stack of items, like the
+// inventory of a player or a chest. It belongs to the game loop.
+type Inventory struct {
+	slots []*item.ItemStack
+}
+
+// New returns an empty inventory of size slots.
+func New(size int) *Inventory {
+	return &Inventory{slots: make([]*item.ItemStack, size)}
+}
+
+// Size returns how many slots the inventory has.
+func (inv *Inventory) Size() int {
+	return len(inv.slots)
+}
+
+// Slot returns the stack in a slot, nil when it is empty.
+func (inv *Inventory) Slot(i int) *item.ItemStack {
+	return inv.slots[i]
+}
+
+// SetSlot puts a stack in a slot, emptying it for empty stacks.
+func (inv *Inventory) SetSlot(i int, s *item.ItemStack) {
+	if s.Empty() {
+		s = nil
+	}
+	inv.slots[i] = s
+}
+
+// Empty reports whether every slot is empty.
+func (inv *Inventory) Empty() bool {
+	for _, s := range inv.slots {
+		if !s.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear empties every slot, returning the stacks it held.
+func (inv *Inventory) Clear() []*item.ItemStack {
+	var stacks []*item.ItemStack
+	for i, s := range inv.slots {
+		if !s.Empty() {
+			stacks = append(stacks, s)
+		}
+		inv.slots[i] = nil
+	}
+	return stacks
+}
+
+// merge adds as much of a stack as fits to the stacks of the same item in
+// slots, then to the empty ones, in the order given. It returns what didn't
+// fit, nil when everything did.
+func (inv *Inventory) merge(s *item.ItemStack, slots []int) *item.ItemStack {
+	s = s.Clone()
+	for _, i := range slots {
+		if s.Empty() {
+			return nil
+		}
+		if existing := inv.slots[i]; existing.Stacks(s) {
+			n := min(s.Count, existing.MaxStackSize()-existing.Count)
+			if n > 0 {
+				inv.slots[i] = existing.WithCount(existing.Count + n)
+				s.Count -= n
+			}
+		}
+	}
+	for _, i := range slots {
+		if s.Empty() {
+			return nil
+		}
+		if inv.slots[i].Empty() {
+			n := min(s.Count, s.MaxStackSize())
+			inv.slots[i] = s.WithCount(n)
+			s.Count -= n
+		}
+	}
+	if s.Empty() {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+package inventory
+
+import (
+	"strings"
+
+	"github.com/hunterros-s/algernon/item"
+)
+
+// Slots of the inventory of a player, as vanilla numbers them in storage
+const (
+	// HotbarSize is how many of the first slots are the hotbar.
+	HotbarSize = 9
+	// MainSize is how many slots the hotbar and the rest of the main
+	// inventory have.
+	MainSize = 36
+	// ArmorStart is the slot of the boots, followed by the leggings, the
+	// chestplate and the helmet.
+	ArmorStart = 36
+	Offhand    = 40
+	PlayerSize = 41
+)
+
+// Armor pieces, by their slot after ArmorStart
+const (
+	Feet = iota
+	Legs
+	Chest
+	Head
+)
+
+// Crafting grid of the player's inventory window: its result and the 2×2 grid
+const (
+	craftingWidth = 2
+	craftingSize  = 1 + craftingWidth*craftingWidth
+)
+
+// PlayerInventory is the inventory of a player, along with the crafting grid
+// of its inventory window.
+type PlayerInventory struct {
+	*Inventory
+	// Selected is the hotbar slot held in the main hand.
+	Selected int
+
+	crafting *Inventory
+}
+
+// NewPlayer returns an empty player inventory.
+func NewPlayer() *PlayerInventory {
+	return &PlayerInventory{Inventory: New(PlayerSize), crafting: New(craftingSize)}
+}
+
+// Held returns the stack held in the main hand.
+func (p *PlayerInventory) Held() *item.ItemStack {
+	return p.Slot(p.Selected)
+}
+
+// HandSlot returns the slot held in a hand, the main hand being 0.
+func (p *PlayerInventory) HandSlot(hand int32) int {
+	if hand == 1 {
+		return Offhand
+	}
+	return p.Selected
+}
+
+// Crafting returns the crafting grid of the inventory window: the result in
+// slot 0, then the grid row by row.
+func (p *PlayerInventory) Crafting() *Inventory {
+	return p.crafting
+}
+
+// Add adds a stack to the inventory as vanilla picks up items: onto stacks of
+// the same item, first the held one and the offhand, then into the first
+// empty slot of the main inventory. It returns what didn't fit.
+func (p *PlayerInventory) Add(s *item.ItemStack) *item.ItemStack {
+	order := make([]int, 0, MainSize+1)
+	order = append(order, p.Selected, Offhand)
+	for i := 0; i < MainSize; i++ {
+		if i != p.Selected {
+			order = append(order, i)
+		}
+	}
+
+	s = s.Clone()
+	for _, i := range order {
+		if s.Empty() {
+			return nil
+		}
+		if existing := p.slots[i]; existing.Stacks(s) {
+			n := min(s.Count, existing.MaxStackSize()-existing.Count)
+			p.slots[i] = existing.WithCount(existing.Count + n)
+			s.Count -= n
+		}
+	}
+	return p.merge(s, mainSlots)
+}
+
+// mainSlots are the slots of the main inventory, the hotbar first.
+var mainSlots = func() []int {
+	slots := make([]int, MainSize)
+	for i := range slots {
+		slots[i] = i
+	}
+	return slots
+}()
+
+// armorSuffixes name the items worn in each armor slot.
+var armorSuffixes = [4][]string{
+	Feet:  {"_boots"},
+	Legs:  {"_leggings"},
+	Chest: {"_chestplate", ":elytra"},
+	Head:  {"_helmet", "_head", "_skull", ":carved_pumpkin"},
+}
+
+// ArmorPiece returns the armor slot a stack is worn in.
+func ArmorPiece(s *item.ItemStack) (int, bool) {
+	if s.Empty() {
+		return 0, false
+	}
+	for piece, suffixes := range armorSuffixes {
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(s.Item.Name, suffix) {
+				return piece, true
+			}
+		}
+	}
+	return 0, false
+}
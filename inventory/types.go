@@ -0,0 +1,94 @@
+package inventory
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// Type is a kind of container window, which the client draws by its ID in the
+// menu registry. Windows show the slots of the container first, then the main
+// inventory and the hotbar of the player.
+type Type struct {
+	Name string
+	ID   int32
+	// Size is how many slots the container has.
+	Size int
+	// Title is what windows of the type are called unless named otherwise.
+	Title text.TextComponent
+	// Output are the slots of the container items are only taken from, like
+	// the result of crafting.
+	Output []int
+	// MayPlace reports whether a stack may be put in a slot of the container,
+	// nil when any may.
+	MayPlace func(slot int, s *item.ItemStack) bool
+	// Temporary is whether windows of the type hold their own container,
+	// like the grid of a crafting table, which is emptied into the inventory
+	// of the player when the window closes.
+	Temporary bool
+	// CraftingGrid is the width of the square crafting grid in the slots
+	// after slot 0, which holds what it crafts, 0 for types without one.
+	CraftingGrid int
+}
+
+// output reports whether a slot of the container is only taken from.
+func (t *Type) output(slot int) bool {
+	return slices.Contains(t.Output, slot)
+}
+
+func generic(name string, id int32, size int, title string) *Type {
+	return &Type{Name: name, ID: id, Size: size, Title: text.TextComponent{Translate: title}}
+}
+
+// Container types
+var (
+	Generic9x1 = generic("minecraft:generic_9x1", 0, 9, "container.chest")
+	Generic9x2 = generic("minecraft:generic_9x2", 1, 18, "container.chest")
+	Generic9x3 = generic("minecraft:generic_9x3", 2, 27, "container.chest")
+	Generic9x4 = generic("minecraft:generic_9x4", 3, 36, "container.chest")
+	Generic9x5 = generic("minecraft:generic_9x5", 4, 45, "container.chest")
+	Generic9x6 = generic("minecraft:generic_9x6", 5, 54, "container.chestDouble")
+	Generic3x3 = generic("minecraft:generic_3x3", 6, 9, "container.dispenser")
+	Crafting   = &Type{
+		Name:         "minecraft:crafting",
+		ID:           12,
+		Size:         10,
+		Title:        text.TextComponent{Translate: "container.crafting"},
+		Output:       []int{0},
+		Temporary:    true,
+		CraftingGrid: 3,
+	}
+	Hopper     = generic("minecraft:hopper", 16, 5, "container.hopper")
+	ShulkerBox = &Type{
+		Name:  "minecraft:shulker_box",
+		ID:    20,
+		Size:  27,
+		Title: text.TextComponent{Translate: "container.shulkerBox"},
+		// shulker boxes can't hold shulker boxes
+		MayPlace: func(_ int, s *item.ItemStack) bool {
+			return !strings.HasSuffix(s.Item.Name, "shulker_box")
+		},
+	}
+)
+
+var types = map[string]*Type{}
+
+func init() {
+	for _, t := range []*Type{Generic9x1, Generic9x2, Generic9x3, Generic9x4, Generic9x5, Generic9x6, Generic3x3, Crafting, Hopper, ShulkerBox} {
+		RegisterType(t)
+	}
+}
+
+// RegisterType adds a container type, replacing the one of the same name. Types
+// must be registered before the game loop starts.
+func RegisterType(t *Type) {
+	types[t.Name] = t
+}
+
+// TypeNamed returns a container type by name.
+func TypeNamed(name string) (*Type, bool) {
+	t, ok := types[name]
+	return t, ok
+}
@@ -0,0 +1,267 @@
+package inventory
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// PlayerWindowID is the ID of the window of the player's own inventory, which
+// is always open beneath the others.
+const PlayerWindowID = 0
+
+// Slots of the window of the player's own inventory
+const (
+	PlayerResultSlot  = 0
+	PlayerCraftStart  = 1
+	PlayerArmorStart  = 5 // helmet first
+	PlayerMainStart   = 9
+	PlayerHotbarStart = 36
+	PlayerOffhandSlot = 45
+)
+
+// slotKind is what a slot of a window is for, which decides what goes in it and
+// where shift clicking moves what it holds.
+type slotKind int
+
+const (
+	kindContainer slotKind = iota
+	kindOutput
+	kindArmor
+	kindOffhand
+	kindMain
+	kindHotbar
+)
+
+type slot struct {
+	inv   *Inventory
+	index int
+	kind  slotKind
+	// piece is the armor worn in armor slots.
+	piece int
+}
+
+// Update is a slot whose contents the client is sent, -1 for the stack carried
+// by the cursor.
+type Update struct {
+	Slot    int
+	Stack   *item.ItemStack
+	StateID int32
+}
+
+// Window is a window a player has open, showing the slots of containers and
+// of its inventory. It keeps the contents the client believes the slots have
+// to send it what changed. It belongs to the game loop.
+type Window struct {
+	ID int32
+	// Type is nil for the window of the player's own inventory.
+	Type  *Type
+	Title text.TextComponent
+
+	player     *PlayerInventory
+	containers []*Inventory
+	slots      []slot
+	carried    *item.ItemStack
+	drag       drag
+
+	// grid is the width of the crafting grid after slot 0, and gridChanged
+	// whether what it crafts must be found again
+	grid        int
+	gridChanged bool
+	// spilled are the stacks that fell out of the window during a click
+	spilled []*item.ItemStack
+
+	stateID int32
+	// the contents of the slots and cursor as the client has them, and
+	// whether they are not known and must all be sent
+	remote        []*item.ItemStack
+	remoteCarried *item.ItemStack
+	desynced      bool
+}
+
+// NewPlayerWindow returns the window of a player's own inventory.
+func NewPlayerWindow(p *PlayerInventory) *Window {
+	w := &Window{ID: PlayerWindowID, player: p, grid: craftingWidth}
+	w.slots = append(w.slots, slot{inv: p.crafting, index: 0, kind: kindOutput})
+	for i := 1; i < craftingSize; i++ {
+		w.slots = append(w.slots, slot{inv: p.crafting, index: i, kind: kindContainer})
+	}
+	for piece := Head; piece >= Feet; piece-- {
+		w.slots = append(w.slots, slot{inv: p.Inventory, index: ArmorStart + piece, kind: kindArmor, piece: piece})
+	}
+	w.addPlayerSlots()
+	w.slots = append(w.slots, slot{inv: p.Inventory, index: Offhand, kind: kindOffhand})
+	w.reset()
+	return w
+}
+
+// NewWindow returns a window of a container type showing containers, whose
+// sizes must add up to the size of the type, and the inventory of a player.
+// Windows of temporary types make their own container when given none.
+func NewWindow(id int32, t *Type, title text.TextComponent, p *PlayerInventory, containers ...*Inventory) *Window {
+	if t.Temporary && len(containers) == 0 {
+		containers = []*Inventory{New(t.Size)}
+	}
+	w := &Window{ID: id, Type: t, Title: title, player: p, containers: containers, grid: t.CraftingGrid}
+	for _, inv := range containers {
+		for i := range inv.slots {
+			kind := kindContainer
+			if t.output(len(w.slots)) {
+				kind = kindOutput
+			}
+			w.slots = append(w.slots, slot{inv: inv, index: i, kind: kind})
+		}
+	}
+	if len(w.slots) != t.Size {
+		panic(fmt.Sprintf("inventory: containers of %d slots for %s of %d", len(w.slots), t.Name, t.Size))
+	}
+	w.addPlayerSlots()
+	w.reset()
+	return w
+}
+
+// addPlayerSlots adds the main inventory then the hotbar of the player.
+func (w *Window) addPlayerSlots() {
+	for i := HotbarSize; i < MainSize; i++ {
+		w.slots = append(w.slots, slot{inv: w.player.Inventory, index: i, kind: kindMain})
+	}
+	for i := 0; i < HotbarSize; i++ {
+		w.slots = append(w.slots, slot{inv: w.player.Inventory, index: i, kind: kindHotbar})
+	}
+}
+
+func (w *Window) reset() {
+	w.remote = make([]*item.ItemStack, len(w.slots))
+	w.desynced = true
+}
+
+// Shows reports whether the window shows the slots of an inventory.
+func (w *Window) Shows(inv *Inventory) bool {
+	for _, s := range w.slots {
+		if s.inv == inv {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns how many slots the window has.
+func (w *Window) Size() int {
+	return len(w.slots)
+}
+
+// Slot returns the stack in a slot of the window.
+func (w *Window) Slot(i int) *item.ItemStack {
+	s := w.slots[i]
+	return s.inv.Slot(s.index)
+}
+
+// SetSlot puts a stack in a slot of the window.
+func (w *Window) SetSlot(i int, stack *item.ItemStack) {
+	s := w.slots[i]
+	s.inv.SetSlot(s.index, stack)
+	if w.inGrid(i) {
+		w.gridChanged = true
+	}
+}
+
+// Carried returns the stack carried by the cursor.
+func (w *Window) Carried() *item.ItemStack {
+	return w.carried
+}
+
+// SetCarried sets the stack carried by the cursor.
+func (w *Window) SetCarried(s *item.ItemStack) {
+	if s.Empty() {
+		s = nil
+	}
+	w.carried = s
+}
+
+// Resync makes the next updates send the client every slot.
+func (w *Window) Resync() {
+	w.desynced = true
+}
+
+// Desynced reports whether the client must be sent every slot, with Content.
+func (w *Window) Desynced() bool {
+	return w.desynced
+}
+
+// Content returns the state ID, the stacks of every slot and the carried stack
+// to send the client, as it will have them.
+func (w *Window) Content() (stateID int32, slots []*item.ItemStack, carried *item.ItemStack) {
+	w.updateResult()
+	slots = make([]*item.ItemStack, len(w.slots))
+	for i := range w.slots {
+		slots[i] = w.Slot(i).Clone()
+		w.remote[i] = slots[i]
+	}
+	w.remoteCarried = w.carried.Clone()
+	w.desynced = false
+	return w.nextState(), slots, w.remoteCarried
+}
+
+// Changes returns the slots and cursor whose contents changed from what the
+// client has, as it will have them.
+func (w *Window) Changes() []Update {
+	w.updateResult()
+	var updates []Update
+	for i := range w.slots {
+		if s := w.Slot(i); !same(s, w.remote[i]) {
+			w.remote[i] = s.Clone()
+			updates = append(updates, Update{Slot: i, Stack: w.remote[i], StateID: w.nextState()})
+		}
+	}
+	if !same(w.carried, w.remoteCarried) {
+		w.remoteCarried = w.carried.Clone()
+		updates = append(updates, Update{Slot: -1, Stack: w.remoteCarried, StateID: w.nextState()})
+	}
+	return updates
+}
+
+// nextState returns a new state ID, which the client sends back to show what
+// it has seen.
+func (w *Window) nextState() int32 {
+	w.stateID = (w.stateID + 1) & 0x7fff
+	return w.stateID
+}
+
+// Close closes the window, returning what the player carried and what was in
+// the crafting grids it closes, to be given back to it.
+func (w *Window) Close() []*item.ItemStack {
+	var back []*item.ItemStack
+	if !w.carried.Empty() {
+		back = append(back, w.carried)
+	}
+	w.carried = nil
+	w.drag = drag{}
+
+	var grids []*Inventory
+	if w.Type == nil {
+		grids = append(grids, w.player.crafting)
+	} else if w.Type.Temporary {
+		grids = w.containers
+	}
+	for _, inv := range grids {
+		for i, s := range w.slots {
+			if s.inv == inv && s.kind == kindOutput {
+				inv.SetSlot(s.index, nil)
+			} else if s.inv == inv && !inv.Slot(s.index).Empty() {
+				back = append(back, inv.Slot(s.index))
+				w.SetSlot(i, nil)
+			}
+		}
+	}
+	return back
+}
+
+// same reports whether two stacks are alike enough that the client needn't be
+// sent one in place of the other.
+func same(a, b *item.ItemStack) bool {
+	if a.Empty() || b.Empty() {
+		return a.Empty() == b.Empty()
+	}
+	return a.Count == b.Count && a.Stacks(b)
+}
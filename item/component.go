@@ -0,0 +1,441 @@
+package item
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/registry"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// Component is a type of data component, by its ID in the 1.21 registry.
+//
+// https://wiki.vg/Slot_Data#Structured_components
+type Component int32
+
+// Data components, the types of their values in the comments. Components
+// marked unit have the value struct{}.
+const (
+	CustomData               Component = 0  // nbt.Compound
+	MaxStackSize             Component = 1  // int32
+	MaxDamage                Component = 2  // int32
+	Damage                   Component = 3  // int32
+	Unbreakable              Component = 4  // bool, whether it shows in the tooltip
+	CustomName               Component = 5  // text.TextComponent
+	ItemName                 Component = 6  // text.TextComponent
+	Lore                     Component = 7  // []text.TextComponent
+	Rarity                   Component = 8  // int32, one of the Rarity values
+	Enchantments             Component = 9  // ItemEnchantments
+	CustomModelData          Component = 13 // int32
+	HideAdditionalTooltip    Component = 14 // unit
+	HideTooltip              Component = 15 // unit
+	RepairCost               Component = 16 // int32
+	CreativeSlotLock         Component = 17 // unit
+	EnchantmentGlintOverride Component = 18 // bool
+	FireResistant            Component = 21 // unit
+	StoredEnchantments       Component = 23 // ItemEnchantments
+	DyedColor                Component = 24 // DyedItemColor
+	MapColor                 Component = 25 // int32
+	MapID                    Component = 26 // int32
+)
+
+// Rarities, which color the names of items
+const (
+	RarityCommon int32 = iota
+	RarityUncommon
+	RarityRare
+	RarityEpic
+)
+
+// maxLore is how many lines of lore vanilla accepts.
+const maxLore = 256
+
+// ItemEnchantments are the enchantments of an item, or those an enchanted book
+// holds, as levels by enchantment name.
+type ItemEnchantments struct {
+	Levels        map[string]int32
+	ShowInTooltip bool
+}
+
+// DyedItemColor is the color of dyed leather armor.
+type DyedItemColor struct {
+	RGB           int32
+	ShowInTooltip bool
+}
+
+// codec reads and writes the value of a component on the wire and in NBT.
+type codec struct {
+	name    string
+	write   func(w *io.Writer, v any)
+	read    func(r *io.Reader) (any, error)
+	toNBT   func(v any) (any, error)
+	fromNBT func(tag any) (any, error)
+}
+
+var codecs = map[Component]codec{
+	CustomData:               {"minecraft:custom_data", writeNBT, readCompound, identity, compoundFromNBT},
+	MaxStackSize:             varInt("minecraft:max_stack_size"),
+	MaxDamage:                varInt("minecraft:max_damage"),
+	Damage:                   varInt("minecraft:damage"),
+	Unbreakable:              {"minecraft:unbreakable", writeBool, readBool, tooltipToNBT, tooltipFromNBT},
+	CustomName:               {"minecraft:custom_name", writeText, readText, textToNBT, textFromNBT},
+	ItemName:                 {"minecraft:item_name", writeText, readText, textToNBT, textFromNBT},
+	Lore:                     {"minecraft:lore", writeLore, readLore, loreToNBT, loreFromNBT},
+	Rarity:                   {"minecraft:rarity", writeVarInt, readVarInt, rarityToNBT, rarityFromNBT},
+	Enchantments:             enchantments("minecraft:enchantments"),
+	CustomModelData:          varInt("minecraft:custom_model_data"),
+	HideAdditionalTooltip:    unit("minecraft:hide_additional_tooltip"),
+	HideTooltip:              unit("minecraft:hide_tooltip"),
+	RepairCost:               varInt("minecraft:repair_cost"),
+	CreativeSlotLock:         unit("minecraft:creative_slot_lock"),
+	EnchantmentGlintOverride: {"minecraft:enchantment_glint_override", writeBool, readBool, identity, boolFromNBT},
+	FireResistant:            unit("minecraft:fire_resistant"),
+	StoredEnchantments:       enchantments("minecraft:stored_enchantments"),
+	DyedColor:                {"minecraft:dyed_color", writeDyedColor, readDyedColor, dyedColorToNBT, dyedColorFromNBT},
+	MapColor:                 {"minecraft:map_color", writeInt, readInt, identity, intFromNBT},
+	MapID:                    varInt("minecraft:map_id"),
+}
+
+// componentsByName are the components by the names NBT stores them under.
+var componentsByName = func() map[string]Component {
+	byName := make(map[string]Component, len(codecs))
+	for c, codec := range codecs {
+		byName[codec.name] = c
+	}
+	return byName
+}()
+
+// String returns the name of the component.
+func (c Component) String() string {
+	if codec, ok := codecs[c]; ok {
+		return codec.name
+	}
+	return fmt.Sprintf("component %d", int32(c))
+}
+
+func varInt(name string) codec {
+	return codec{name, writeVarInt, readVarInt, identity, intFromNBT}
+}
+
+func unit(name string) codec {
+	return codec{
+		name:    name,
+		write:   func(*io.Writer, any) {},
+		read:    func(*io.Reader) (any, error) { return struct{}{}, nil },
+		toNBT:   func(any) (any, error) { return nbt.Compound{}, nil },
+		fromNBT: func(any) (any, error) { return struct{}{}, nil },
+	}
+}
+
+func enchantments(name string) codec {
+	return codec{name, writeEnchantments, readEnchantments, enchantmentsToNBT, enchantmentsFromNBT}
+}
+
+func identity(v any) (any, error) {
+	return v, nil
+}
+
+func writeVarInt(w *io.Writer, v any) {
+	w.WriteVarInt(v.(int32))
+}
+
+func readVarInt(r *io.Reader) (any, error) {
+	return r.ReadVarInt(), r.Err()
+}
+
+func writeInt(w *io.Writer, v any) {
+	w.WriteInt(v.(int32))
+}
+
+func readInt(r *io.Reader) (any, error) {
+	return r.ReadInt(), r.Err()
+}
+
+func intFromNBT(tag any) (any, error) {
+	switch tag := tag.(type) {
+	case int8:
+		return int32(tag), nil
+	case int16:
+		return int32(tag), nil
+	case int32:
+		return tag, nil
+	}
+	return nil, fmt.Errorf("expected an int, got %T", tag)
+}
+
+func writeBool(w *io.Writer, v any) {
+	w.WriteBool(v.(bool))
+}
+
+func readBool(r *io.Reader) (any, error) {
+	return r.ReadBool(), r.Err()
+}
+
+func boolFromNBT(tag any) (any, error) {
+	b, ok := tag.(int8)
+	if !ok {
+		return nil, fmt.Errorf("expected a byte, got %T", tag)
+	}
+	return b != 0, nil
+}
+
+// tooltipToNBT stores whether a component shows in the tooltip, left out when
+// it does.
+func tooltipToNBT(v any) (any, error) {
+	if v.(bool) {
+		return nbt.Compound{}, nil
+	}
+	return nbt.Compound{"show_in_tooltip": int8(0)}, nil
+}
+
+func tooltipFromNBT(tag any) (any, error) {
+	c, ok := tag.(nbt.Compound)
+	if !ok {
+		return nil, fmt.Errorf("expected a compound, got %T", tag)
+	}
+	return showInTooltip(c), nil
+}
+
+func showInTooltip(c nbt.Compound) bool {
+	show, ok := c.Byte("show_in_tooltip")
+	return !ok || show != 0
+}
+
+func writeNBT(w *io.Writer, v any) {
+	w.WriteNBT(v)
+}
+
+func readCompound(r *io.Reader) (any, error) {
+	tag := r.ReadNBT()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return compoundFromNBT(tag)
+}
+
+func compoundFromNBT(tag any) (any, error) {
+	c, ok := tag.(nbt.Compound)
+	if !ok {
+		return nil, fmt.Errorf("expected a compound, got %T", tag)
+	}
+	return c, nil
+}
+
+func writeText(w *io.Writer, v any) {
+	w.WriteTextComponent(v.(text.TextComponent))
+}
+
+func readText(r *io.Reader) (any, error) {
+	return r.ReadTextComponent(), r.Err()
+}
+
+// textToNBT stores a text component as its JSON, as vanilla 1.21 does in item
+// components.
+func textToNBT(v any) (any, error) {
+	data, err := json.Marshal(v.(text.TextComponent))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func textFromNBT(tag any) (any, error) {
+	s, ok := tag.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a string, got %T", tag)
+	}
+	var comp text.TextComponent
+	if err := json.Unmarshal([]byte(s), &comp); err != nil {
+		return nil, err
+	}
+	return comp, nil
+}
+
+func writeLore(w *io.Writer, v any) {
+	lore := v.([]text.TextComponent)
+	w.WriteVarInt(int32(len(lore)))
+	for _, line := range lore {
+		w.WriteTextComponent(line)
+	}
+}
+
+func readLore(r *io.Reader) (any, error) {
+	n := r.ReadVarInt()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if n < 0 || n > maxLore {
+		return nil, fmt.Errorf("lore of %d lines", n)
+	}
+	lore := make([]text.TextComponent, n)
+	for i := range lore {
+		lore[i] = r.ReadTextComponent()
+	}
+	return lore, r.Err()
+}
+
+func loreToNBT(v any) (any, error) {
+	lore := v.([]text.TextComponent)
+	list := make(nbt.List, len(lore))
+	for i, line := range lore {
+		tag, err := textToNBT(line)
+		if err != nil {
+			return nil, err
+		}
+		list[i] = tag
+	}
+	return list, nil
+}
+
+func loreFromNBT(tag any) (any, error) {
+	list, ok := tag.(nbt.List)
+	if !ok {
+		return nil, fmt.Errorf("expected a list, got %T", tag)
+	}
+	if len(list) > maxLore {
+		return nil, fmt.Errorf("lore of %d lines", len(list))
+	}
+	lore := make([]text.TextComponent, len(list))
+	for i, line := range list {
+		comp, err := textFromNBT(line)
+		if err != nil {
+			return nil, err
+		}
+		lore[i] = comp.(text.TextComponent)
+	}
+	return lore, nil
+}
+
+var rarities = []string{"common", "uncommon", "rare", "epic"}
+
+func rarityToNBT(v any) (any, error) {
+	r := v.(int32)
+	if r < 0 || int(r) >= len(rarities) {
+		return nil, fmt.Errorf("unknown rarity %d", r)
+	}
+	return rarities[r], nil
+}
+
+func rarityFromNBT(tag any) (any, error) {
+	for i, r := range rarities {
+		if r == tag {
+			return int32(i), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown rarity %v", tag)
+}
+
+// writeEnchantments writes enchantments by their registry IDs, leaving out
+// those the registry doesn't know.
+func writeEnchantments(w *io.Writer, v any) {
+	e := v.(ItemEnchantments)
+	enchantments := enchantmentRegistry()
+	ids := make([]int32, 0, len(e.Levels))
+	levels := make([]int32, 0, len(e.Levels))
+	for name, level := range e.Levels {
+		if id, ok := enchantments.ID(name); ok {
+			ids = append(ids, id)
+			levels = append(levels, level)
+		}
+	}
+	w.WriteVarInt(int32(len(ids)))
+	for i, id := range ids {
+		w.WriteVarInt(id).WriteVarInt(levels[i])
+	}
+	w.WriteBool(e.ShowInTooltip)
+}
+
+func readEnchantments(r *io.Reader) (any, error) {
+	n := r.ReadVarInt()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	enchantments := enchantmentRegistry()
+	if n < 0 || int(n) > len(enchantments.Entries) {
+		return nil, fmt.Errorf("%d enchantments", n)
+	}
+	e := ItemEnchantments{Levels: make(map[string]int32, n)}
+	for range n {
+		id, level := r.ReadVarInt(), r.ReadVarInt()
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		if id < 0 || int(id) >= len(enchantments.Entries) {
+			return nil, fmt.Errorf("unknown enchantment %d", id)
+		}
+		e.Levels[enchantments.Entries[id].Name] = level
+	}
+	e.ShowInTooltip = r.ReadBool()
+	return e, r.Err()
+}
+
+func enchantmentsToNBT(v any) (any, error) {
+	e := v.(ItemEnchantments)
+	levels := make(nbt.Compound, len(e.Levels))
+	for name, level := range e.Levels {
+		levels[name] = level
+	}
+	c := nbt.Compound{"levels": levels}
+	if !e.ShowInTooltip {
+		c["show_in_tooltip"] = int8(0)
+	}
+	return c, nil
+}
+
+func enchantmentsFromNBT(tag any) (any, error) {
+	c, ok := tag.(nbt.Compound)
+	if !ok {
+		return nil, fmt.Errorf("expected a compound, got %T", tag)
+	}
+	levels, _ := c.Compound("levels")
+	e := ItemEnchantments{Levels: make(map[string]int32, len(levels)), ShowInTooltip: showInTooltip(c)}
+	for name, level := range levels {
+		l, err := intFromNBT(level)
+		if err != nil {
+			return nil, fmt.Errorf("level of %s: %w", name, err)
+		}
+		e.Levels[name] = l.(int32)
+	}
+	return e, nil
+}
+
+func enchantmentRegistry() *registry.Registry {
+	r, _ := registry.Lookup("minecraft:enchantment")
+	return r
+}
+
+func writeDyedColor(w *io.Writer, v any) {
+	c := v.(DyedItemColor)
+	w.WriteInt(c.RGB).WriteBool(c.ShowInTooltip)
+}
+
+func readDyedColor(r *io.Reader) (any, error) {
+	c := DyedItemColor{RGB: r.ReadInt(), ShowInTooltip: r.ReadBool()}
+	return c, r.Err()
+}
+
+func dyedColorToNBT(v any) (any, error) {
+	c := v.(DyedItemColor)
+	tag := nbt.Compound{"rgb": c.RGB}
+	if !c.ShowInTooltip {
+		tag["show_in_tooltip"] = int8(0)
+	}
+	return tag, nil
+}
+
+func dyedColorFromNBT(tag any) (any, error) {
+	switch tag := tag.(type) {
+	case int32:
+		return DyedItemColor{RGB: tag, ShowInTooltip: true}, nil
+	case nbt.Compound:
+		rgb, ok := tag.Int("rgb")
+		if !ok {
+			return nil, errors.New("dyed color without rgb")
+		}
+		return DyedItemColor{RGB: rgb, ShowInTooltip: showInTooltip(tag)}, nil
+	}
+	return nil, fmt.Errorf("expected an int or a compound, got %T", tag)
+}
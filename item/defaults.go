@@ -0,0 +1,153 @@
+package item
+
+import (
+	"strings"
+
+	"github.com/hunterros-s/algernon/text"
+)
+
+// durabilities are how much damage items take before breaking, for the items
+// named after what they are made of.
+var durabilities = map[string]int32{
+	"minecraft:bow":                      384,
+	"minecraft:crossbow":                 465,
+	"minecraft:trident":                  250,
+	"minecraft:mace":                     500,
+	"minecraft:shears":                   238,
+	"minecraft:fishing_rod":              64,
+	"minecraft:flint_and_steel":          64,
+	"minecraft:brush":                    64,
+	"minecraft:shield":                   336,
+	"minecraft:elytra":                   432,
+	"minecraft:turtle_helmet":            275,
+	"minecraft:wolf_armor":               64,
+	"minecraft:carrot_on_a_stick":        25,
+	"minecraft:warped_fungus_on_a_stick": 100,
+}
+
+// toolDurabilities are the durabilities of tools by what they are made of.
+var toolDurabilities = map[string]int32{
+	"wooden":    59,
+	"stone":     131,
+	"iron":      250,
+	"golden":    32,
+	"diamond":   1561,
+	"netherite": 2031,
+}
+
+var tools = []string{"_sword", "_pickaxe", "_axe", "_shovel", "_hoe"}
+
+// armorDurabilities are the durabilities of armor by what it is made of, per
+// unit of the armor piece.
+var armorDurabilities = map[string]int32{
+	"leather":   5,
+	"chainmail": 15,
+	"iron":      15,
+	"golden":    7,
+	"diamond":   33,
+	"netherite": 37,
+}
+
+// armorPieces are the units of durability of each piece of armor.
+var armorPieces = map[string]int32{
+	"_helmet":     11,
+	"_chestplate": 16,
+	"_leggings":   15,
+	"_boots":      13,
+}
+
+// durability returns how much damage an item takes before breaking, 0 for
+// items that don't take damage.
+func durability(name string) int32 {
+	if d, ok := durabilities[name]; ok {
+		return d
+	}
+	material, piece, ok := strings.Cut(strings.TrimPrefix(name, "minecraft:"), "_")
+	if !ok {
+		return 0
+	}
+	piece = "_" + piece
+	for _, tool := range tools {
+		if piece == tool {
+			return toolDurabilities[material]
+		}
+	}
+	if units, ok := armorPieces[piece]; ok {
+		return armorDurabilities[material] * units
+	}
+	return 0
+}
+
+// stacksOf16 are the items stacking up to 16, besides signs and banners.
+var stacksOf16 = map[string]bool{
+	"minecraft:ender_pearl":  true,
+	"minecraft:snowball":     true,
+	"minecraft:egg":          true,
+	"minecraft:bucket":       true,
+	"minecraft:honey_bottle": true,
+	"minecraft:armor_stand":  true,
+	"minecraft:written_book": true,
+}
+
+// unstackable are the items that don't stack, besides those that take damage
+// and those named alike below.
+var unstackable = map[string]bool{
+	"minecraft:potion":           true,
+	"minecraft:splash_potion":    true,
+	"minecraft:lingering_potion": true,
+	"minecraft:milk_bucket":      true,
+	"minecraft:minecart":         true,
+	"minecraft:saddle":           true,
+	"minecraft:cake":             true,
+	"minecraft:enchanted_book":   true,
+	"minecraft:totem_of_undying": true,
+	"minecraft:shulker_box":      true,
+	"minecraft:mushroom_stew":    true,
+	"minecraft:rabbit_stew":      true,
+	"minecraft:beetroot_soup":    true,
+	"minecraft:suspicious_stew":  true,
+	"minecraft:knowledge_book":   true,
+	"minecraft:debug_stick":      true,
+	"minecraft:writable_book":    true,
+	"minecraft:bundle":           true,
+	"minecraft:goat_horn":        true,
+	"minecraft:spyglass":         true,
+}
+
+// unstackableSuffixes name the families of items that don't stack.
+var unstackableSuffixes = []string{
+	"_bucket", "_bed", "_boat", "_raft", "_minecart", "_shulker_box",
+	"_horse_armor", "_banner_pattern",
+}
+
+// stackSize returns how many of an item fit in a slot.
+func stackSize(name string) int32 {
+	switch {
+	case unstackable[name], durability(name) > 0, strings.HasPrefix(name, "minecraft:music_disc_"):
+		return 1
+	case stacksOf16[name], strings.HasSuffix(name, "_sign"), strings.HasSuffix(name, "_banner"):
+		return 16
+	}
+	for _, suffix := range unstackableSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return 1
+		}
+	}
+	return 64
+}
+
+// defaultComponents returns the components every stack of an item has, as far
+// as stacks are modeled.
+func defaultComponents(name string) map[Component]any {
+	components := map[Component]any{
+		MaxStackSize: stackSize(name),
+		Lore:         []text.TextComponent{},
+		Enchantments: ItemEnchantments{ShowInTooltip: true},
+		RepairCost:   int32(0),
+	}
+	if d := durability(name); d > 0 {
+		components[MaxDamage] = d
+		components[Damage] = int32(0)
+	}
+	return components
+}
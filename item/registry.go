@@ -0,0 +1,173 @@
+// Package item holds the items of the game and the stacks of them inventories
+// are made of.
+//
+// https://wiki.vg/Slot_Data
+package item
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// The item registry of the vanilla 1.21 registries.json report, generated with
+// `java -DbundlerMainClass=net.minecraft.data.Main -jar server.jar --reports`.
+//
+//go:embed registries.json
+var bundledReport []byte
+
+// Item is a kind of item.
+type Item struct {
+	Name string
+	ID   int32
+	// defaults are the components every stack of the item has unless it
+	// removes them.
+	defaults map[Component]any
+}
+
+// MaxStackSize returns how many of the item fit in a slot.
+func (i *Item) MaxStackSize() int32 {
+	return i.defaults[MaxStackSize].(int32)
+}
+
+// MaxDamage returns how much damage the item takes before breaking, 0 for
+// items that don't take damage.
+func (i *Item) MaxDamage() int32 {
+	damage, _ := i.defaults[MaxDamage].(int32)
+	return damage
+}
+
+// Registry holds every item of a registries.json report.
+type Registry struct {
+	items map[string]*Item
+	ids   []*Item
+}
+
+type reportRegistry struct {
+	Entries map[string]struct {
+		ProtocolID int32 `json:"protocol_id"`
+	} `json:"entries"`
+}
+
+// LoadReport builds a registry from the item registry of a vanilla
+// registries.json data generator report.
+func LoadReport(r io.Reader) (*Registry, error) {
+	report := map[string]reportRegistry{}
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("error decoding item report: %w", err)
+	}
+	items, ok := report["minecraft:item"]
+	if !ok {
+		return nil, fmt.Errorf("item report has no minecraft:item registry")
+	}
+
+	reg := &Registry{items: make(map[string]*Item, len(items.Entries))}
+	for name, entry := range items.Entries {
+		if entry.ProtocolID < 0 {
+			return nil, fmt.Errorf("item %s has a negative id", name)
+		}
+		for int32(len(reg.ids)) <= entry.ProtocolID {
+			reg.ids = append(reg.ids, nil)
+		}
+		if reg.ids[entry.ProtocolID] != nil {
+			return nil, fmt.Errorf("duplicate item id %d in %s and %s", entry.ProtocolID, reg.ids[entry.ProtocolID].Name, name)
+		}
+		i := &Item{Name: name, ID: entry.ProtocolID, defaults: defaultComponents(name)}
+		reg.items[name] = i
+		reg.ids[entry.ProtocolID] = i
+	}
+	if _, ok := reg.items["minecraft:air"]; !ok {
+		return nil, fmt.Errorf("item report has no minecraft:air")
+	}
+	return reg, nil
+}
+
+// Item returns an item by name. The minecraft namespace is optional.
+func (r *Registry) Item(name string) (*Item, bool) {
+	if !strings.Contains(name, ":") {
+		name = "minecraft:" + name
+	}
+	i, ok := r.items[name]
+	return i, ok
+}
+
+// ByID returns the item with a protocol ID.
+func (r *Registry) ByID(id int32) (*Item, bool) {
+	if id < 0 || int(id) >= len(r.ids) || r.ids[id] == nil {
+		return nil, false
+	}
+	return r.ids[id], true
+}
+
+// Items returns every item, in no particular order.
+func (r *Registry) Items() []*Item {
+	items := make([]*Item, 0, len(r.items))
+	for _, i := range r.items {
+		items = append(items, i)
+	}
+	return items
+}
+
+// Names returns the name of every item, ordered by ID. IDs missing from the
+// report are empty.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.ids))
+	for id, i := range r.ids {
+		if i != nil {
+			names[id] = i.Name
+		}
+	}
+	return names
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+	defaultMutex    sync.RWMutex
+)
+
+// Default returns the registry used by the rest of the server. Until
+// LoadDefault is called it holds the bundled report.
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		reg, err := LoadReport(bytes.NewReader(bundledReport))
+		if err != nil {
+			panic("item: invalid bundled report: " + err.Error())
+		}
+
+		defaultMutex.Lock()
+		if defaultRegistry == nil {
+			defaultRegistry = reg
+		}
+		defaultMutex.Unlock()
+	})
+
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultRegistry
+}
+
+// LoadDefault replaces the default registry with a report read from a file.
+// It should be called before players join.
+func LoadDefault(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reg, err := LoadReport(f)
+	if err != nil {
+		return err
+	}
+
+	defaultMutex.Lock()
+	defaultRegistry = reg
+	defaultMutex.Unlock()
+	return nil
+}
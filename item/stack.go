@@ -0,0 +1,379 @@
+package item
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// maxPatch is how many components a slot may add or remove, to bound what a
+// client makes us read.
+const maxPatch = 256
+
+// removed marks a component removed from the defaults of an item.
+type removed struct{}
+
+// ItemStack is a number of an item sharing the same components. A nil stack
+// is an empty slot.
+type ItemStack struct {
+	Item  *Item
+	Count int32
+
+	// patch holds the components changed from the defaults of the item, with
+	// removed for those taken away.
+	patch map[Component]any
+	// unknown holds the NBT of the components read from NBT that aren't
+	// modeled, kept to be written back.
+	unknown nbt.Compound
+}
+
+var _ entity.Slot = (*ItemStack)(nil)
+
+// NewItemStack returns a stack of count of an item with its default components.
+func NewItemStack(item *Item, count int32) *ItemStack {
+	return &ItemStack{Item: item, Count: count}
+}
+
+// Empty reports whether the stack holds nothing, which air never does.
+func (s *ItemStack) Empty() bool {
+	return s == nil || s.Count <= 0 || s.Item == nil || s.Item.Name == "minecraft:air"
+}
+
+// Clone returns a copy of the stack that changes independently of it.
+func (s *ItemStack) Clone() *ItemStack {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.patch = maps.Clone(s.patch)
+	clone.unknown = maps.Clone(s.unknown)
+	return &clone
+}
+
+// WithCount returns a copy of the stack holding count items.
+func (s *ItemStack) WithCount(count int32) *ItemStack {
+	clone := s.Clone()
+	clone.Count = count
+	return clone
+}
+
+// Stacks reports whether two stacks hold the same item with the same
+// components, and so merge into one.
+func (s *ItemStack) Stacks(other *ItemStack) bool {
+	if s.Empty() || other.Empty() {
+		return false
+	}
+	return s.Item == other.Item && componentsEqual(s.patch, other.patch) && componentsEqual(s.unknown, other.unknown)
+}
+
+func componentsEqual[K comparable](a, b map[K]any) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Get returns the value of a component, from the stack or else from the
+// defaults of its item.
+func (s *ItemStack) Get(c Component) (any, bool) {
+	if v, ok := s.patch[c]; ok {
+		if _, gone := v.(removed); gone {
+			return nil, false
+		}
+		return v, true
+	}
+	v, ok := s.Item.defaults[c]
+	return v, ok
+}
+
+// Has reports whether the stack has a component.
+func (s *ItemStack) Has(c Component) bool {
+	_, ok := s.Get(c)
+	return ok
+}
+
+// Set sets a component to a value of the type its constant documents. Values
+// equal to the default of the item leave the component unchanged.
+func (s *ItemStack) Set(c Component, v any) {
+	if def, ok := s.Item.defaults[c]; ok && reflect.DeepEqual(def, v) {
+		delete(s.patch, c)
+		return
+	}
+	if s.patch == nil {
+		s.patch = make(map[Component]any)
+	}
+	s.patch[c] = v
+}
+
+// Remove removes a component, including one the item has by default.
+func (s *ItemStack) Remove(c Component) {
+	if _, ok := s.Item.defaults[c]; !ok {
+		delete(s.patch, c)
+		return
+	}
+	if s.patch == nil {
+		s.patch = make(map[Component]any)
+	}
+	s.patch[c] = removed{}
+}
+
+// Reset sets a component back to the default of the item.
+func (s *ItemStack) Reset(c Component) {
+	delete(s.patch, c)
+}
+
+// MaxStackSize returns how many items the stack holds at most.
+func (s *ItemStack) MaxStackSize() int32 {
+	if v, ok := s.Get(MaxStackSize); ok {
+		return v.(int32)
+	}
+	return 1
+}
+
+// MaxDamage returns how much damage the stack takes before breaking, 0 when it
+// doesn't take damage.
+func (s *ItemStack) MaxDamage() int32 {
+	v, _ := s.Get(MaxDamage)
+	damage, _ := v.(int32)
+	return damage
+}
+
+// Damage returns how much damage the stack has taken.
+func (s *ItemStack) Damage() int32 {
+	v, _ := s.Get(Damage)
+	damage, _ := v.(int32)
+	return damage
+}
+
+// SetDamage sets how much damage the stack has taken, up to its max damage.
+func (s *ItemStack) SetDamage(damage int32) {
+	s.Set(Damage, max(0, min(damage, s.MaxDamage())))
+}
+
+// CustomName returns the name the stack was given.
+func (s *ItemStack) CustomName() (text.TextComponent, bool) {
+	v, ok := s.Get(CustomName)
+	if !ok {
+		return text.TextComponent{}, false
+	}
+	return v.(text.TextComponent), true
+}
+
+// SetCustomName names the stack.
+func (s *ItemStack) SetCustomName(name text.TextComponent) {
+	s.Set(CustomName, name)
+}
+
+// Lore returns the lines shown under the name of the stack.
+func (s *ItemStack) Lore() []text.TextComponent {
+	v, _ := s.Get(Lore)
+	lore, _ := v.([]text.TextComponent)
+	return lore
+}
+
+// SetLore sets the lines shown under the name of the stack.
+func (s *ItemStack) SetLore(lore []text.TextComponent) {
+	s.Set(Lore, lore)
+}
+
+// Enchantments returns the enchantments of the stack by name.
+func (s *ItemStack) Enchantments() map[string]int32 {
+	v, _ := s.Get(Enchantments)
+	e, _ := v.(ItemEnchantments)
+	return e.Levels
+}
+
+// SetEnchantment enchants the stack, or takes the enchantment away with level
+// 0.
+func (s *ItemStack) SetEnchantment(name string, level int32) {
+	v, ok := s.Get(Enchantments)
+	e, _ := v.(ItemEnchantments)
+	if !ok {
+		e.ShowInTooltip = true
+	}
+	e.Levels = maps.Clone(e.Levels)
+	if level > 0 {
+		if e.Levels == nil {
+			e.Levels = make(map[string]int32)
+		}
+		e.Levels[name] = level
+	} else {
+		delete(e.Levels, name)
+	}
+	if len(e.Levels) == 0 {
+		e.Levels = nil
+	}
+	s.Set(Enchantments, e)
+}
+
+// CustomModelData returns the number resource packs pick the model of the
+// stack by.
+func (s *ItemStack) CustomModelData() (int32, bool) {
+	v, ok := s.Get(CustomModelData)
+	if !ok {
+		return 0, false
+	}
+	return v.(int32), true
+}
+
+// SetCustomModelData sets the number resource packs pick the model of the
+// stack by.
+func (s *ItemStack) SetCustomModelData(data int32) {
+	s.Set(CustomModelData, data)
+}
+
+// WriteSlot writes the stack as a Slot, with its components as a patch of
+// those of its item. Components only kept from NBT aren't sent.
+//
+// https://wiki.vg/Slot_Data
+func (s *ItemStack) WriteSlot(w *io.Writer) {
+	if s.Empty() {
+		w.WriteVarInt(0)
+		return
+	}
+	w.WriteVarInt(s.Count).WriteVarInt(s.Item.ID)
+
+	var added, gone []Component
+	for c, v := range s.patch {
+		if _, ok := v.(removed); ok {
+			gone = append(gone, c)
+		} else {
+			added = append(added, c)
+		}
+	}
+	w.WriteVarInt(int32(len(added))).WriteVarInt(int32(len(gone)))
+	for _, c := range added {
+		w.WriteVarInt(int32(c))
+		codecs[c].write(w, s.patch[c])
+	}
+	for _, c := range gone {
+		w.WriteVarInt(int32(c))
+	}
+}
+
+// ErrUnsupportedComponent is returned by ReadSlot for slots with components
+// that aren't modeled. Their length isn't known, so nothing after them in the
+// packet can be read either.
+var ErrUnsupportedComponent = errors.New("unsupported component")
+
+// ReadSlot reads a Slot, returning nil for an empty one. Slots with components
+// that aren't modeled can't be read, see ErrUnsupportedComponent.
+func ReadSlot(r *io.Reader) (*ItemStack, error) {
+	count := r.ReadVarInt()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	if count <= 0 {
+		return nil, nil
+	}
+
+	id := r.ReadVarInt()
+	added, gone := r.ReadVarInt(), r.ReadVarInt()
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	item, ok := Default().ByID(id)
+	if !ok {
+		return nil, fmt.Errorf("unknown item %d", id)
+	}
+	if added < 0 || gone < 0 || added+gone > maxPatch {
+		return nil, fmt.Errorf("slot patches %d components", added+gone)
+	}
+
+	s := NewItemStack(item, count)
+	for range added {
+		c := Component(r.ReadVarInt())
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		codec, ok := codecs[c]
+		if !ok {
+			return nil, fmt.Errorf("%w %d", ErrUnsupportedComponent, int32(c))
+		}
+		v, err := codec.read(r)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", c, err)
+		}
+		s.Set(c, v)
+	}
+	for range gone {
+		c := Component(r.ReadVarInt())
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		s.Remove(c)
+	}
+	return s, nil
+}
+
+// ToNBT returns the stack as vanilla stores it, with its components as a patch
+// of those of its item and removed ones prefixed with !.
+func (s *ItemStack) ToNBT() (nbt.Compound, error) {
+	c := nbt.Compound{"id": s.Item.Name, "count": s.Count}
+	components := maps.Clone(s.unknown)
+	if components == nil {
+		components = nbt.Compound{}
+	}
+	for component, v := range s.patch {
+		codec := codecs[component]
+		if _, ok := v.(removed); ok {
+			components["!"+codec.name] = nbt.Compound{}
+			continue
+		}
+		tag, err := codec.toNBT(v)
+		if err != nil {
+			return nil, fmt.Errorf("error converting %s: %w", component, err)
+		}
+		components[codec.name] = tag
+	}
+	if len(components) > 0 {
+		c["components"] = components
+	}
+	return c, nil
+}
+
+// FromNBT reads a stack as vanilla stores it. Components that aren't modeled
+// are kept as they are to be stored again.
+func FromNBT(c nbt.Compound) (*ItemStack, error) {
+	name, _ := c.String("id")
+	item, ok := Default().Item(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown item %q", name)
+	}
+	count, ok := c.Int("count")
+	if !ok {
+		count = 1
+	}
+
+	s := NewItemStack(item, count)
+	components, _ := c.Compound("components")
+	for key, tag := range components {
+		name, remove := key, false
+		if key != "" && key[0] == '!' {
+			name, remove = key[1:], true
+		}
+		component, ok := componentsByName[name]
+		switch {
+		case !ok:
+			if s.unknown == nil {
+				s.unknown = nbt.Compound{}
+			}
+			s.unknown[key] = tag
+		case remove:
+			s.Remove(component)
+		default:
+			v, err := codecs[component].fromNBT(tag)
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s: %w", name, err)
+			}
+			s.Set(component, v)
+		}
+	}
+	return s, nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/hunterros-s/algernon/text"
 	"github.com/rs/zerolog"
 )
 
@@ -14,7 +15,7 @@ func NewLogger() zerolog.Logger {
 		TimeFormat: time.RFC3339,
 	}
 
-	if os.Getenv("GO_ENV") != "development" {
+	if !isConsole() {
 		output = os.Stderr
 	}
 
@@ -27,3 +28,16 @@ func NewLogger() zerolog.Logger {
 
 	return logger
 }
+
+// Text renders a player-visible message (chat, disconnect reasons, ...) for
+// logging. Colors are kept on the console and stripped from structured output.
+func Text(comp text.TextComponent) string {
+	if isConsole() {
+		return text.ANSI(comp, text.TrueColor)
+	}
+	return text.PlainText(comp)
+}
+
+func isConsole() bool {
+	return os.Getenv("GO_ENV") == "development"
+}
@@ -0,0 +1,181 @@
+package nbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Unmarshal decodes a named root compound, as stored in files.
+func Unmarshal(data []byte) (string, Compound, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+// Decode reads a named root compound from r.
+func Decode(r io.Reader) (string, Compound, error) {
+	d := decoder{r: r}
+	if typ := d.byte(); d.err == nil && typ != TagCompound {
+		return "", nil, fmt.Errorf("nbt: root tag must be a compound, got type %d", typ)
+	}
+	name := d.string()
+	c, _ := d.payload(TagCompound, 0).(Compound)
+	if d.err != nil {
+		return "", nil, d.err
+	}
+	return name, c, nil
+}
+
+// DecodeNetwork reads a nameless root tag from r, as sent over the network.
+// An empty root (TAG_End) decodes to nil.
+func DecodeNetwork(r io.Reader) (any, error) {
+	d := decoder{r: r}
+	typ := d.byte()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if typ == TagEnd {
+		return nil, nil
+	}
+	v := d.payload(typ, 0)
+	if d.err != nil {
+		return nil, d.err
+	}
+	return v, nil
+}
+
+type decoder struct {
+	r   io.Reader
+	err error
+}
+
+// maxRead bounds a single array or string so a corrupt length can't make us
+// allocate gigabytes.
+const maxRead = 16 << 20
+
+func (d *decoder) read(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n > maxRead {
+		d.err = fmt.Errorf("nbt: length %d exceeds limit", n)
+		return nil
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(d.r, b); err != nil {
+		d.err = fmt.Errorf("nbt: %w", err)
+		return nil
+	}
+	return b
+}
+
+func (d *decoder) byte() byte {
+	b := d.read(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *decoder) short() int16 {
+	b := d.read(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *decoder) int() int32 {
+	b := d.read(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *decoder) long() int64 {
+	b := d.read(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *decoder) string() string {
+	n := uint16(d.short())
+	return string(d.read(int(n)))
+}
+
+func (d *decoder) length() int {
+	n := d.int()
+	if n < 0 && d.err == nil {
+		d.err = errors.New("nbt: negative length")
+	}
+	return int(n)
+}
+
+func (d *decoder) payload(typ byte, depth int) any {
+	if depth > maxDepth {
+		d.err = errors.New("nbt: maximum depth exceeded")
+		return nil
+	}
+
+	switch typ {
+	case TagByte:
+		return int8(d.byte())
+	case TagShort:
+		return d.short()
+	case TagInt:
+		return d.int()
+	case TagLong:
+		return d.long()
+	case TagFloat:
+		return math.Float32frombits(uint32(d.int()))
+	case TagDouble:
+		return math.Float64frombits(uint64(d.long()))
+	case TagByteArray:
+		return d.read(d.length())
+	case TagString:
+		return d.string()
+	case TagList:
+		elemType := d.byte()
+		n := d.length()
+		list := make(List, 0, min(n, 1024))
+		for i := 0; i < n && d.err == nil; i++ {
+			list = append(list, d.payload(elemType, depth+1))
+		}
+		return list
+	case TagCompound:
+		c := Compound{}
+		for d.err == nil {
+			childType := d.byte()
+			if childType == TagEnd {
+				break
+			}
+			name := d.string()
+			c[name] = d.payload(childType, depth+1)
+		}
+		return c
+	case TagIntArray:
+		n := d.length()
+		arr := make([]int32, 0, min(n, 1024))
+		for i := 0; i < n && d.err == nil; i++ {
+			arr = append(arr, d.int())
+		}
+		return arr
+	case TagLongArray:
+		n := d.length()
+		arr := make([]int64, 0, min(n, 1024))
+		for i := 0; i < n && d.err == nil; i++ {
+			arr = append(arr, d.long())
+		}
+		return arr
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("nbt: unknown tag type %d", typ)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package nbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// Marshal encodes a root compound with a name, as stored in files.
+func Marshal(name string, c Compound) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, name, c); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalNetwork encodes a nameless root tag, as sent over the network since
+// 1.20.2. The root is usually a compound but may be any tag, text components
+// for example are sent as a bare string when they have no style.
+func MarshalNetwork(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := EncodeNetwork(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Encode writes a named root compound to w.
+func Encode(w io.Writer, name string, c Compound) error {
+	e := encoder{w: w}
+	e.byte(TagCompound)
+	e.string(name)
+	e.payload(c)
+	return e.err
+}
+
+// EncodeNetwork writes a nameless root tag to w.
+func EncodeNetwork(w io.Writer, v any) error {
+	typ, err := tagType(v)
+	if err != nil {
+		return err
+	}
+	e := encoder{w: w}
+	e.byte(typ)
+	e.payload(v)
+	return e.err
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) write(b []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(b)
+}
+
+func (e *encoder) byte(b byte) {
+	e.write([]byte{b})
+}
+
+func (e *encoder) short(i int16) {
+	e.write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+}
+
+func (e *encoder) int(i int32) {
+	e.write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+}
+
+func (e *encoder) long(i int64) {
+	e.write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+}
+
+// string writes a length prefixed string. NBT uses Java's modified UTF-8,
+// which only differs from UTF-8 for NUL and supplementary characters.
+func (e *encoder) string(s string) {
+	if len(s) > math.MaxUint16 {
+		e.fail(fmt.Errorf("nbt: string of %d bytes is too long", len(s)))
+		return
+	}
+	e.write(binary.BigEndian.AppendUint16(nil, uint16(len(s))))
+	e.write([]byte(s))
+}
+
+func (e *encoder) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *encoder) payload(v any) {
+	switch v := v.(type) {
+	case int8:
+		e.byte(byte(v))
+	case uint8:
+		e.byte(v)
+	case bool:
+		if v {
+			e.byte(1)
+		} else {
+			e.byte(0)
+		}
+	case int16:
+		e.short(v)
+	case int32:
+		e.int(v)
+	case int:
+		e.int(int32(v))
+	case int64:
+		e.long(v)
+	case float32:
+		e.int(int32(math.Float32bits(v)))
+	case float64:
+		e.long(int64(math.Float64bits(v)))
+	case []byte:
+		e.int(int32(len(v)))
+		e.write(v)
+	case string:
+		e.string(v)
+	case List:
+		e.list([]any(v))
+	case []any:
+		e.list(v)
+	case []string:
+		list := make([]any, len(v))
+		for i, s := range v {
+			list[i] = s
+		}
+		e.list(list)
+	case []Compound:
+		list := make([]any, len(v))
+		for i, c := range v {
+			list[i] = c
+		}
+		e.list(list)
+	case Compound:
+		e.compound(v)
+	case map[string]any:
+		e.compound(v)
+	case []int32:
+		e.int(int32(len(v)))
+		for _, i := range v {
+			e.int(i)
+		}
+	case []int64:
+		e.int(int32(len(v)))
+		for _, i := range v {
+			e.long(i)
+		}
+	default:
+		e.fail(fmt.Errorf("nbt: unsupported type %T", v))
+	}
+}
+
+func (e *encoder) list(list []any) {
+	elemType := TagEnd
+	if len(list) > 0 {
+		typ, err := tagType(list[0])
+		if err != nil {
+			e.fail(err)
+			return
+		}
+		elemType = typ
+	}
+
+	e.byte(elemType)
+	e.int(int32(len(list)))
+	for _, v := range list {
+		if typ, err := tagType(v); err != nil || typ != elemType {
+			e.fail(fmt.Errorf("nbt: list elements must all be the same type, got %T", v))
+			return
+		}
+		e.payload(v)
+	}
+}
+
+func (e *encoder) compound(c map[string]any) {
+	// sorted so the same compound always encodes to the same bytes
+	names := make([]string, 0, len(c))
+	for name := range c {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		v := c[name]
+		typ, err := tagType(v)
+		if err != nil {
+			e.fail(fmt.Errorf("nbt: %s: %w", name, err))
+			return
+		}
+		e.byte(typ)
+		e.string(name)
+		e.payload(v)
+	}
+	e.byte(TagEnd)
+}
@@ -0,0 +1,163 @@
+// Package nbt implements Minecraft's Named Binary Tag format.
+//
+// https://wiki.vg/NBT
+//
+// Tags are represented by plain Go values: int8, int16, int32, int64, float32,
+// float64, []byte, string, List, Compound, []int32 and []int64. Encoding also
+// accepts bool, uint8, int and []string for convenience.
+package nbt
+
+import "fmt"
+
+// Tag types
+const (
+	TagEnd byte = iota
+	TagByte
+	TagShort
+	TagInt
+	TagLong
+	TagFloat
+	TagDouble
+	TagByteArray
+	TagString
+	TagList
+	TagCompound
+	TagIntArray
+	TagLongArray
+)
+
+// maxDepth is the nesting limit vanilla enforces when reading.
+const maxDepth = 512
+
+// Compound is a TAG_Compound.
+type Compound map[string]any
+
+// List is a TAG_List. Every element must have the same tag type.
+type List []any
+
+// Byte returns a byte (or boolean) tag.
+func (c Compound) Byte(key string) (int8, bool) {
+	v, ok := c[key].(int8)
+	return v, ok
+}
+
+// Bool returns a byte tag as a boolean.
+func (c Compound) Bool(key string) bool {
+	v, _ := c[key].(int8)
+	return v != 0
+}
+
+// Short returns a short tag.
+func (c Compound) Short(key string) (int16, bool) {
+	v, ok := c[key].(int16)
+	return v, ok
+}
+
+// Int returns an int tag.
+func (c Compound) Int(key string) (int32, bool) {
+	v, ok := c[key].(int32)
+	return v, ok
+}
+
+// Long returns a long tag.
+func (c Compound) Long(key string) (int64, bool) {
+	v, ok := c[key].(int64)
+	return v, ok
+}
+
+// Float returns a float tag.
+func (c Compound) Float(key string) (float32, bool) {
+	v, ok := c[key].(float32)
+	return v, ok
+}
+
+// Double returns a double tag.
+func (c Compound) Double(key string) (float64, bool) {
+	v, ok := c[key].(float64)
+	return v, ok
+}
+
+// String returns a string tag.
+func (c Compound) String(key string) (string, bool) {
+	v, ok := c[key].(string)
+	return v, ok
+}
+
+// ByteArray returns a byte array tag.
+func (c Compound) ByteArray(key string) ([]byte, bool) {
+	v, ok := c[key].([]byte)
+	return v, ok
+}
+
+// IntArray returns an int array tag.
+func (c Compound) IntArray(key string) ([]int32, bool) {
+	v, ok := c[key].([]int32)
+	return v, ok
+}
+
+// LongArray returns a long array tag.
+func (c Compound) LongArray(key string) ([]int64, bool) {
+	v, ok := c[key].([]int64)
+	return v, ok
+}
+
+// List returns a list tag.
+func (c Compound) List(key string) (List, bool) {
+	v, ok := c[key].(List)
+	return v, ok
+}
+
+// Compound returns a nested compound tag.
+func (c Compound) Compound(key string) (Compound, bool) {
+	v, ok := c[key].(Compound)
+	return v, ok
+}
+
+// Compounds returns a list tag whose elements are compounds.
+func (c Compound) Compounds(key string) ([]Compound, bool) {
+	list, ok := c.List(key)
+	if !ok {
+		return nil, false
+	}
+	compounds := make([]Compound, 0, len(list))
+	for _, v := range list {
+		compound, ok := v.(Compound)
+		if !ok {
+			return nil, false
+		}
+		compounds = append(compounds, compound)
+	}
+	return compounds, true
+}
+
+// tagType returns the tag type a Go value encodes as.
+func tagType(v any) (byte, error) {
+	switch v.(type) {
+	case int8, uint8, bool:
+		return TagByte, nil
+	case int16:
+		return TagShort, nil
+	case int32, int:
+		return TagInt, nil
+	case int64:
+		return TagLong, nil
+	case float32:
+		return TagFloat, nil
+	case float64:
+		return TagDouble, nil
+	case []byte:
+		return TagByteArray, nil
+	case string:
+		return TagString, nil
+	case List, []any, []string, []Compound:
+		return TagList, nil
+	case Compound, map[string]any:
+		return TagCompound, nil
+	case []int32:
+		return TagIntArray, nil
+	case []int64:
+		return TagLongArray, nil
+	default:
+		return 0, fmt.Errorf("nbt: unsupported type %T", v)
+	}
+}
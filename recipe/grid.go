@@ -0,0 +1,82 @@
+package recipe
+
+import "github.com/hunterros-s/algernon/item"
+
+// Grid is the items in a crafting grid, row by row.
+type Grid struct {
+	Width  int
+	Height int
+	Slots  []*item.ItemStack
+}
+
+// At returns the stack in a slot of the grid.
+func (g Grid) At(x, y int) *item.ItemStack {
+	return g.Slots[y*g.Width+x]
+}
+
+// Empty reports whether the grid holds nothing.
+func (g Grid) Empty() bool {
+	for _, s := range g.Slots {
+		if !s.Empty() {
+			return false
+		}
+	}
+	return true
+}
+
+// trim returns the smallest part of the grid holding all of its items, as
+// shaped recipes match anywhere in it.
+func (g Grid) trim() Grid {
+	minX, minY, maxX, maxY := bounds(g.Width, g.Height, func(i int) bool { return !g.Slots[i].Empty() })
+	if maxX < minX {
+		return Grid{}
+	}
+	t := Grid{Width: maxX - minX + 1, Height: maxY - minY + 1}
+	for y := minY; y <= maxY; y++ {
+		t.Slots = append(t.Slots, g.Slots[y*g.Width+minX:y*g.Width+maxX+1]...)
+	}
+	return t
+}
+
+// bounds returns the corners of the smallest rectangle of cells of a grid
+// holding all of those filled, with maxX below minX when none are.
+func bounds(width, height int, filled func(i int) bool) (minX, minY, maxX, maxY int) {
+	minX, minY, maxX, maxY = width, height, -1, -1
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if filled(y*width + x) {
+				minX, maxX = min(minX, x), max(maxX, x)
+				minY, maxY = min(minY, y), max(maxY, y)
+			}
+		}
+	}
+	return minX, minY, maxX, maxY
+}
+
+// remainders are what is left in the grid of the items that aren't used up by
+// crafting.
+var remainders = map[string]string{
+	"minecraft:water_bucket":       "minecraft:bucket",
+	"minecraft:lava_bucket":        "minecraft:bucket",
+	"minecraft:milk_bucket":        "minecraft:bucket",
+	"minecraft:powder_snow_bucket": "minecraft:bucket",
+	"minecraft:honey_bottle":       "minecraft:glass_bottle",
+	"minecraft:dragon_breath":      "minecraft:glass_bottle",
+}
+
+// Remainder returns what is left of a stack used in crafting, nil when it is
+// used up.
+func Remainder(s *item.ItemStack) *item.ItemStack {
+	if s.Empty() {
+		return nil
+	}
+	name, ok := remainders[s.Item.Name]
+	if !ok {
+		return nil
+	}
+	i, ok := item.Default().Item(name)
+	if !ok {
+		return nil
+	}
+	return item.NewItemStack(i, 1)
+}
@@ -0,0 +1,316 @@
+package recipe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/hunterros-s/algernon/item"
+)
+
+// errUnsupported marks recipes that can't be loaded with the items and
+// serializers the server has, which are skipped rather than failing the load.
+var errUnsupported = errors.New("unsupported recipe")
+
+// Cooking times of recipes that don't give one, in ticks
+const (
+	defaultSmeltingTime = 200
+	defaultCookingTime  = 100
+)
+
+// maxTagDepth is how deep tags may refer to other tags.
+const maxTagDepth = 16
+
+// jsonRecipe is a recipe as data packs hold it.
+//
+// https://minecraft.wiki/w/Recipe#JSON_format
+type jsonRecipe struct {
+	Type             string                     `json:"type"`
+	Group            string                     `json:"group"`
+	Category         string                     `json:"category"`
+	Pattern          []string                   `json:"pattern"`
+	Key              map[string]json.RawMessage `json:"key"`
+	Ingredients      []json.RawMessage          `json:"ingredients"`
+	Ingredient       json.RawMessage            `json:"ingredient"`
+	Result           json.RawMessage            `json:"result"`
+	Experience       float32                    `json:"experience"`
+	CookingTime      *int32                     `json:"cookingtime"`
+	ShowNotification *bool                      `json:"show_notification"`
+}
+
+type jsonResult struct {
+	ID         string          `json:"id"`
+	Count      *int32          `json:"count"`
+	Components json.RawMessage `json:"components"`
+}
+
+type jsonIngredient struct {
+	Item string `json:"item"`
+	Tag  string `json:"tag"`
+}
+
+type jsonTag struct {
+	Values []json.RawMessage `json:"values"`
+}
+
+// loader loads the recipes of a data pack, resolving the item tags they use.
+type loader struct {
+	fsys fs.FS
+	// tags are the entries of the item tags of the data pack by name, with
+	// the names of other tags prefixed with #
+	tags map[string][]string
+}
+
+// LoadFS adds the recipes of a data pack, found in data/<namespace>/recipe,
+// replacing those of the same ID. The item tags they use are read from
+// data/<namespace>/tags/item. It returns how many recipes were skipped for
+// using items the item registry doesn't have or unsupported serializers.
+func (r *Registry) LoadFS(fsys fs.FS) (skipped int, err error) {
+	l := &loader{fsys: fsys, tags: make(map[string][]string)}
+	if err := l.walk("tags/item", l.loadTag); err != nil {
+		return 0, err
+	}
+	err = l.walk("recipe", func(id string, data []byte) error {
+		rec, err := l.parse(id, data)
+		if errors.Is(err, errUnsupported) {
+			skipped++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.Register(rec)
+		return nil
+	})
+	return skipped, err
+}
+
+// walk calls f with the ID and contents of every JSON file of a kind in the
+// namespaces of the data pack.
+func (l *loader) walk(kind string, f func(id string, data []byte) error) error {
+	namespaces, err := fs.ReadDir(l.fsys, "data")
+	if err != nil {
+		return fmt.Errorf("error reading data pack: %w", err)
+	}
+	for _, ns := range namespaces {
+		if !ns.IsDir() {
+			continue
+		}
+		root := path.Join("data", ns.Name(), kind)
+		err := fs.WalkDir(l.fsys, root, func(p string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && p == root {
+				return fs.SkipDir
+			}
+			if err != nil || d.IsDir() || !strings.HasSuffix(p, ".json") {
+				return err
+			}
+			data, err := fs.ReadFile(l.fsys, p)
+			if err != nil {
+				return err
+			}
+			id := ns.Name() + ":" + strings.TrimSuffix(strings.TrimPrefix(p, root+"/"), ".json")
+			if err := f(id, data); err != nil {
+				return fmt.Errorf("error loading %s: %w", p, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *loader) loadTag(id string, data []byte) error {
+	var tag jsonTag
+	if err := json.Unmarshal(data, &tag); err != nil {
+		return err
+	}
+	for _, raw := range tag.Values {
+		// entries are names, or objects naming optional entries
+		var entry string
+		if err := json.Unmarshal(raw, &entry); err != nil {
+			var optional struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &optional); err != nil {
+				return err
+			}
+			entry = optional.ID
+		}
+		l.tags[id] = append(l.tags[id], entry)
+	}
+	return nil
+}
+
+// tag returns the items of an item tag the item registry has.
+func (l *loader) tag(name string, depth int) ([]*item.Item, error) {
+	entries, ok := l.tags[withNamespace(name)]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown item tag %s", errUnsupported, name)
+	}
+	if depth > maxTagDepth {
+		return nil, fmt.Errorf("item tag %s nests too deep", name)
+	}
+	var items []*item.Item
+	for _, e := range entries {
+		if nested, ok := strings.CutPrefix(e, "#"); ok {
+			more, err := l.tag(nested, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, more...)
+		} else if i, ok := item.Default().Item(e); ok {
+			items = append(items, i)
+		}
+	}
+	return items, nil
+}
+
+func (l *loader) parse(id string, data []byte) (Recipe, error) {
+	var j jsonRecipe
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	kind := withNamespace(j.Type)
+	if _, ok := serializerIDs[kind]; !ok {
+		return nil, fmt.Errorf("%w: serializer %s", errUnsupported, kind)
+	}
+	result, err := l.result(j.Result)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case CraftingShaped:
+		key := make(map[rune]Ingredient, len(j.Key))
+		for k, raw := range j.Key {
+			runes := []rune(k)
+			if len(runes) != 1 || k == " " {
+				return nil, fmt.Errorf("invalid key %q", k)
+			}
+			in, err := l.ingredient(raw)
+			if err != nil {
+				return nil, err
+			}
+			key[runes[0]] = in
+		}
+		rec, err := newShaped(id, j.Pattern, key)
+		if err != nil {
+			return nil, err
+		}
+		rec.Group, rec.Result = j.Group, result
+		rec.Category = craftingCategories[j.Category]
+		rec.ShowNotification = j.ShowNotification == nil || *j.ShowNotification
+		return rec, nil
+
+	case CraftingShapeless:
+		if len(j.Ingredients) == 0 || len(j.Ingredients) > 9 {
+			return nil, fmt.Errorf("%d ingredients", len(j.Ingredients))
+		}
+		rec := &Shapeless{Name: id, Group: j.Group, Category: craftingCategories[j.Category], Result: result}
+		for _, raw := range j.Ingredients {
+			in, err := l.ingredient(raw)
+			if err != nil {
+				return nil, err
+			}
+			rec.Ingredients = append(rec.Ingredients, in)
+		}
+		return rec, nil
+
+	case Stonecutting:
+		in, err := l.ingredient(j.Ingredient)
+		if err != nil {
+			return nil, err
+		}
+		return &Stonecutter{Name: id, Group: j.Group, Ingredient: in, Result: result}, nil
+	}
+
+	in, err := l.ingredient(j.Ingredient)
+	if err != nil {
+		return nil, err
+	}
+	rec := &Cooking{
+		Name:        id,
+		Kind:        kind,
+		Group:       j.Group,
+		Category:    CookingMisc,
+		Ingredient:  in,
+		Result:      result,
+		Experience:  j.Experience,
+		CookingTime: defaultCookingTime,
+	}
+	if c, ok := cookingCategories[j.Category]; ok {
+		rec.Category = c
+	}
+	if kind == Smelting {
+		rec.CookingTime = defaultSmeltingTime
+	}
+	if j.CookingTime != nil {
+		rec.CookingTime = *j.CookingTime
+	}
+	return rec, nil
+}
+
+// ingredient reads an ingredient: an item, an item tag, or a list of them.
+func (l *loader) ingredient(raw json.RawMessage) (Ingredient, error) {
+	var list []jsonIngredient
+	if err := json.Unmarshal(raw, &list); err != nil {
+		var single jsonIngredient
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, fmt.Errorf("invalid ingredient: %w", err)
+		}
+		list = []jsonIngredient{single}
+	}
+
+	var in Ingredient
+	for _, e := range list {
+		switch {
+		case e.Item != "":
+			if i, ok := item.Default().Item(e.Item); ok {
+				in = append(in, i)
+			}
+		case e.Tag != "":
+			items, err := l.tag(e.Tag, 0)
+			if err != nil {
+				return nil, err
+			}
+			in = append(in, items...)
+		default:
+			return nil, fmt.Errorf("ingredient has neither item nor tag")
+		}
+	}
+	if len(in) == 0 {
+		return nil, fmt.Errorf("%w: no known item for ingredient", errUnsupported)
+	}
+	return in, nil
+}
+
+// result reads the stack a recipe makes. Results with components aren't
+// supported.
+func (l *loader) result(raw json.RawMessage) (*item.ItemStack, error) {
+	var r jsonResult
+	if err := json.Unmarshal(raw, &r.ID); err != nil {
+		if err := json.Unmarshal(raw, &r); err != nil {
+			return nil, fmt.Errorf("invalid result: %w", err)
+		}
+	}
+	if len(r.Components) > 0 {
+		return nil, fmt.Errorf("%w: result with components", errUnsupported)
+	}
+	i, ok := item.Default().Item(r.ID)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown item %s", errUnsupported, r.ID)
+	}
+	count := int32(1)
+	if r.Count != nil {
+		count = *r.Count
+	}
+	if count <= 0 || count > i.MaxStackSize() {
+		return nil, fmt.Errorf("result of %d %s", count, r.ID)
+	}
+	return item.NewItemStack(i, count), nil
+}
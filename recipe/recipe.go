@@ -0,0 +1,383 @@
+// Package recipe holds the recipes items are crafted, cooked and cut with,
+// loaded from data packs or registered by plugins, and finds the recipe a
+// crafting grid makes.
+//
+// Plugins add recipes with Default().Register before players join, building
+// crafting recipes with NewShaped or as Shapeless.
+//
+// https://wiki.vg/Protocol#Update_Recipes
+package recipe
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+)
+
+// Recipe serializers, which the client reads the data of recipes by
+const (
+	CraftingShaped    = "minecraft:crafting_shaped"
+	CraftingShapeless = "minecraft:crafting_shapeless"
+	Smelting          = "minecraft:smelting"
+	Blasting          = "minecraft:blasting"
+	Smoking           = "minecraft:smoking"
+	CampfireCooking   = "minecraft:campfire_cooking"
+	Stonecutting      = "minecraft:stonecutting"
+)
+
+// serializerIDs are the IDs of the recipe serializers in the 1.21
+// recipe_serializer registry.
+var serializerIDs = map[string]int32{
+	CraftingShaped:    0,
+	CraftingShapeless: 1,
+	Smelting:          15,
+	Blasting:          16,
+	Smoking:           17,
+	CampfireCooking:   18,
+	Stonecutting:      19,
+}
+
+// CraftingCategory is the tab of the recipe book crafting recipes are shown
+// under.
+type CraftingCategory int32
+
+const (
+	CraftingBuilding CraftingCategory = iota
+	CraftingRedstone
+	CraftingEquipment
+	CraftingMisc
+)
+
+var craftingCategories = map[string]CraftingCategory{
+	"building":  CraftingBuilding,
+	"redstone":  CraftingRedstone,
+	"equipment": CraftingEquipment,
+	"misc":      CraftingMisc,
+}
+
+// CookingCategory is the tab of the recipe book cooking recipes are shown
+// under.
+type CookingCategory int32
+
+const (
+	CookingFood CookingCategory = iota
+	CookingBlocks
+	CookingMisc
+)
+
+var cookingCategories = map[string]CookingCategory{
+	"food":   CookingFood,
+	"blocks": CookingBlocks,
+	"misc":   CookingMisc,
+}
+
+// Recipe is a way of making an item.
+type Recipe interface {
+	// ID returns the name of the recipe, like minecraft:polished_granite.
+	ID() string
+	// Serializer returns the kind of recipe the client reads it as.
+	Serializer() string
+	// WriteData writes the recipe as Update Recipes sends it after its ID and
+	// serializer.
+	WriteData(w *io.Writer)
+}
+
+// CraftingRecipe is a recipe of the crafting grid.
+type CraftingRecipe interface {
+	Recipe
+	// Matches reports whether the items in a grid make the recipe.
+	Matches(g Grid) bool
+	// Assemble returns what crafting a grid matching the recipe makes.
+	Assemble(g Grid) *item.ItemStack
+	// Layout returns the ingredients the recipe book puts in a grid of a width
+	// row by row, nil when they don't fit.
+	Layout(width, height int) []Ingredient
+}
+
+// Ingredient is the items one of which goes in a slot of a recipe. The empty
+// ingredient is an empty slot.
+type Ingredient []*item.Item
+
+// Test reports whether a stack may be used for the ingredient, the empty
+// ingredient taking only empty stacks.
+func (in Ingredient) Test(s *item.ItemStack) bool {
+	if len(in) == 0 {
+		return s.Empty()
+	}
+	return !s.Empty() && slices.Contains(in, s.Item)
+}
+
+func (in Ingredient) write(w *io.Writer) {
+	w.WriteVarInt(int32(len(in)))
+	for _, i := range in {
+		item.NewItemStack(i, 1).WriteSlot(w)
+	}
+}
+
+// Shaped is a crafting recipe with its ingredients in a pattern, which may be
+// mirrored.
+type Shaped struct {
+	Name     string
+	Group    string
+	Category CraftingCategory
+	Width    int
+	Height   int
+	// Pattern is the ingredients row by row.
+	Pattern []Ingredient
+	Result  *item.ItemStack
+	// ShowNotification is whether unlocking the recipe shows a toast.
+	ShowNotification bool
+}
+
+var _ CraftingRecipe = (*Shaped)(nil)
+
+// NewShaped returns a shaped recipe with a pattern written as in data packs:
+// a string per row, with a rune of key per ingredient and spaces for empty
+// slots. Empty rows and columns around the pattern are removed.
+func NewShaped(name string, result *item.ItemStack, pattern []string, key map[rune]Ingredient) *Shaped {
+	r, err := newShaped(name, pattern, key)
+	if err != nil {
+		panic("recipe: " + err.Error())
+	}
+	r.Result = result
+	r.ShowNotification = true
+	return r
+}
+
+func newShaped(name string, pattern []string, key map[rune]Ingredient) (*Shaped, error) {
+	if len(pattern) == 0 || len(pattern) > 3 {
+		return nil, fmt.Errorf("pattern of %s has %d rows", name, len(pattern))
+	}
+	width := len([]rune(pattern[0]))
+	cells := make([]Ingredient, 0, width*len(pattern))
+	for _, row := range pattern {
+		runes := []rune(row)
+		if len(runes) != width || width == 0 || width > 3 {
+			return nil, fmt.Errorf("pattern of %s has rows of different or invalid widths", name)
+		}
+		for _, c := range runes {
+			in, ok := key[c]
+			if !ok && c != ' ' {
+				return nil, fmt.Errorf("pattern of %s uses %q, which isn't in its key", name, c)
+			}
+			cells = append(cells, in)
+		}
+	}
+
+	minX, minY, maxX, maxY := bounds(width, len(pattern), func(i int) bool { return len(cells[i]) > 0 })
+	if maxX < minX {
+		return nil, fmt.Errorf("pattern of %s is empty", name)
+	}
+	r := &Shaped{Name: name, Width: maxX - minX + 1, Height: maxY - minY + 1}
+	for y := minY; y <= maxY; y++ {
+		r.Pattern = append(r.Pattern, cells[y*width+minX:y*width+maxX+1]...)
+	}
+	return r, nil
+}
+
+func (r *Shaped) ID() string {
+	return r.Name
+}
+
+func (r *Shaped) Serializer() string {
+	return CraftingShaped
+}
+
+func (r *Shaped) WriteData(w *io.Writer) {
+	w.WriteString(r.Group).WriteVarInt(int32(r.Category))
+	w.WriteVarInt(int32(r.Width)).WriteVarInt(int32(r.Height))
+	for _, in := range r.Pattern {
+		in.write(w)
+	}
+	r.Result.WriteSlot(w)
+	w.WriteBool(r.ShowNotification)
+}
+
+// Matches reports whether the items in a grid are laid out as the pattern,
+// or as its mirror image.
+func (r *Shaped) Matches(g Grid) bool {
+	g = g.trim()
+	if g.Width != r.Width || g.Height != r.Height {
+		return false
+	}
+	return r.matches(g, false) || r.matches(g, true)
+}
+
+func (r *Shaped) matches(g Grid, mirrored bool) bool {
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			px := x
+			if mirrored {
+				px = r.Width - 1 - x
+			}
+			if !r.Pattern[y*r.Width+px].Test(g.At(x, y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *Shaped) Assemble(Grid) *item.ItemStack {
+	return r.Result.Clone()
+}
+
+// Layout returns the pattern in the top left corner of the grid.
+func (r *Shaped) Layout(width, height int) []Ingredient {
+	if r.Width > width || r.Height > height {
+		return nil
+	}
+	layout := make([]Ingredient, width*height)
+	for y := 0; y < r.Height; y++ {
+		copy(layout[y*width:], r.Pattern[y*r.Width:(y+1)*r.Width])
+	}
+	return layout
+}
+
+// Shapeless is a crafting recipe whose ingredients go anywhere in the grid.
+type Shapeless struct {
+	Name        string
+	Group       string
+	Category    CraftingCategory
+	Ingredients []Ingredient
+	Result      *item.ItemStack
+}
+
+var _ CraftingRecipe = (*Shapeless)(nil)
+
+func (r *Shapeless) ID() string {
+	return r.Name
+}
+
+func (r *Shapeless) Serializer() string {
+	return CraftingShapeless
+}
+
+func (r *Shapeless) WriteData(w *io.Writer) {
+	w.WriteString(r.Group).WriteVarInt(int32(r.Category))
+	w.WriteVarInt(int32(len(r.Ingredients)))
+	for _, in := range r.Ingredients {
+		in.write(w)
+	}
+	r.Result.WriteSlot(w)
+}
+
+// Matches reports whether every stack in a grid is used for a different
+// ingredient and every ingredient is in it.
+func (r *Shapeless) Matches(g Grid) bool {
+	var stacks []*item.ItemStack
+	for _, s := range g.Slots {
+		if !s.Empty() {
+			stacks = append(stacks, s)
+		}
+	}
+	if len(stacks) != len(r.Ingredients) {
+		return false
+	}
+	return assign(r.Ingredients, stacks, make([]bool, len(stacks)))
+}
+
+// assign reports whether each ingredient can be given a different stack it
+// takes, trying every way of doing so.
+func assign(ingredients []Ingredient, stacks []*item.ItemStack, used []bool) bool {
+	if len(ingredients) == 0 {
+		return true
+	}
+	for i, s := range stacks {
+		if !used[i] && ingredients[0].Test(s) {
+			used[i] = true
+			if assign(ingredients[1:], stacks, used) {
+				return true
+			}
+			used[i] = false
+		}
+	}
+	return false
+}
+
+func (r *Shapeless) Assemble(Grid) *item.ItemStack {
+	return r.Result.Clone()
+}
+
+// Layout returns the ingredients in the first slots of the grid.
+func (r *Shapeless) Layout(width, height int) []Ingredient {
+	if len(r.Ingredients) > width*height {
+		return nil
+	}
+	layout := make([]Ingredient, width*height)
+	copy(layout, r.Ingredients)
+	return layout
+}
+
+// Cooking is a recipe of a furnace, blast furnace, smoker or campfire, by its
+// serializer.
+type Cooking struct {
+	Name       string
+	Kind       string
+	Group      string
+	Category   CookingCategory
+	Ingredient Ingredient
+	Result     *item.ItemStack
+	// Experience is how much experience taking the result gives.
+	Experience float32
+	// CookingTime is how many ticks cooking takes.
+	CookingTime int32
+}
+
+var _ Recipe = (*Cooking)(nil)
+
+func (r *Cooking) ID() string {
+	return r.Name
+}
+
+func (r *Cooking) Serializer() string {
+	return r.Kind
+}
+
+func (r *Cooking) WriteData(w *io.Writer) {
+	w.WriteString(r.Group).WriteVarInt(int32(r.Category))
+	r.Ingredient.write(w)
+	r.Result.WriteSlot(w)
+	w.WriteFloat(r.Experience).WriteVarInt(r.CookingTime)
+}
+
+// Stonecutter is a recipe of the stonecutter.
+type Stonecutter struct {
+	Name       string
+	Group      string
+	Ingredient Ingredient
+	Result     *item.ItemStack
+}
+
+var _ Recipe = (*Stonecutter)(nil)
+
+func (r *Stonecutter) ID() string {
+	return r.Name
+}
+
+func (r *Stonecutter) Serializer() string {
+	return Stonecutting
+}
+
+func (r *Stonecutter) WriteData(w *io.Writer) {
+	w.WriteString(r.Group)
+	r.Ingredient.write(w)
+	r.Result.WriteSlot(w)
+}
+
+// Write writes a recipe as Update Recipes sends it.
+func Write(w *io.Writer, r Recipe) {
+	w.WriteIdentifier(r.ID()).WriteVarInt(serializerIDs[r.Serializer()])
+	r.WriteData(w)
+}
+
+// withNamespace adds the minecraft namespace to names without one.
+func withNamespace(name string) string {
+	if !strings.Contains(name, ":") {
+		return "minecraft:" + name
+	}
+	return name
+}
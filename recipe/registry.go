@@ -0,0 +1,132 @@
+package recipe
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hunterros-s/algernon/item"
+)
+
+// The vanilla recipes of the serializers this package has and the item tags,
+// as a data pack. They come from the 1.21.1 data pack, whose recipes are those
+// of 1.21.
+//
+//go:embed data
+var bundled embed.FS
+
+// Registry holds the recipes of the server by ID. It belongs to the game loop
+// once players join, and recipes must be registered before then to be sent
+// to them.
+type Registry struct {
+	recipes map[string]Recipe
+	// sorted are the recipes ordered by ID, nil when they changed since
+	sorted []Recipe
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{recipes: make(map[string]Recipe)}
+}
+
+// Register adds a recipe, replacing the one with the same ID. Recipes must use
+// one of the serializers of this package.
+func (r *Registry) Register(rec Recipe) {
+	if _, ok := serializerIDs[rec.Serializer()]; !ok {
+		panic("recipe: unknown serializer " + rec.Serializer())
+	}
+	r.recipes[rec.ID()] = rec
+	r.sorted = nil
+}
+
+// Remove removes a recipe.
+func (r *Registry) Remove(id string) {
+	delete(r.recipes, withNamespace(id))
+	r.sorted = nil
+}
+
+// Recipe returns a recipe by ID. The minecraft namespace is optional.
+func (r *Registry) Recipe(id string) (Recipe, bool) {
+	rec, ok := r.recipes[withNamespace(id)]
+	return rec, ok
+}
+
+// Recipes returns every recipe, ordered by ID.
+func (r *Registry) Recipes() []Recipe {
+	if r.sorted == nil {
+		r.sorted = make([]Recipe, 0, len(r.recipes))
+		for _, rec := range r.recipes {
+			r.sorted = append(r.sorted, rec)
+		}
+		slices.SortFunc(r.sorted, func(a, b Recipe) int { return strings.Compare(a.ID(), b.ID()) })
+	}
+	return r.sorted
+}
+
+// Craft returns the crafting recipe the items in a grid make, the first by
+// ID when several do.
+func (r *Registry) Craft(g Grid) (CraftingRecipe, bool) {
+	if g.Empty() {
+		return nil, false
+	}
+	for _, rec := range r.Recipes() {
+		if c, ok := rec.(CraftingRecipe); ok && c.Matches(g) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Cook returns the recipe cooking a stack in a furnace, blast furnace, smoker
+// or campfire, by serializer.
+func (r *Registry) Cook(kind string, s *item.ItemStack) (*Cooking, bool) {
+	for _, rec := range r.Recipes() {
+		if c, ok := rec.(*Cooking); ok && c.Kind == kind && c.Ingredient.Test(s) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Cut returns the recipes of the stonecutter taking a stack.
+func (r *Registry) Cut(s *item.ItemStack) []*Stonecutter {
+	var cuts []*Stonecutter
+	for _, rec := range r.Recipes() {
+		if c, ok := rec.(*Stonecutter); ok && c.Ingredient.Test(s) {
+			cuts = append(cuts, c)
+		}
+	}
+	return cuts
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+)
+
+// Default returns the registry used by the rest of the server, holding the
+// bundled recipes until LoadDefault adds others.
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		if _, err := defaultRegistry.LoadFS(bundled); err != nil {
+			panic("recipe: invalid bundled recipes: " + err.Error())
+		}
+	})
+	return defaultRegistry
+}
+
+// LoadDefault adds the recipes of a data pack folder to the default registry,
+// replacing those of the same ID. It returns how many recipes were skipped for
+// using items the item registry doesn't have or unsupported serializers. It
+// should be called before players join.
+func LoadDefault(dir string) (skipped int, err error) {
+	skipped, err = Default().LoadFS(os.DirFS(dir))
+	if err != nil {
+		return skipped, fmt.Errorf("error loading recipes of %s: %w", dir, err)
+	}
+	return skipped, nil
+}
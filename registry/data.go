@@ -0,0 +1,150 @@
+package registry
+
+import "github.com/hunterros-s/algernon/nbt"
+
+// Vanilla 1.21 data pack elements, in their network NBT form. Booleans are
+// bytes.
+
+// monsterSpawnLight is the light level range monsters spawn in outside the
+// nether.
+var monsterSpawnLight = nbt.Compound{
+	"type":          "minecraft:uniform",
+	"min_inclusive": int32(0),
+	"max_inclusive": int32(7),
+}
+
+func dimensionType(d nbt.Compound) nbt.Compound {
+	base := nbt.Compound{
+		"has_skylight":                    int8(1),
+		"has_ceiling":                     int8(0),
+		"ultrawarm":                       int8(0),
+		"natural":                         int8(1),
+		"coordinate_scale":                1.0,
+		"bed_works":                       int8(1),
+		"respawn_anchor_works":            int8(0),
+		"min_y":                           int32(-64),
+		"height":                          int32(384),
+		"logical_height":                  int32(384),
+		"infiniburn":                      "#minecraft:infiniburn_overworld",
+		"effects":                         "minecraft:overworld",
+		"ambient_light":                   float32(0),
+		"piglin_safe":                     int8(0),
+		"has_raids":                       int8(1),
+		"monster_spawn_light_level":       monsterSpawnLight,
+		"monster_spawn_block_light_limit": int32(0),
+	}
+	for k, v := range d {
+		base[k] = v
+	}
+	return base
+}
+
+// https://minecraft.wiki/w/Dimension_type
+var dimensionTypeData = map[string]nbt.Compound{
+	"minecraft:overworld":       dimensionType(nil),
+	"minecraft:overworld_caves": dimensionType(nbt.Compound{"has_ceiling": int8(1)}),
+	"minecraft:the_end": dimensionType(nbt.Compound{
+		"fixed_time":     int64(6000),
+		"has_skylight":   int8(0),
+		"natural":        int8(0),
+		"bed_works":      int8(0),
+		"min_y":          int32(0),
+		"height":         int32(256),
+		"logical_height": int32(256),
+		"infiniburn":     "#minecraft:infiniburn_end",
+		"effects":        "minecraft:the_end",
+	}),
+	"minecraft:the_nether": dimensionType(nbt.Compound{
+		"fixed_time":                      int64(18000),
+		"has_skylight":                    int8(0),
+		"has_ceiling":                     int8(1),
+		"ultrawarm":                       int8(1),
+		"natural":                         int8(0),
+		"coordinate_scale":                8.0,
+		"bed_works":                       int8(0),
+		"respawn_anchor_works":            int8(1),
+		"min_y":                           int32(0),
+		"height":                          int32(256),
+		"logical_height":                  int32(128),
+		"infiniburn":                      "#minecraft:infiniburn_nether",
+		"effects":                         "minecraft:the_nether",
+		"ambient_light":                   float32(0.1),
+		"piglin_safe":                     int8(1),
+		"has_raids":                       int8(0),
+		"monster_spawn_light_level":       int32(7),
+		"monster_spawn_block_light_limit": int32(15),
+	}),
+}
+
+// chatDecoration is how a chat type formats a message, from a translation
+// taking the given parameters.
+func chatDecoration(key string, style nbt.Compound, parameters ...string) nbt.Compound {
+	params := make([]any, len(parameters))
+	for i, p := range parameters {
+		params[i] = p
+	}
+	d := nbt.Compound{"translation_key": key, "parameters": params}
+	if style != nil {
+		d["style"] = style
+	}
+	return d
+}
+
+var (
+	whisper   = nbt.Compound{"color": "gray", "italic": int8(1)}
+	narration = chatDecoration("chat.type.text.narrate", nil, "sender", "content")
+)
+
+// https://minecraft.wiki/w/Chat_type
+var chatTypeData = map[string]nbt.Compound{
+	"minecraft:chat": {
+		"chat":      chatDecoration("chat.type.text", nil, "sender", "content"),
+		"narration": narration,
+	},
+	"minecraft:emote_command": {
+		"chat":      chatDecoration("chat.type.emote", nil, "sender", "content"),
+		"narration": chatDecoration("chat.type.emote", nil, "sender", "content"),
+	},
+	"minecraft:msg_command_incoming": {
+		"chat":      chatDecoration("commands.message.display.incoming", whisper, "sender", "content"),
+		"narration": narration,
+	},
+	"minecraft:msg_command_outgoing": {
+		"chat":      chatDecoration("commands.message.display.outgoing", whisper, "target", "content"),
+		"narration": narration,
+	},
+	"minecraft:say_command": {
+		"chat":      chatDecoration("chat.type.announcement", nil, "sender", "content"),
+		"narration": narration,
+	},
+	"minecraft:team_msg_command_incoming": {
+		"chat":      chatDecoration("chat.type.team.text", nil, "target", "sender", "content"),
+		"narration": narration,
+	},
+	"minecraft:team_msg_command_outgoing": {
+		"chat":      chatDecoration("chat.type.team.sent", nil, "target", "sender", "content"),
+		"narration": narration,
+	},
+}
+
+func wolfVariant(texture, biomes string) nbt.Compound {
+	return nbt.Compound{
+		"wild_texture":  "minecraft:entity/wolf/" + texture,
+		"tame_texture":  "minecraft:entity/wolf/" + texture + "_tame",
+		"angry_texture": "minecraft:entity/wolf/" + texture + "_angry",
+		"biomes":        biomes,
+	}
+}
+
+// https://minecraft.wiki/w/Wolf#Variants
+var wolfVariantData = map[string]nbt.Compound{
+	"minecraft:ashen":    wolfVariant("wolf_ashen", "minecraft:snowy_taiga"),
+	"minecraft:black":    wolfVariant("wolf_black", "minecraft:old_growth_pine_taiga"),
+	"minecraft:chestnut": wolfVariant("wolf_chestnut", "minecraft:old_growth_spruce_taiga"),
+	"minecraft:pale":     wolfVariant("wolf", "minecraft:taiga"),
+	"minecraft:rusty":    wolfVariant("wolf_rusty", "#minecraft:is_jungle"),
+	"minecraft:snowy":    wolfVariant("wolf_snowy", "minecraft:grove"),
+	"minecraft:spotted":  wolfVariant("wolf_spotted", "#minecraft:is_savanna"),
+	"minecraft:striped":  wolfVariant("wolf_striped", "#minecraft:is_badlands"),
+	"minecraft:woods":    wolfVariant("wolf_woods", "minecraft:forest"),
+}
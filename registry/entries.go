@@ -0,0 +1,200 @@
+package registry
+
+// Entries of the vanilla 1.21 registries, sorted by name as vanilla loads them.
+
+var chatTypes = []string{
+	"minecraft:chat", "minecraft:emote_command", "minecraft:msg_command_incoming",
+	"minecraft:msg_command_outgoing", "minecraft:say_command",
+	"minecraft:team_msg_command_incoming", "minecraft:team_msg_command_outgoing",
+}
+
+var trimPatterns = []string{
+	"minecraft:bolt", "minecraft:coast", "minecraft:dune", "minecraft:eye", "minecraft:flow",
+	"minecraft:host", "minecraft:raiser", "minecraft:rib", "minecraft:sentry",
+	"minecraft:shaper", "minecraft:silence", "minecraft:snout", "minecraft:spire",
+	"minecraft:tide", "minecraft:vex", "minecraft:ward", "minecraft:wayfinder",
+	"minecraft:wild",
+}
+
+var trimMaterials = []string{
+	"minecraft:amethyst", "minecraft:copper", "minecraft:diamond", "minecraft:emerald",
+	"minecraft:gold", "minecraft:iron", "minecraft:lapis", "minecraft:netherite",
+	"minecraft:quartz", "minecraft:redstone",
+}
+
+var wolfVariants = []string{
+	"minecraft:ashen", "minecraft:black", "minecraft:chestnut", "minecraft:pale",
+	"minecraft:rusty", "minecraft:snowy", "minecraft:spotted", "minecraft:striped",
+	"minecraft:woods",
+}
+
+var paintingVariants = []string{
+	"minecraft:alban", "minecraft:aztec", "minecraft:aztec2", "minecraft:backyard",
+	"minecraft:baroque", "minecraft:bomb", "minecraft:bouquet", "minecraft:burning_skull",
+	"minecraft:bust", "minecraft:cavebird", "minecraft:changing", "minecraft:cotan",
+	"minecraft:courbet", "minecraft:creebet", "minecraft:donkey_kong", "minecraft:earth",
+	"minecraft:endboss", "minecraft:fern", "minecraft:fighters", "minecraft:finding",
+	"minecraft:fire", "minecraft:graham", "minecraft:humble", "minecraft:kebab",
+	"minecraft:lowmist", "minecraft:match", "minecraft:meditative", "minecraft:orb",
+	"minecraft:owlemons", "minecraft:passage", "minecraft:pigscene", "minecraft:plant",
+	"minecraft:pointer", "minecraft:pond", "minecraft:pool", "minecraft:prairie_ride",
+	"minecraft:sea", "minecraft:skeleton", "minecraft:skull_and_roses", "minecraft:stage",
+	"minecraft:sunflowers", "minecraft:sunset", "minecraft:tides", "minecraft:unpacked",
+	"minecraft:void", "minecraft:wanderer", "minecraft:wasteland", "minecraft:water",
+	"minecraft:wind", "minecraft:wither",
+}
+
+var dimensionTypes = []string{
+	"minecraft:overworld", "minecraft:overworld_caves", "minecraft:the_end",
+	"minecraft:the_nether",
+}
+
+var damageTypes = []string{
+	"minecraft:arrow", "minecraft:bad_respawn_point", "minecraft:cactus",
+	"minecraft:campfire", "minecraft:cramming", "minecraft:dragon_breath", "minecraft:drown",
+	"minecraft:dry_out", "minecraft:explosion", "minecraft:fall", "minecraft:falling_anvil",
+	"minecraft:falling_block", "minecraft:falling_stalactite", "minecraft:fireball",
+	"minecraft:fireworks", "minecraft:fly_into_wall", "minecraft:freeze", "minecraft:generic",
+	"minecraft:generic_kill", "minecraft:hot_floor", "minecraft:in_fire", "minecraft:in_wall",
+	"minecraft:indirect_magic", "minecraft:lava", "minecraft:lightning_bolt",
+	"minecraft:magic", "minecraft:mob_attack", "minecraft:mob_attack_no_aggro",
+	"minecraft:mob_projectile", "minecraft:on_fire", "minecraft:out_of_world",
+	"minecraft:outside_border", "minecraft:player_attack", "minecraft:player_explosion",
+	"minecraft:sonic_boom", "minecraft:spit", "minecraft:stalagmite", "minecraft:starve",
+	"minecraft:sting", "minecraft:sweet_berry_bush", "minecraft:thorns", "minecraft:thrown",
+	"minecraft:trident", "minecraft:unattributed_fireball", "minecraft:wind_charge",
+	"minecraft:wither", "minecraft:wither_skull",
+}
+
+var bannerPatterns = []string{
+	"minecraft:base", "minecraft:border", "minecraft:bricks", "minecraft:circle",
+	"minecraft:creeper", "minecraft:cross", "minecraft:curly_border",
+	"minecraft:diagonal_left", "minecraft:diagonal_right", "minecraft:diagonal_up_left",
+	"minecraft:diagonal_up_right", "minecraft:flow", "minecraft:flower", "minecraft:globe",
+	"minecraft:gradient", "minecraft:gradient_up", "minecraft:guster",
+	"minecraft:half_horizontal", "minecraft:half_horizontal_bottom",
+	"minecraft:half_vertical", "minecraft:half_vertical_right", "minecraft:mojang",
+	"minecraft:piglin", "minecraft:rhombus", "minecraft:skull", "minecraft:small_stripes",
+	"minecraft:square_bottom_left", "minecraft:square_bottom_right",
+	"minecraft:square_top_left", "minecraft:square_top_right", "minecraft:straight_cross",
+	"minecraft:stripe_bottom", "minecraft:stripe_center", "minecraft:stripe_downleft",
+	"minecraft:stripe_downright", "minecraft:stripe_left", "minecraft:stripe_middle",
+	"minecraft:stripe_right", "minecraft:stripe_top", "minecraft:triangle_bottom",
+	"minecraft:triangle_top", "minecraft:triangles_bottom", "minecraft:triangles_top",
+}
+
+var enchantments = []string{
+	"minecraft:aqua_affinity", "minecraft:bane_of_arthropods", "minecraft:binding_curse",
+	"minecraft:blast_protection", "minecraft:breach", "minecraft:channeling",
+	"minecraft:density", "minecraft:depth_strider", "minecraft:efficiency",
+	"minecraft:feather_falling", "minecraft:fire_aspect", "minecraft:fire_protection",
+	"minecraft:flame", "minecraft:fortune", "minecraft:frost_walker", "minecraft:impaling",
+	"minecraft:infinity", "minecraft:knockback", "minecraft:looting", "minecraft:loyalty",
+	"minecraft:luck_of_the_sea", "minecraft:lure", "minecraft:mending", "minecraft:multishot",
+	"minecraft:piercing", "minecraft:power", "minecraft:projectile_protection",
+	"minecraft:protection", "minecraft:punch", "minecraft:quick_charge",
+	"minecraft:respiration", "minecraft:riptide", "minecraft:sharpness",
+	"minecraft:silk_touch", "minecraft:smite", "minecraft:soul_speed",
+	"minecraft:sweeping_edge", "minecraft:swift_sneak", "minecraft:thorns",
+	"minecraft:unbreaking", "minecraft:vanishing_curse", "minecraft:wind_burst",
+}
+
+var jukeboxSongs = []string{
+	"minecraft:11", "minecraft:13", "minecraft:5", "minecraft:blocks", "minecraft:cat",
+	"minecraft:chirp", "minecraft:creator", "minecraft:creator_music_box", "minecraft:far",
+	"minecraft:mall", "minecraft:mellohi", "minecraft:otherside", "minecraft:pigstep",
+	"minecraft:precipice", "minecraft:relic", "minecraft:stal", "minecraft:strad",
+	"minecraft:wait", "minecraft:ward",
+}
+
+// fluids is in registration order, which isn't alphabetical.
+var fluids = []string{
+	"minecraft:empty", "minecraft:flowing_water", "minecraft:water", "minecraft:flowing_lava", "minecraft:lava",
+}
+
+// entityTypes is in registration order, which isn't alphabetical.
+var entityTypes = []string{
+	"minecraft:allay", "minecraft:area_effect_cloud", "minecraft:armadillo",
+	"minecraft:armor_stand", "minecraft:arrow", "minecraft:axolotl", "minecraft:bat",
+	"minecraft:bee", "minecraft:blaze", "minecraft:block_display", "minecraft:boat",
+	"minecraft:bogged", "minecraft:breeze", "minecraft:breeze_wind_charge", "minecraft:camel",
+	"minecraft:cat", "minecraft:cave_spider", "minecraft:chest_boat", "minecraft:chest_minecart",
+	"minecraft:chicken", "minecraft:cod", "minecraft:command_block_minecart", "minecraft:cow",
+	"minecraft:creeper", "minecraft:dolphin", "minecraft:donkey", "minecraft:dragon_fireball",
+	"minecraft:drowned", "minecraft:egg", "minecraft:elder_guardian", "minecraft:end_crystal",
+	"minecraft:ender_dragon", "minecraft:ender_pearl", "minecraft:enderman",
+	"minecraft:endermite", "minecraft:evoker", "minecraft:evoker_fangs",
+	"minecraft:experience_bottle", "minecraft:experience_orb", "minecraft:eye_of_ender",
+	"minecraft:falling_block", "minecraft:firework_rocket", "minecraft:fox", "minecraft:frog",
+	"minecraft:furnace_minecart", "minecraft:ghast", "minecraft:giant",
+	"minecraft:glow_item_frame", "minecraft:glow_squid", "minecraft:goat", "minecraft:guardian",
+	"minecraft:hoglin", "minecraft:hopper_minecart", "minecraft:horse", "minecraft:husk",
+	"minecraft:illusioner", "minecraft:interaction", "minecraft:iron_golem", "minecraft:item",
+	"minecraft:item_display", "minecraft:item_frame", "minecraft:ominous_item_spawner",
+	"minecraft:fireball", "minecraft:leash_knot", "minecraft:lightning_bolt", "minecraft:llama",
+	"minecraft:llama_spit", "minecraft:magma_cube", "minecraft:marker", "minecraft:minecart",
+	"minecraft:mooshroom", "minecraft:mule", "minecraft:ocelot", "minecraft:painting",
+	"minecraft:panda", "minecraft:parrot", "minecraft:phantom", "minecraft:pig",
+	"minecraft:piglin", "minecraft:piglin_brute", "minecraft:pillager", "minecraft:polar_bear",
+	"minecraft:potion", "minecraft:pufferfish", "minecraft:rabbit", "minecraft:ravager",
+	"minecraft:salmon", "minecraft:sheep", "minecraft:shulker", "minecraft:shulker_bullet",
+	"minecraft:silverfish", "minecraft:skeleton", "minecraft:skeleton_horse", "minecraft:slime",
+	"minecraft:small_fireball", "minecraft:sniffer", "minecraft:snow_golem", "minecraft:snowball",
+	"minecraft:spawner_minecart", "minecraft:spectral_arrow", "minecraft:spider",
+	"minecraft:squid", "minecraft:stray", "minecraft:strider", "minecraft:tadpole",
+	"minecraft:text_display", "minecraft:tnt", "minecraft:tnt_minecart", "minecraft:trader_llama",
+	"minecraft:trident", "minecraft:tropical_fish", "minecraft:turtle", "minecraft:vex",
+	"minecraft:villager", "minecraft:vindicator", "minecraft:wandering_trader",
+	"minecraft:warden", "minecraft:wind_charge", "minecraft:witch", "minecraft:wither",
+	"minecraft:wither_skeleton", "minecraft:wither_skull", "minecraft:wolf", "minecraft:zoglin",
+	"minecraft:zombie", "minecraft:zombie_horse", "minecraft:zombie_villager",
+	"minecraft:zombified_piglin", "minecraft:player", "minecraft:fishing_bobber",
+}
+
+// gameEvents is in registration order, which isn't alphabetical.
+var gameEvents = []string{
+	"minecraft:block_activate", "minecraft:block_attach", "minecraft:block_change",
+	"minecraft:block_close", "minecraft:block_deactivate", "minecraft:block_destroy",
+	"minecraft:block_detach", "minecraft:block_open", "minecraft:block_place",
+	"minecraft:container_close", "minecraft:container_open", "minecraft:drink",
+	"minecraft:eat", "minecraft:elytra_glide", "minecraft:entity_damage",
+	"minecraft:entity_die", "minecraft:entity_dismount", "minecraft:entity_interact",
+	"minecraft:entity_mount", "minecraft:entity_place", "minecraft:entity_action",
+	"minecraft:equip", "minecraft:explode", "minecraft:flap", "minecraft:fluid_pickup",
+	"minecraft:fluid_place", "minecraft:hit_ground", "minecraft:instrument_play",
+	"minecraft:item_interact_finish", "minecraft:item_interact_start",
+	"minecraft:jukebox_play", "minecraft:jukebox_stop_play", "minecraft:lightning_strike",
+	"minecraft:note_block_play", "minecraft:prime_fuse", "minecraft:projectile_land",
+	"minecraft:projectile_shoot", "minecraft:sculk_sensor_tendrils_clicking",
+	"minecraft:shear", "minecraft:shriek", "minecraft:splash", "minecraft:step",
+	"minecraft:swim", "minecraft:teleport", "minecraft:unequip", "minecraft:resonate_1",
+	"minecraft:resonate_2", "minecraft:resonate_3", "minecraft:resonate_4",
+	"minecraft:resonate_5", "minecraft:resonate_6", "minecraft:resonate_7",
+	"minecraft:resonate_8", "minecraft:resonate_9", "minecraft:resonate_10",
+	"minecraft:resonate_11", "minecraft:resonate_12", "minecraft:resonate_13",
+	"minecraft:resonate_14", "minecraft:resonate_15",
+}
+
+// instruments is in registration order, which isn't alphabetical.
+var instruments = []string{
+	"minecraft:ponder_goat_horn", "minecraft:sing_goat_horn", "minecraft:seek_goat_horn",
+	"minecraft:feel_goat_horn", "minecraft:admire_goat_horn", "minecraft:call_goat_horn",
+	"minecraft:yearn_goat_horn", "minecraft:dream_goat_horn",
+}
+
+// catVariants is in registration order, which isn't alphabetical.
+var catVariants = []string{
+	"minecraft:tabby", "minecraft:black", "minecraft:red", "minecraft:siamese",
+	"minecraft:british_shorthair", "minecraft:calico", "minecraft:persian",
+	"minecraft:ragdoll", "minecraft:white", "minecraft:jellie", "minecraft:all_black",
+}
+
+// pointsOfInterest is in registration order, which isn't alphabetical.
+var pointsOfInterest = []string{
+	"minecraft:armorer", "minecraft:butcher", "minecraft:cartographer", "minecraft:cleric",
+	"minecraft:farmer", "minecraft:fisherman", "minecraft:fletcher",
+	"minecraft:leatherworker", "minecraft:librarian", "minecraft:mason", "minecraft:shepherd",
+	"minecraft:toolsmith", "minecraft:weaponsmith", "minecraft:home", "minecraft:meeting",
+	"minecraft:beehive", "minecraft:bee_nest", "minecraft:nether_portal",
+	"minecraft:lodestone", "minecraft:lightning_rod",
+}
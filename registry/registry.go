@@ -0,0 +1,132 @@
+// Package registry holds the data driven registries clients are sent during
+// configuration, bundled from vanilla 1.21.
+//
+// https://wiki.vg/Registry_Data
+package registry
+
+import (
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/world/biome"
+)
+
+// KnownPack identifies a data pack both sides may have, letting the server
+// leave out the data of registry entries that come from it.
+type KnownPack struct {
+	Namespace string
+	ID        string
+	Version   string
+}
+
+// Core is the pack holding vanilla's registries, which every vanilla client of
+// the same version has.
+var Core = KnownPack{Namespace: "minecraft", ID: "core", Version: "1.21"}
+
+// Entry is an element of a registry. Data is nil when only its name is bundled,
+// in which case it can only be sent to clients knowing the core pack.
+type Entry struct {
+	Name string
+	Data nbt.Compound
+}
+
+// Registry is a registry and its entries, whose IDs are their indices.
+type Registry struct {
+	Name    string
+	Entries []Entry
+	ids     map[string]int32
+}
+
+func newRegistry(name string, names []string, data map[string]nbt.Compound) *Registry {
+	r := &Registry{
+		Name:    name,
+		Entries: make([]Entry, len(names)),
+		ids:     make(map[string]int32, len(names)),
+	}
+	for i, n := range names {
+		r.Entries[i] = Entry{Name: n, Data: data[n]}
+		r.ids[n] = int32(i)
+	}
+	return r
+}
+
+// FromNames creates a registry of entries without data, whose IDs are their
+// indices in names. It is for registries the server holds elsewhere, like
+// blocks and items, to resolve tags of.
+func FromNames(name string, names []string) *Registry {
+	return newRegistry(name, names, nil)
+}
+
+// ID returns the ID of an entry.
+func (r *Registry) ID(name string) (int32, bool) {
+	id, ok := r.ids[name]
+	return id, ok
+}
+
+// Complete reports whether every entry has its data bundled.
+func (r *Registry) Complete() bool {
+	for _, e := range r.Entries {
+		if e.Data == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// synchronized are the registries a 1.21 client needs before entering play,
+// in the order vanilla sends them.
+var synchronized = []*Registry{
+	newRegistry("minecraft:worldgen/biome", biome.Names, nil),
+	newRegistry("minecraft:chat_type", chatTypes, chatTypeData),
+	newRegistry("minecraft:trim_pattern", trimPatterns, nil),
+	newRegistry("minecraft:trim_material", trimMaterials, nil),
+	newRegistry("minecraft:wolf_variant", wolfVariants, wolfVariantData),
+	newRegistry("minecraft:painting_variant", paintingVariants, nil),
+	newRegistry("minecraft:dimension_type", dimensionTypes, dimensionTypeData),
+	newRegistry("minecraft:damage_type", damageTypes, nil),
+	newRegistry("minecraft:banner_pattern", bannerPatterns, nil),
+	newRegistry("minecraft:enchantment", enchantments, nil),
+	newRegistry("minecraft:jukebox_song", jukeboxSongs, nil),
+}
+
+// static are registries built into the game rather than loaded from data
+// packs. They are never sent, but tags refer to their IDs.
+var static = []*Registry{
+	newRegistry("minecraft:fluid", fluids, nil),
+	newRegistry("minecraft:entity_type", entityTypes, nil),
+	newRegistry("minecraft:game_event", gameEvents, nil),
+	newRegistry("minecraft:instrument", instruments, nil),
+	newRegistry("minecraft:cat_variant", catVariants, nil),
+	newRegistry("minecraft:point_of_interest_type", pointsOfInterest, nil),
+}
+
+// Synchronized returns the registries sent with Registry Data.
+func Synchronized() []*Registry {
+	return synchronized
+}
+
+// Lookup returns a registry by name.
+func Lookup(name string) (*Registry, bool) {
+	for _, r := range synchronized {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	for _, r := range static {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// DimensionTypes is the dimension_type registry, whose IDs Login and Respawn
+// refer to.
+func DimensionTypes() *Registry {
+	r, _ := Lookup("minecraft:dimension_type")
+	return r
+}
+
+// EntityTypes is the entity_type registry, whose IDs Spawn Entity refers to.
+func EntityTypes() *Registry {
+	r, _ := Lookup("minecraft:entity_type")
+	return r
+}
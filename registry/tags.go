@@ -0,0 +1,61 @@
+package registry
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+)
+
+// Tags are named sets of entries of a registry, sent with Update Tags as
+// lists of entry IDs.
+type Tags struct {
+	Registry string
+	Tags     map[string][]int32
+}
+
+// The tags of the vanilla data pack by registry, with references to other
+// tags expanded. They come from the 1.21.4 data pack, limited to entries that
+// exist in 1.21.
+//
+//go:embed tags.json
+var tagData []byte
+
+var tagEntries = func() map[string]map[string][]string {
+	tags := map[string]map[string][]string{}
+	if err := json.Unmarshal(tagData, &tags); err != nil {
+		panic("registry: invalid bundled tags: " + err.Error())
+	}
+	return tags
+}()
+
+// AllTags resolves the bundled tags into entry IDs. Registries not held by
+// this package, like blocks and items, are given by the caller.
+func AllTags(registries ...*Registry) ([]Tags, error) {
+	all := make([]Tags, 0, len(tagEntries))
+	for name, tags := range tagEntries {
+		r, ok := Lookup(name)
+		for _, given := range registries {
+			if given.Name == name {
+				r, ok = given, true
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("tags of unknown registry %s", name)
+		}
+
+		resolved := Tags{Registry: name, Tags: make(map[string][]int32, len(tags))}
+		for tag, entries := range tags {
+			ids := make([]int32, len(entries))
+			for i, e := range entries {
+				id, ok := r.ID(e)
+				if !ok {
+					return nil, fmt.Errorf("unknown entry %s in tag %s of %s", e, tag, name)
+				}
+				ids[i] = id
+			}
+			resolved.Tags[tag] = ids
+		}
+		all = append(all, resolved)
+	}
+	return all, nil
+}
@@ -5,5 +5,9 @@ import "github.com/google/uuid"
 type Client interface {
 	Send(b []byte)
 	GetState() State
+	// SetState switches the state packets from the client are decoded in.
+	SetState(s State)
 	GetUUID() uuid.UUID
+	// Close disconnects the client after the data sent before is written.
+	Close()
 }
@@ -17,3 +17,10 @@ type ClientboundPacket interface {
 	PacketUID() string
 	Encode() ([]byte, error)
 }
+
+// StateTransition is implemented by serverbound packets after which the client
+// is in another state. The switch happens as the packet is decoded, before the
+// packets following it are.
+type StateTransition interface {
+	NextState() State
+}
@@ -1,6 +1,8 @@
 package listener
 
 import (
+	"sync/atomic"
+
 	"github.com/google/uuid"
 	"github.com/hunterros-s/algernon/server/common"
 	"github.com/hunterros-s/algernon/tcpserver"
@@ -10,23 +12,35 @@ import (
 type client struct {
 	tcpclient *tcpserver.Client
 	Logger    zerolog.Logger
-	state     common.State
+	state     atomic.Uint32
+
+	// data received that doesn't make up a whole packet yet
+	pending []byte
 }
 
 func newClient(c *tcpserver.Client, logger zerolog.Logger) *client {
-	return &client{
+	client := &client{
 		tcpclient: c,
 		Logger:    logger.With().Str("client address", c.GetIP()).Logger(),
-		state:     common.Handshaking,
 	}
+	client.SetState(common.Handshaking)
+	return client
 }
 
 func (c *client) Send(data []byte) {
 	c.tcpclient.Send(data)
 }
 
+func (c *client) Close() {
+	c.tcpclient.Close()
+}
+
 func (c *client) GetState() common.State {
-	return c.state
+	return common.State(c.state.Load())
+}
+
+func (c *client) SetState(s common.State) {
+	c.state.Store(uint32(s))
 }
 
 func (c *client) GetUUID() uuid.UUID {
@@ -0,0 +1,40 @@
+package listener
+
+import "errors"
+
+// maxPacketLength is the longest packet the length prefix, a varint of at most
+// three bytes, can announce.
+const maxPacketLength = 1<<21 - 1
+
+// maxVarIntLength is how many bytes varints take at most.
+const maxVarIntLength = 5
+
+var errPacketTooLong = errors.New("packet length too long")
+
+// nextFrame splits the first whole packet, length prefix included, off the
+// front of data. ok is false while more data is needed for it.
+func nextFrame(data []byte) (frame, rest []byte, ok bool, err error) {
+	var length, shift int
+	for i := 0; ; i++ {
+		if i == maxVarIntLength {
+			return nil, data, false, errPacketTooLong
+		}
+		if i == len(data) {
+			return nil, data, false, nil
+		}
+		b := data[i]
+		length |= int(b&0x7F) << shift
+		shift += 7
+		if b&0x80 != 0 {
+			continue
+		}
+		if length > maxPacketLength {
+			return nil, data, false, errPacketTooLong
+		}
+		end := i + 1 + length
+		if end > len(data) {
+			return nil, data, false, nil
+		}
+		return data[:end], data[end:], true, nil
+	}
+}
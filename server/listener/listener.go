@@ -109,8 +109,28 @@ func (l *Listener) onNewMessageTCP(c *tcpserver.Client, b []byte) {
 		return
 	}
 
-	if l.onNewMessage != nil {
-		l.onNewMessage(client, b)
+	// reads don't line up with packets, which may arrive split or several at once
+	client.pending = append(client.pending, b...)
+	for {
+		frame, rest, ok, err := nextFrame(client.pending)
+		if err != nil {
+			// the stream can't be resynchronized, so the connection is dropped
+			// like vanilla does
+			client.Logger.Error().Err(err).Msg("Unable to read packet")
+			client.pending = nil
+			client.Close()
+			return
+		}
+		if !ok {
+			break
+		}
+		if l.onNewMessage != nil {
+			l.onNewMessage(client, frame)
+		}
+		client.pending = rest
+	}
+	if len(client.pending) == 0 {
+		client.pending = nil
 	}
 }
 
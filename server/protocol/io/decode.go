@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/nbt"
 	"github.com/hunterros-s/algernon/text"
 )
 
@@ -150,6 +151,20 @@ func ReadJSONTextComponent(buf *bytes.Buffer) (text.TextComponent, error) {
 	return comp, nil
 }
 
+// https://wiki.vg/Protocol#Type:Text_Component
+func ReadTextComponent(buf *bytes.Buffer) (text.TextComponent, error) {
+	v, err := ReadNBT(buf)
+	if err != nil {
+		return text.TextComponent{}, err
+	}
+
+	var comp text.TextComponent
+	if err := comp.UnmarshalNBT(v); err != nil {
+		return text.TextComponent{}, err
+	}
+	return comp, nil
+}
+
 // https://wiki.vg/Protocol#Type:Identifier
 func ReadIdentifier(buf *bytes.Buffer) (string, error) {
 	identifier, err := ReadString(buf)
@@ -265,6 +280,15 @@ func ReadPosition(buf *bytes.Buffer) (x, y, z int32, err error) {
 	return x, y, z, nil
 }
 
+// https://wiki.vg/Protocol#Type:Angle
+func ReadAngle(buf *bytes.Buffer) (float32, error) {
+	b, err := buf.ReadByte()
+	if err != nil {
+		return 0, errors.New("buffer has insufficient data to read angle")
+	}
+	return float32(b) * 360 / 256, nil
+}
+
 // https://wiki.vg/Protocol#Type:UUID
 func ReadUUID(buf *bytes.Buffer) (uuid.UUID, error) {
 	if buf.Len() < 16 {
@@ -360,3 +384,8 @@ func ReadFixedByteArray(buf *bytes.Buffer, length int) ([]byte, error) {
 
 	return data, nil
 }
+
+// https://wiki.vg/Protocol#Type:NBT
+func ReadNBT(buf *bytes.Buffer) (any, error) {
+	return nbt.DecodeNetwork(buf)
+}
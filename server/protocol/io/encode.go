@@ -8,6 +8,7 @@ import (
 	"math"
 
 	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/nbt"
 	"github.com/hunterros-s/algernon/text"
 )
 
@@ -85,6 +86,15 @@ func WriteJSONTextComponent(comp text.TextComponent) ([]byte, error) {
 	return WriteByteArray(d)
 }
 
+// https://wiki.vg/Protocol#Type:Text_Component
+func WriteTextComponent(comp text.TextComponent) ([]byte, error) {
+	v, err := comp.MarshalNBT()
+	if err != nil {
+		return nil, err
+	}
+	return WriteNBT(v)
+}
+
 // https://wiki.vg/Protocol#Type:Identifier
 func WriteIdentifier(s string) ([]byte, error) {
 	if len(s) > 32767 {
@@ -131,6 +141,11 @@ func WritePosition(x, y, z int32) ([]byte, error) {
 	return WriteLong(value)
 }
 
+// https://wiki.vg/Protocol#Type:Angle
+func WriteAngle(degrees float32) ([]byte, error) {
+	return []byte{byte(int32(math.Floor(float64(degrees) * 256 / 360)))}, nil
+}
+
 // https://wiki.vg/Protocol#Type:UUID
 func WriteUUID(u uuid.UUID) ([]byte, error) {
 	return u[:], nil
@@ -170,3 +185,8 @@ func WriteByteArray(s []byte) ([]byte, error) {
 func WriteFixedByteArray(s []byte) ([]byte, error) {
 	return s, nil
 }
+
+// https://wiki.vg/Protocol#Type:NBT
+func WriteNBT(v any) ([]byte, error) {
+	return nbt.MarshalNetwork(v)
+}
@@ -123,6 +123,15 @@ func (r *Reader) ReadJSONTextComponent() text.TextComponent {
 	return value
 }
 
+func (r *Reader) ReadTextComponent() text.TextComponent {
+	if r.err != nil {
+		return text.TextComponent{}
+	}
+	var value text.TextComponent
+	value, r.err = ReadTextComponent(r.buffer)
+	return value
+}
+
 func (r *Reader) ReadIdentifier() string {
 	if r.err != nil {
 		return ""
@@ -159,6 +168,15 @@ func (r *Reader) ReadPosition() (int32, int32, int32) {
 	return x, y, z
 }
 
+func (r *Reader) ReadAngle() float32 {
+	if r.err != nil {
+		return 0
+	}
+	var a float32
+	a, r.err = ReadAngle(r.buffer)
+	return a
+}
+
 func (r *Reader) ReadUUID() uuid.UUID {
 	if r.err != nil {
 		return uuid.UUID{}
@@ -203,3 +221,12 @@ func (r *Reader) ReadFixedByteArray(length int) []byte {
 	value, r.err = ReadFixedByteArray(r.buffer, length)
 	return value
 }
+
+func (r *Reader) ReadNBT() any {
+	if r.err != nil {
+		return nil
+	}
+	var value any
+	value, r.err = ReadNBT(r.buffer)
+	return value
+}
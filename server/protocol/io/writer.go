@@ -168,6 +168,19 @@ func (w *Writer) WriteJSONTextComponent(comp text.TextComponent) *Writer {
 	return w
 }
 
+func (w *Writer) WriteTextComponent(comp text.TextComponent) *Writer {
+	if w.err != nil {
+		return w
+	}
+	buf, err := WriteTextComponent(comp)
+	if err != nil {
+		w.err = err
+		return w
+	}
+	w.buffer = append(w.buffer, buf...)
+	return w
+}
+
 func (w *Writer) WriteIdentifier(s string) *Writer {
 	if w.err != nil {
 		return w
@@ -220,6 +233,19 @@ func (w *Writer) WritePosition(x, y, z int32) *Writer {
 	return w
 }
 
+func (w *Writer) WriteAngle(degrees float32) *Writer {
+	if w.err != nil {
+		return w
+	}
+	buf, err := WriteAngle(degrees)
+	if err != nil {
+		w.err = err
+		return w
+	}
+	w.buffer = append(w.buffer, buf...)
+	return w
+}
+
 func (w *Writer) WriteUUID(u uuid.UUID) *Writer {
 	if w.err != nil {
 		return w
@@ -284,3 +310,16 @@ func (w *Writer) WriteFixedByteArray(s []byte) *Writer {
 	w.buffer = append(w.buffer, buf...)
 	return w
 }
+
+func (w *Writer) WriteNBT(v any) *Writer {
+	if w.err != nil {
+		return w
+	}
+	buf, err := WriteNBT(v)
+	if err != nil {
+		w.err = err
+		return w
+	}
+	w.buffer = append(w.buffer, buf...)
+	return w
+}
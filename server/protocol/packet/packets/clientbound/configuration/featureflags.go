@@ -0,0 +1,33 @@
+package configuration
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*FeatureFlagsPacket)(nil)
+
+// https://wiki.vg/Protocol#Feature_Flags
+type FeatureFlagsPacket struct {
+	FeatureFlags []string `mc:"array,identifier"`
+}
+
+func (FeatureFlagsPacket) MCPacketID() uint32 {
+	return 0x0C
+}
+
+var featureFlagsUID = util.GetPacketUID(FeatureFlagsPacket{})
+
+func (FeatureFlagsPacket) PacketUID() string {
+	return featureFlagsUID
+}
+
+func (p FeatureFlagsPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(int32(len(p.FeatureFlags)))
+	for _, flag := range p.FeatureFlags {
+		w.WriteIdentifier(flag)
+	}
+	return w.Bytes(), w.Err()
+}
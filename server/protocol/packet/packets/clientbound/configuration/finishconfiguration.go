@@ -0,0 +1,25 @@
+package configuration
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*FinishConfigurationPacket)(nil)
+
+// https://wiki.vg/Protocol#Finish_Configuration
+type FinishConfigurationPacket struct{}
+
+func (FinishConfigurationPacket) MCPacketID() uint32 {
+	return 0x03
+}
+
+var finishConfigurationUID = util.GetPacketUID(FinishConfigurationPacket{})
+
+func (FinishConfigurationPacket) PacketUID() string {
+	return finishConfigurationUID
+}
+
+func (FinishConfigurationPacket) Encode() ([]byte, error) {
+	return nil, nil
+}
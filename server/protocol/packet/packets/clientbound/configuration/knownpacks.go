@@ -0,0 +1,45 @@
+package configuration
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*KnownPacksPacket)(nil)
+
+// KnownPack is a data pack by namespace, ID and version.
+type KnownPack struct {
+	Namespace string `mc:"string"`
+	ID        string `mc:"string"`
+	Version   string `mc:"string"`
+}
+
+// KnownPacksPacket lists the packs the server has, asking the client which of
+// them it has too.
+//
+// https://wiki.vg/Protocol#Clientbound_Known_Packs
+type KnownPacksPacket struct {
+	KnownPacks []KnownPack `mc:"array"`
+}
+
+func (KnownPacksPacket) MCPacketID() uint32 {
+	return 0x0E
+}
+
+var knownPacksUID = util.GetPacketUID(KnownPacksPacket{})
+
+func (KnownPacksPacket) PacketUID() string {
+	return knownPacksUID
+}
+
+func (p KnownPacksPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(int32(len(p.KnownPacks)))
+	for _, pack := range p.KnownPacks {
+		w.WriteString(pack.Namespace)
+		w.WriteString(pack.ID)
+		w.WriteString(pack.Version)
+	}
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,36 @@
+package configuration
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*PluginMessagePacket)(nil)
+
+// BrandChannel carries the name of the server software, shown in the debug
+// screen.
+const BrandChannel = "minecraft:brand"
+
+// https://wiki.vg/Protocol#Clientbound_Plugin_Message_.28configuration.29
+type PluginMessagePacket struct {
+	Channel string `mc:"identifier"`
+	Data    []byte `mc:"rest"`
+}
+
+func (PluginMessagePacket) MCPacketID() uint32 {
+	return 0x01
+}
+
+var pluginMessageUID = util.GetPacketUID(PluginMessagePacket{})
+
+func (PluginMessagePacket) PacketUID() string {
+	return pluginMessageUID
+}
+
+func (p PluginMessagePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteIdentifier(p.Channel)
+	w.WriteFixedByteArray(p.Data)
+	return w.Bytes(), w.Err()
+}
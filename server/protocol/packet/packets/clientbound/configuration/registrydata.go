@@ -0,0 +1,46 @@
+package configuration
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*RegistryDataPacket)(nil)
+
+// RegistryEntry is an entry of a registry. Data is nil for entries the client
+// takes from a pack it knows.
+type RegistryEntry struct {
+	ID   string `mc:"identifier"`
+	Data any    `mc:"optional nbt"`
+}
+
+// https://wiki.vg/Protocol#Registry_Data_2
+type RegistryDataPacket struct {
+	RegistryID string          `mc:"identifier"`
+	Entries    []RegistryEntry `mc:"array"`
+}
+
+func (RegistryDataPacket) MCPacketID() uint32 {
+	return 0x07
+}
+
+var registryDataUID = util.GetPacketUID(RegistryDataPacket{})
+
+func (RegistryDataPacket) PacketUID() string {
+	return registryDataUID
+}
+
+func (p RegistryDataPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteIdentifier(p.RegistryID)
+	w.WriteVarInt(int32(len(p.Entries)))
+	for _, e := range p.Entries {
+		w.WriteIdentifier(e.ID)
+		w.WriteBool(e.Data != nil)
+		if e.Data != nil {
+			w.WriteNBT(e.Data)
+		}
+	}
+	return w.Bytes(), w.Err()
+}
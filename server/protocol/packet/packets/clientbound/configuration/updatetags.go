@@ -0,0 +1,54 @@
+package configuration
+
+import (
+	"slices"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UpdateTagsPacket)(nil)
+
+// https://wiki.vg/Protocol#Update_Tags_.28configuration.29
+type UpdateTagsPacket struct {
+	// tags by registry, each tag listing the IDs of its entries
+	Tags map[string]map[string][]int32 `mc:"array"`
+}
+
+func (UpdateTagsPacket) MCPacketID() uint32 {
+	return 0x0D
+}
+
+var updateTagsUID = util.GetPacketUID(UpdateTagsPacket{})
+
+func (UpdateTagsPacket) PacketUID() string {
+	return updateTagsUID
+}
+
+func (p UpdateTagsPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(int32(len(p.Tags)))
+	for _, registry := range sortedKeys(p.Tags) {
+		tags := p.Tags[registry]
+		w.WriteIdentifier(registry)
+		w.WriteVarInt(int32(len(tags)))
+		for _, tag := range sortedKeys(tags) {
+			w.WriteIdentifier(tag)
+			w.WriteVarInt(int32(len(tags[tag])))
+			for _, id := range tags[tag] {
+				w.WriteVarInt(id)
+			}
+		}
+	}
+	return w.Bytes(), w.Err()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
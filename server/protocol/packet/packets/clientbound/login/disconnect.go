@@ -0,0 +1,32 @@
+package login
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/text"
+)
+
+var _ common.ClientboundPacket = (*DisconnectPacket)(nil)
+
+// https://wiki.vg/Protocol#Disconnect_.28login.29
+type DisconnectPacket struct {
+	// sent as JSON, unlike in the later states
+	Reason text.TextComponent `mc:"json"`
+}
+
+func (DisconnectPacket) MCPacketID() uint32 {
+	return 0x00
+}
+
+var disconnectUID = util.GetPacketUID(DisconnectPacket{})
+
+func (DisconnectPacket) PacketUID() string {
+	return disconnectUID
+}
+
+func (p DisconnectPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteJSONTextComponent(p.Reason)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,55 @@
+package login
+
+import (
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*LoginSuccessPacket)(nil)
+
+// Property is a signed or unsigned game profile property, like the textures of
+// a skin.
+type Property struct {
+	Name      string `mc:"string,max=32767"`
+	Value     string `mc:"string,max=32767"`
+	Signature string `mc:"optional string,max=32767"` // empty when unsigned
+}
+
+// https://wiki.vg/Protocol#Login_Success
+type LoginSuccessPacket struct {
+	UUID       uuid.UUID  `mc:"uuid"`
+	Username   string     `mc:"string,max=16"`
+	Properties []Property `mc:"array"`
+	// whether the client disconnects on packets it can't decode instead of
+	// skipping them
+	StrictErrorHandling bool `mc:"bool"`
+}
+
+func (LoginSuccessPacket) MCPacketID() uint32 {
+	return 0x02
+}
+
+var loginSuccessUID = util.GetPacketUID(LoginSuccessPacket{})
+
+func (LoginSuccessPacket) PacketUID() string {
+	return loginSuccessUID
+}
+
+func (p LoginSuccessPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteUUID(p.UUID)
+	w.WriteString(p.Username)
+	w.WriteVarInt(int32(len(p.Properties)))
+	for _, prop := range p.Properties {
+		w.WriteString(prop.Name)
+		w.WriteString(prop.Value)
+		w.WriteBool(prop.Signature != "")
+		if prop.Signature != "" {
+			w.WriteString(prop.Signature)
+		}
+	}
+	w.WriteBool(p.StrictErrorHandling)
+	return w.Bytes(), w.Err()
+}
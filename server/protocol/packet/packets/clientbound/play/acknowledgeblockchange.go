@@ -0,0 +1,34 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*AcknowledgeBlockChangePacket)(nil)
+
+// AcknowledgeBlockChangePacket tells the client the block changes it predicted
+// up to a sequence were handled. It then shows the blocks it was sent instead
+// of its predictions.
+//
+// https://wiki.vg/Protocol#Acknowledge_Block_Change
+type AcknowledgeBlockChangePacket struct {
+	Sequence int32 `mc:"varint"`
+}
+
+func (AcknowledgeBlockChangePacket) MCPacketID() uint32 {
+	return 0x05
+}
+
+var acknowledgeBlockChangeUID = util.GetPacketUID(AcknowledgeBlockChangePacket{})
+
+func (AcknowledgeBlockChangePacket) PacketUID() string {
+	return acknowledgeBlockChangeUID
+}
+
+func (p AcknowledgeBlockChangePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.Sequence)
+	return w.Bytes(), w.Err()
+}
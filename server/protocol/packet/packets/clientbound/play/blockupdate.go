@@ -0,0 +1,35 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*BlockUpdatePacket)(nil)
+
+// https://wiki.vg/Protocol#Block_Update
+type BlockUpdatePacket struct {
+	X int32 `mc:"position"`
+	Y int32 `mc:"position"`
+	Z int32 `mc:"position"`
+	// BlockState is the ID of the new state in the global palette.
+	BlockState int32 `mc:"varint"`
+}
+
+func (BlockUpdatePacket) MCPacketID() uint32 {
+	return 0x09
+}
+
+var blockUpdateUID = util.GetPacketUID(BlockUpdatePacket{})
+
+func (BlockUpdatePacket) PacketUID() string {
+	return blockUpdateUID
+}
+
+func (p BlockUpdatePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WritePosition(p.X, p.Y, p.Z)
+	w.WriteVarInt(p.BlockState)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,30 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*ChunkBatchFinishedPacket)(nil)
+
+// https://wiki.vg/Protocol#Chunk_Batch_Finished
+type ChunkBatchFinishedPacket struct {
+	BatchSize int32 `mc:"varint"`
+}
+
+func (ChunkBatchFinishedPacket) MCPacketID() uint32 {
+	return 0x0C
+}
+
+var chunkBatchFinishedUID = util.GetPacketUID(ChunkBatchFinishedPacket{})
+
+func (ChunkBatchFinishedPacket) PacketUID() string {
+	return chunkBatchFinishedUID
+}
+
+func (p ChunkBatchFinishedPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.BatchSize)
+	return w.Bytes(), w.Err()
+}
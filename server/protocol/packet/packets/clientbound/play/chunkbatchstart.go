@@ -0,0 +1,25 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*ChunkBatchStartPacket)(nil)
+
+// https://wiki.vg/Protocol#Chunk_Batch_Start
+type ChunkBatchStartPacket struct{}
+
+func (ChunkBatchStartPacket) MCPacketID() uint32 {
+	return 0x0D
+}
+
+var chunkBatchStartUID = util.GetPacketUID(ChunkBatchStartPacket{})
+
+func (ChunkBatchStartPacket) PacketUID() string {
+	return chunkBatchStartUID
+}
+
+func (ChunkBatchStartPacket) Encode() ([]byte, error) {
+	return nil, nil
+}
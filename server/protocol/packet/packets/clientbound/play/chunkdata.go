@@ -0,0 +1,31 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+var _ common.ClientboundPacket = (*ChunkDataPacket)(nil)
+
+// https://wiki.vg/Protocol#Chunk_Data_and_Update_Light
+type ChunkDataPacket struct {
+	Chunk *chunk.Chunk
+}
+
+func (ChunkDataPacket) MCPacketID() uint32 {
+	return 0x27
+}
+
+var chunkDataUID = util.GetPacketUID(ChunkDataPacket{})
+
+func (ChunkDataPacket) PacketUID() string {
+	return chunkDataUID
+}
+
+func (p ChunkDataPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	p.Chunk.EncodeData(w)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,32 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*CloseContainerPacket)(nil)
+
+// CloseContainerPacket closes a window the client has open.
+//
+// https://wiki.vg/Protocol#Close_Container
+type CloseContainerPacket struct {
+	WindowID uint8 `mc:"ubyte"`
+}
+
+func (CloseContainerPacket) MCPacketID() uint32 {
+	return 0x12
+}
+
+var closeContainerUID = util.GetPacketUID(CloseContainerPacket{})
+
+func (CloseContainerPacket) PacketUID() string {
+	return closeContainerUID
+}
+
+func (p CloseContainerPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteUbyte(p.WindowID)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,49 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*DamageEventPacket)(nil)
+
+// https://wiki.vg/Protocol#Damage_Event
+type DamageEventPacket struct {
+	EntityID int32 `mc:"varint"`
+	// SourceType is the ID of the damage_type registry entry
+	SourceType int32 `mc:"varint"`
+	// entity IDs plus one, 0 for none
+	SourceCauseID  int32 `mc:"varint"`
+	SourceDirectID int32 `mc:"varint"`
+
+	HasSourcePosition bool    `mc:"bool"`
+	SourceX           float64 `mc:"optional double"`
+	SourceY           float64 `mc:"optional double"`
+	SourceZ           float64 `mc:"optional double"`
+}
+
+func (DamageEventPacket) MCPacketID() uint32 {
+	return 0x1A
+}
+
+var damageEventUID = util.GetPacketUID(DamageEventPacket{})
+
+func (DamageEventPacket) PacketUID() string {
+	return damageEventUID
+}
+
+func (p DamageEventPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteVarInt(p.SourceType)
+	w.WriteVarInt(p.SourceCauseID)
+	w.WriteVarInt(p.SourceDirectID)
+	w.WriteBool(p.HasSourcePosition)
+	if p.HasSourcePosition {
+		w.WriteDouble(p.SourceX)
+		w.WriteDouble(p.SourceY)
+		w.WriteDouble(p.SourceZ)
+	}
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,31 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/text"
+)
+
+var _ common.ClientboundPacket = (*DisconnectPacket)(nil)
+
+// https://wiki.vg/Protocol#Disconnect_.28play.29
+type DisconnectPacket struct {
+	Reason text.TextComponent `mc:"textcomponent"`
+}
+
+func (DisconnectPacket) MCPacketID() uint32 {
+	return 0x1D
+}
+
+var disconnectUID = util.GetPacketUID(DisconnectPacket{})
+
+func (DisconnectPacket) PacketUID() string {
+	return disconnectUID
+}
+
+func (p DisconnectPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteTextComponent(p.Reason)
+	return w.Bytes(), w.Err()
+}
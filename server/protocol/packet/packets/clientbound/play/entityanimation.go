@@ -0,0 +1,41 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*EntityAnimationPacket)(nil)
+
+// Animations of Entity Animation
+const (
+	AnimationSwingMainArm uint8 = 0
+	AnimationLeaveBed     uint8 = 2
+	AnimationSwingOffhand uint8 = 3
+	AnimationCriticalHit  uint8 = 4
+	AnimationMagicHit     uint8 = 5
+)
+
+// https://wiki.vg/Protocol#Entity_Animation
+type EntityAnimationPacket struct {
+	EntityID  int32 `mc:"varint"`
+	Animation uint8 `mc:"ubyte"`
+}
+
+func (EntityAnimationPacket) MCPacketID() uint32 {
+	return 0x03
+}
+
+var entityAnimationUID = util.GetPacketUID(EntityAnimationPacket{})
+
+func (EntityAnimationPacket) PacketUID() string {
+	return entityAnimationUID
+}
+
+func (p EntityAnimationPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteUbyte(p.Animation)
+	return w.Bytes(), w.Err()
+}
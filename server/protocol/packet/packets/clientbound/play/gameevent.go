@@ -0,0 +1,38 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*GameEventPacket)(nil)
+
+// Game events
+const (
+	GameEventChangeGameMode        = 3
+	GameEventStartWaitingForChunks = 13
+)
+
+// https://wiki.vg/Protocol#Game_Event
+type GameEventPacket struct {
+	Event uint8   `mc:"ubyte"`
+	Value float32 `mc:"float"`
+}
+
+func (GameEventPacket) MCPacketID() uint32 {
+	return 0x22
+}
+
+var gameEventUID = util.GetPacketUID(GameEventPacket{})
+
+func (GameEventPacket) PacketUID() string {
+	return gameEventUID
+}
+
+func (p GameEventPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteUbyte(p.Event)
+	w.WriteFloat(p.Value)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,33 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*KeepAlivePacket)(nil)
+
+// KeepAlivePacket must be answered with the same ID, or the client is
+// disconnected.
+//
+// https://wiki.vg/Protocol#Clientbound_Keep_Alive_.28play.29
+type KeepAlivePacket struct {
+	KeepAliveID int64 `mc:"long"`
+}
+
+func (KeepAlivePacket) MCPacketID() uint32 {
+	return 0x26
+}
+
+var keepAliveUID = util.GetPacketUID(KeepAlivePacket{})
+
+func (KeepAlivePacket) PacketUID() string {
+	return keepAliveUID
+}
+
+func (p KeepAlivePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteLong(p.KeepAliveID)
+	return w.Bytes(), w.Err()
+}
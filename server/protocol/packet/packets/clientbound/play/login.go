@@ -0,0 +1,82 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*LoginPacket)(nil)
+
+// LoginPacket starts play, describing the world the player joins.
+//
+// https://wiki.vg/Protocol#Login_.28play.29
+type LoginPacket struct {
+	EntityID            int32    `mc:"int"`
+	IsHardcore          bool     `mc:"bool"`
+	DimensionNames      []string `mc:"array,identifier"`
+	MaxPlayers          int32    `mc:"varint"` // unused by the client
+	ViewDistance        int32    `mc:"varint"`
+	SimulationDistance  int32    `mc:"varint"`
+	ReducedDebugInfo    bool     `mc:"bool"`
+	EnableRespawnScreen bool     `mc:"bool"`
+	DoLimitedCrafting   bool     `mc:"bool"`
+	// ID in the dimension_type registry
+	DimensionType int32  `mc:"varint"`
+	DimensionName string `mc:"identifier"`
+	// first 8 bytes of the SHA-256 hash of the world seed, for biome noise
+	HashedSeed       int64 `mc:"long"`
+	GameMode         uint8 `mc:"ubyte"`
+	PreviousGameMode int8  `mc:"byte"` // -1 for none
+	IsDebug          bool  `mc:"bool"`
+	IsFlat           bool  `mc:"bool"`
+	// where the player last died, sent when HasDeathLocation is true
+	HasDeathLocation   bool   `mc:"bool"`
+	DeathDimensionName string `mc:"optional identifier"`
+	DeathX             int32  `mc:"optional position"`
+	DeathY             int32  `mc:"optional position"`
+	DeathZ             int32  `mc:"optional position"`
+	PortalCooldown     int32  `mc:"varint"`
+	EnforcesSecureChat bool   `mc:"bool"`
+}
+
+func (LoginPacket) MCPacketID() uint32 {
+	return 0x2B
+}
+
+var loginUID = util.GetPacketUID(LoginPacket{})
+
+func (LoginPacket) PacketUID() string {
+	return loginUID
+}
+
+func (p LoginPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteInt(p.EntityID)
+	w.WriteBool(p.IsHardcore)
+	w.WriteVarInt(int32(len(p.DimensionNames)))
+	for _, name := range p.DimensionNames {
+		w.WriteIdentifier(name)
+	}
+	w.WriteVarInt(p.MaxPlayers)
+	w.WriteVarInt(p.ViewDistance)
+	w.WriteVarInt(p.SimulationDistance)
+	w.WriteBool(p.ReducedDebugInfo)
+	w.WriteBool(p.EnableRespawnScreen)
+	w.WriteBool(p.DoLimitedCrafting)
+	w.WriteVarInt(p.DimensionType)
+	w.WriteIdentifier(p.DimensionName)
+	w.WriteLong(p.HashedSeed)
+	w.WriteUbyte(p.GameMode)
+	w.WriteByteInt8(p.PreviousGameMode)
+	w.WriteBool(p.IsDebug)
+	w.WriteBool(p.IsFlat)
+	w.WriteBool(p.HasDeathLocation)
+	if p.HasDeathLocation {
+		w.WriteIdentifier(p.DeathDimensionName)
+		w.WritePosition(p.DeathX, p.DeathY, p.DeathZ)
+	}
+	w.WriteVarInt(p.PortalCooldown)
+	w.WriteBool(p.EnforcesSecureChat)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,36 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/text"
+)
+
+var _ common.ClientboundPacket = (*OpenScreenPacket)(nil)
+
+// OpenScreenPacket opens a container window, whose slots are sent after.
+//
+// https://wiki.vg/Protocol#Open_Screen
+type OpenScreenPacket struct {
+	WindowID int32 `mc:"varint"`
+	// WindowType is the ID of the type in the menu registry.
+	WindowType int32              `mc:"varint"`
+	Title      text.TextComponent `mc:"textcomponent"`
+}
+
+func (OpenScreenPacket) MCPacketID() uint32 {
+	return 0x33
+}
+
+var openScreenUID = util.GetPacketUID(OpenScreenPacket{})
+
+func (OpenScreenPacket) PacketUID() string {
+	return openScreenUID
+}
+
+func (p OpenScreenPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.WindowID).WriteVarInt(p.WindowType).WriteTextComponent(p.Title)
+	return w.Bytes(), w.Err()
+}
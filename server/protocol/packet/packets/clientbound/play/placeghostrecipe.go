@@ -0,0 +1,34 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*PlaceGhostRecipePacket)(nil)
+
+// PlaceGhostRecipePacket shows the ingredients of a recipe the player lacks in
+// the crafting grid of a window, in answer to Place Recipe.
+//
+// https://wiki.vg/Protocol#Place_Ghost_Recipe
+type PlaceGhostRecipePacket struct {
+	WindowID int8   `mc:"byte"`
+	Recipe   string `mc:"identifier"`
+}
+
+func (PlaceGhostRecipePacket) MCPacketID() uint32 {
+	return 0x37
+}
+
+var placeGhostRecipeUID = util.GetPacketUID(PlaceGhostRecipePacket{})
+
+func (PlaceGhostRecipePacket) PacketUID() string {
+	return placeGhostRecipeUID
+}
+
+func (p PlaceGhostRecipePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteByteInt8(p.WindowID).WriteIdentifier(p.Recipe)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,42 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*PlayerAbilitiesPacket)(nil)
+
+// Player ability flags
+const (
+	AbilityInvulnerable = 0x01
+	AbilityFlying       = 0x02
+	AbilityAllowFlying  = 0x04
+	AbilityInstantBreak = 0x08
+)
+
+// https://wiki.vg/Protocol#Player_Abilities_.28clientbound.29
+type PlayerAbilitiesPacket struct {
+	Flags               int8    `mc:"byte"`
+	FlyingSpeed         float32 `mc:"float"`
+	FieldOfViewModifier float32 `mc:"float"`
+}
+
+func (PlayerAbilitiesPacket) MCPacketID() uint32 {
+	return 0x38
+}
+
+var playerAbilitiesUID = util.GetPacketUID(PlayerAbilitiesPacket{})
+
+func (PlayerAbilitiesPacket) PacketUID() string {
+	return playerAbilitiesUID
+}
+
+func (p PlayerAbilitiesPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteByteInt8(p.Flags)
+	w.WriteFloat(p.FlyingSpeed)
+	w.WriteFloat(p.FieldOfViewModifier)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,34 @@
+package play
+
+import (
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*PlayerInfoRemovePacket)(nil)
+
+// https://wiki.vg/Protocol#Player_Info_Remove
+type PlayerInfoRemovePacket struct {
+	UUIDs []uuid.UUID `mc:"array"`
+}
+
+func (PlayerInfoRemovePacket) MCPacketID() uint32 {
+	return 0x3D
+}
+
+var playerInfoRemoveUID = util.GetPacketUID(PlayerInfoRemovePacket{})
+
+func (PlayerInfoRemovePacket) PacketUID() string {
+	return playerInfoRemoveUID
+}
+
+func (p PlayerInfoRemovePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(int32(len(p.UUIDs)))
+	for _, u := range p.UUIDs {
+		w.WriteUUID(u)
+	}
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,90 @@
+package play
+
+import (
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/login"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/text"
+)
+
+var _ common.ClientboundPacket = (*PlayerInfoUpdatePacket)(nil)
+
+// Actions of Player Info Update, selecting the fields sent for every player.
+// Initialize Chat (0x02) isn't supported, chat isn't signed.
+const (
+	PlayerInfoAddPlayer         = 0x01
+	PlayerInfoUpdateGameMode    = 0x04
+	PlayerInfoUpdateListed      = 0x08
+	PlayerInfoUpdateLatency     = 0x10
+	PlayerInfoUpdateDisplayName = 0x20
+)
+
+// PlayerInfo is the entry of one player in the tab list.
+type PlayerInfo struct {
+	UUID       uuid.UUID        `mc:"uuid"`
+	Name       string           `mc:"string,max=16"`
+	Properties []login.Property `mc:"array"`
+	GameMode   int32            `mc:"varint"`
+	Listed     bool             `mc:"bool"`
+	Latency    int32            `mc:"varint"` // milliseconds
+	// nil to show the name
+	DisplayName *text.TextComponent `mc:"optional text component"`
+}
+
+// PlayerInfoUpdatePacket adds players to the client's player list or updates
+// them. Only the fields of the actions set are sent.
+//
+// https://wiki.vg/Protocol#Player_Info_Update
+type PlayerInfoUpdatePacket struct {
+	Actions uint8        `mc:"ubyte"`
+	Players []PlayerInfo `mc:"array"`
+}
+
+func (PlayerInfoUpdatePacket) MCPacketID() uint32 {
+	return 0x3E
+}
+
+var playerInfoUpdateUID = util.GetPacketUID(PlayerInfoUpdatePacket{})
+
+func (PlayerInfoUpdatePacket) PacketUID() string {
+	return playerInfoUpdateUID
+}
+
+func (p PlayerInfoUpdatePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteUbyte(p.Actions)
+	w.WriteVarInt(int32(len(p.Players)))
+	for _, info := range p.Players {
+		w.WriteUUID(info.UUID)
+		if p.Actions&PlayerInfoAddPlayer != 0 {
+			w.WriteString(info.Name)
+			w.WriteVarInt(int32(len(info.Properties)))
+			for _, prop := range info.Properties {
+				w.WriteString(prop.Name)
+				w.WriteString(prop.Value)
+				w.WriteBool(prop.Signature != "")
+				if prop.Signature != "" {
+					w.WriteString(prop.Signature)
+				}
+			}
+		}
+		if p.Actions&PlayerInfoUpdateGameMode != 0 {
+			w.WriteVarInt(info.GameMode)
+		}
+		if p.Actions&PlayerInfoUpdateListed != 0 {
+			w.WriteBool(info.Listed)
+		}
+		if p.Actions&PlayerInfoUpdateLatency != 0 {
+			w.WriteVarInt(info.Latency)
+		}
+		if p.Actions&PlayerInfoUpdateDisplayName != 0 {
+			w.WriteBool(info.DisplayName != nil)
+			if info.DisplayName != nil {
+				w.WriteTextComponent(*info.DisplayName)
+			}
+		}
+	}
+	return w.Bytes(), w.Err()
+}
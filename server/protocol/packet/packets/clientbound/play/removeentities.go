@@ -0,0 +1,33 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*RemoveEntitiesPacket)(nil)
+
+// https://wiki.vg/Protocol#Remove_Entities
+type RemoveEntitiesPacket struct {
+	EntityIDs []int32 `mc:"array"`
+}
+
+func (RemoveEntitiesPacket) MCPacketID() uint32 {
+	return 0x42
+}
+
+var removeEntitiesUID = util.GetPacketUID(RemoveEntitiesPacket{})
+
+func (RemoveEntitiesPacket) PacketUID() string {
+	return removeEntitiesUID
+}
+
+func (p RemoveEntitiesPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(int32(len(p.EntityIDs)))
+	for _, id := range p.EntityIDs {
+		w.WriteVarInt(id)
+	}
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,42 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetBlockDestroyStagePacket)(nil)
+
+// NoDestroyStage removes the cracks of a block.
+const NoDestroyStage uint8 = 0xFF
+
+// https://wiki.vg/Protocol#Set_Block_Destroy_Stage
+type SetBlockDestroyStagePacket struct {
+	// EntityID is the entity breaking the block, whose cracks replace the
+	// ones it showed before.
+	EntityID int32 `mc:"varint"`
+	X        int32 `mc:"position"`
+	Y        int32 `mc:"position"`
+	Z        int32 `mc:"position"`
+	// Stage is from 0 to 9, other values remove the cracks.
+	Stage uint8 `mc:"ubyte"`
+}
+
+func (SetBlockDestroyStagePacket) MCPacketID() uint32 {
+	return 0x06
+}
+
+var setBlockDestroyStageUID = util.GetPacketUID(SetBlockDestroyStagePacket{})
+
+func (SetBlockDestroyStagePacket) PacketUID() string {
+	return setBlockDestroyStageUID
+}
+
+func (p SetBlockDestroyStagePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WritePosition(p.X, p.Y, p.Z)
+	w.WriteUbyte(p.Stage)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,32 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetCenterChunkPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Center_Chunk
+type SetCenterChunkPacket struct {
+	ChunkX int32 `mc:"varint"`
+	ChunkZ int32 `mc:"varint"`
+}
+
+func (SetCenterChunkPacket) MCPacketID() uint32 {
+	return 0x54
+}
+
+var setCenterChunkUID = util.GetPacketUID(SetCenterChunkPacket{})
+
+func (SetCenterChunkPacket) PacketUID() string {
+	return setCenterChunkUID
+}
+
+func (p SetCenterChunkPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.ChunkX)
+	w.WriteVarInt(p.ChunkZ)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,42 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetContainerContentPacket)(nil)
+
+// SetContainerContentPacket sets every slot of a window and the stack carried
+// by the cursor.
+//
+// https://wiki.vg/Protocol#Set_Container_Content
+type SetContainerContentPacket struct {
+	WindowID uint8 `mc:"ubyte"`
+	// StateID is sent back by the client with its clicks.
+	StateID int32             `mc:"varint"`
+	Slots   []*item.ItemStack `mc:"array"`
+	Carried *item.ItemStack   `mc:"slot"`
+}
+
+func (SetContainerContentPacket) MCPacketID() uint32 {
+	return 0x13
+}
+
+var setContainerContentUID = util.GetPacketUID(SetContainerContentPacket{})
+
+func (SetContainerContentPacket) PacketUID() string {
+	return setContainerContentUID
+}
+
+func (p SetContainerContentPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteUbyte(p.WindowID).WriteVarInt(p.StateID).WriteVarInt(int32(len(p.Slots)))
+	for _, s := range p.Slots {
+		s.WriteSlot(w)
+	}
+	p.Carried.WriteSlot(w)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,38 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetContainerSlotPacket)(nil)
+
+// SetContainerSlotPacket sets a slot of a window, or with window and slot -1
+// the stack carried by the cursor.
+//
+// https://wiki.vg/Protocol#Set_Container_Slot
+type SetContainerSlotPacket struct {
+	WindowID int8            `mc:"byte"`
+	StateID  int32           `mc:"varint"`
+	Slot     int16           `mc:"short"`
+	Stack    *item.ItemStack `mc:"slot"`
+}
+
+func (SetContainerSlotPacket) MCPacketID() uint32 {
+	return 0x15
+}
+
+var setContainerSlotUID = util.GetPacketUID(SetContainerSlotPacket{})
+
+func (SetContainerSlotPacket) PacketUID() string {
+	return setContainerSlotUID
+}
+
+func (p SetContainerSlotPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteByteInt8(p.WindowID).WriteVarInt(p.StateID).WriteShort(p.Slot)
+	p.Stack.WriteSlot(w)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,36 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetDefaultSpawnPositionPacket)(nil)
+
+// SetDefaultSpawnPositionPacket sets where compasses point to.
+//
+// https://wiki.vg/Protocol#Set_Default_Spawn_Position
+type SetDefaultSpawnPositionPacket struct {
+	X     int32   `mc:"position"`
+	Y     int32   `mc:"position"`
+	Z     int32   `mc:"position"`
+	Angle float32 `mc:"float"`
+}
+
+func (SetDefaultSpawnPositionPacket) MCPacketID() uint32 {
+	return 0x56
+}
+
+var setDefaultSpawnPositionUID = util.GetPacketUID(SetDefaultSpawnPositionPacket{})
+
+func (SetDefaultSpawnPositionPacket) PacketUID() string {
+	return setDefaultSpawnPositionUID
+}
+
+func (p SetDefaultSpawnPositionPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WritePosition(p.X, p.Y, p.Z)
+	w.WriteFloat(p.Angle)
+	return w.Bytes(), w.Err()
+}
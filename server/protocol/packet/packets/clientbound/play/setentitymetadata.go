@@ -0,0 +1,36 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetEntityMetadataPacket)(nil)
+
+// SetEntityMetadataPacket updates fields of an entity's metadata. Fields not
+// sent keep their values.
+//
+// https://wiki.vg/Protocol#Set_Entity_Metadata
+type SetEntityMetadataPacket struct {
+	EntityID int32          `mc:"varint"`
+	Metadata []entity.Entry `mc:"entity metadata"`
+}
+
+func (SetEntityMetadataPacket) MCPacketID() uint32 {
+	return 0x58
+}
+
+var setEntityMetadataUID = util.GetPacketUID(SetEntityMetadataPacket{})
+
+func (SetEntityMetadataPacket) PacketUID() string {
+	return setEntityMetadataUID
+}
+
+func (p SetEntityMetadataPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	entity.WriteMetadata(w, p.Metadata)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,37 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetEntityVelocityPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Entity_Velocity
+type SetEntityVelocityPacket struct {
+	EntityID int32 `mc:"varint"`
+	// velocities are in 1/8000 blocks per tick
+	VelocityX int16 `mc:"short"`
+	VelocityY int16 `mc:"short"`
+	VelocityZ int16 `mc:"short"`
+}
+
+func (SetEntityVelocityPacket) MCPacketID() uint32 {
+	return 0x5A
+}
+
+var setEntityVelocityUID = util.GetPacketUID(SetEntityVelocityPacket{})
+
+func (SetEntityVelocityPacket) PacketUID() string {
+	return setEntityVelocityUID
+}
+
+func (p SetEntityVelocityPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteShort(p.VelocityX)
+	w.WriteShort(p.VelocityY)
+	w.WriteShort(p.VelocityZ)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,32 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetHeadRotationPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Head_Rotation
+type SetHeadRotationPacket struct {
+	EntityID int32   `mc:"varint"`
+	HeadYaw  float32 `mc:"angle"`
+}
+
+func (SetHeadRotationPacket) MCPacketID() uint32 {
+	return 0x48
+}
+
+var setHeadRotationUID = util.GetPacketUID(SetHeadRotationPacket{})
+
+func (SetHeadRotationPacket) PacketUID() string {
+	return setHeadRotationUID
+}
+
+func (p SetHeadRotationPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteAngle(p.HeadYaw)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,34 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetHealthPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Health
+type SetHealthPacket struct {
+	Health         float32 `mc:"float"` // 0 shows the death screen
+	Food           int32   `mc:"varint"`
+	FoodSaturation float32 `mc:"float"`
+}
+
+func (SetHealthPacket) MCPacketID() uint32 {
+	return 0x5D
+}
+
+var setHealthUID = util.GetPacketUID(SetHealthPacket{})
+
+func (SetHealthPacket) PacketUID() string {
+	return setHealthUID
+}
+
+func (p SetHealthPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteFloat(p.Health)
+	w.WriteVarInt(p.Food)
+	w.WriteFloat(p.FoodSaturation)
+	return w.Bytes(), w.Err()
+}
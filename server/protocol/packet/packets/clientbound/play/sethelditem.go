@@ -0,0 +1,31 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SetHeldItemPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Held_Item_.28clientbound.29
+type SetHeldItemPacket struct {
+	// hotbar slot, 0 to 8
+	Slot int8 `mc:"byte"`
+}
+
+func (SetHeldItemPacket) MCPacketID() uint32 {
+	return 0x53
+}
+
+var setHeldItemUID = util.GetPacketUID(SetHeldItemPacket{})
+
+func (SetHeldItemPacket) PacketUID() string {
+	return setHeldItemUID
+}
+
+func (p SetHeldItemPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteByteInt8(p.Slot)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,60 @@
+package play
+
+import (
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SpawnEntityPacket)(nil)
+
+// SpawnEntityPacket spawns any entity for the client, players included.
+//
+// https://wiki.vg/Protocol#Spawn_Entity
+type SpawnEntityPacket struct {
+	EntityID int32     `mc:"varint"`
+	UUID     uuid.UUID `mc:"uuid"`
+	// ID in the minecraft:entity_type registry
+	Type    int32   `mc:"varint"`
+	X       float64 `mc:"double"`
+	Y       float64 `mc:"double"`
+	Z       float64 `mc:"double"`
+	Pitch   float32 `mc:"angle"`
+	Yaw     float32 `mc:"angle"`
+	HeadYaw float32 `mc:"angle"`
+	// meaning depends on the entity type
+	Data int32 `mc:"varint"`
+	// velocities are in 1/8000 blocks per tick
+	VelocityX int16 `mc:"short"`
+	VelocityY int16 `mc:"short"`
+	VelocityZ int16 `mc:"short"`
+}
+
+func (SpawnEntityPacket) MCPacketID() uint32 {
+	return 0x01
+}
+
+var spawnEntityUID = util.GetPacketUID(SpawnEntityPacket{})
+
+func (SpawnEntityPacket) PacketUID() string {
+	return spawnEntityUID
+}
+
+func (p SpawnEntityPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteUUID(p.UUID)
+	w.WriteVarInt(p.Type)
+	w.WriteDouble(p.X)
+	w.WriteDouble(p.Y)
+	w.WriteDouble(p.Z)
+	w.WriteAngle(p.Pitch)
+	w.WriteAngle(p.Yaw)
+	w.WriteAngle(p.HeadYaw)
+	w.WriteVarInt(p.Data)
+	w.WriteShort(p.VelocityX)
+	w.WriteShort(p.VelocityY)
+	w.WriteShort(p.VelocityZ)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,55 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*SynchronizePlayerPositionPacket)(nil)
+
+// Flags making fields of Synchronize Player Position relative to the current
+// position.
+const (
+	RelativeX     = 0x01
+	RelativeY     = 0x02
+	RelativeZ     = 0x04
+	RelativeYaw   = 0x08
+	RelativePitch = 0x10
+)
+
+// SynchronizePlayerPositionPacket teleports the player. The client answers with
+// Confirm Teleportation carrying the teleport ID.
+//
+// https://wiki.vg/Protocol#Synchronize_Player_Position
+type SynchronizePlayerPositionPacket struct {
+	X          float64 `mc:"double"`
+	Y          float64 `mc:"double"`
+	Z          float64 `mc:"double"`
+	Yaw        float32 `mc:"float"`
+	Pitch      float32 `mc:"float"`
+	Flags      int8    `mc:"byte"`
+	TeleportID int32   `mc:"varint"`
+}
+
+func (SynchronizePlayerPositionPacket) MCPacketID() uint32 {
+	return 0x40
+}
+
+var synchronizePlayerPositionUID = util.GetPacketUID(SynchronizePlayerPositionPacket{})
+
+func (SynchronizePlayerPositionPacket) PacketUID() string {
+	return synchronizePlayerPositionUID
+}
+
+func (p SynchronizePlayerPositionPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteDouble(p.X)
+	w.WriteDouble(p.Y)
+	w.WriteDouble(p.Z)
+	w.WriteFloat(p.Yaw)
+	w.WriteFloat(p.Pitch)
+	w.WriteByteInt8(p.Flags)
+	w.WriteVarInt(p.TeleportID)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,34 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/text"
+)
+
+var _ common.ClientboundPacket = (*SystemChatMessagePacket)(nil)
+
+// https://wiki.vg/Protocol#System_Chat_Message
+type SystemChatMessagePacket struct {
+	Content text.TextComponent `mc:"textcomponent"`
+	// shown above the hotbar instead of in chat
+	Overlay bool `mc:"bool"`
+}
+
+func (SystemChatMessagePacket) MCPacketID() uint32 {
+	return 0x6C
+}
+
+var systemChatMessageUID = util.GetPacketUID(SystemChatMessagePacket{})
+
+func (SystemChatMessagePacket) PacketUID() string {
+	return systemChatMessageUID
+}
+
+func (p SystemChatMessagePacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteTextComponent(p.Content)
+	w.WriteBool(p.Overlay)
+	return w.Bytes(), w.Err()
+}
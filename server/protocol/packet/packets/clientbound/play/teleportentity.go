@@ -0,0 +1,42 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*TeleportEntityPacket)(nil)
+
+// https://wiki.vg/Protocol#Teleport_Entity
+type TeleportEntityPacket struct {
+	EntityID int32   `mc:"varint"`
+	X        float64 `mc:"double"`
+	Y        float64 `mc:"double"`
+	Z        float64 `mc:"double"`
+	Yaw      float32 `mc:"angle"`
+	Pitch    float32 `mc:"angle"`
+	OnGround bool    `mc:"bool"`
+}
+
+func (TeleportEntityPacket) MCPacketID() uint32 {
+	return 0x70
+}
+
+var teleportEntityUID = util.GetPacketUID(TeleportEntityPacket{})
+
+func (TeleportEntityPacket) PacketUID() string {
+	return teleportEntityUID
+}
+
+func (p TeleportEntityPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteDouble(p.X)
+	w.WriteDouble(p.Y)
+	w.WriteDouble(p.Z)
+	w.WriteAngle(p.Yaw)
+	w.WriteAngle(p.Pitch)
+	w.WriteBool(p.OnGround)
+	return w.Bytes(), w.Err()
+}
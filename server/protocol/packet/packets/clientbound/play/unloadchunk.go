@@ -0,0 +1,33 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UnloadChunkPacket)(nil)
+
+// https://wiki.vg/Protocol#Unload_Chunk
+type UnloadChunkPacket struct {
+	// sent Z first
+	ChunkZ int32 `mc:"int"`
+	ChunkX int32 `mc:"int"`
+}
+
+func (UnloadChunkPacket) MCPacketID() uint32 {
+	return 0x21
+}
+
+var unloadChunkUID = util.GetPacketUID(UnloadChunkPacket{})
+
+func (UnloadChunkPacket) PacketUID() string {
+	return unloadChunkUID
+}
+
+func (p UnloadChunkPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteInt(p.ChunkZ)
+	w.WriteInt(p.ChunkX)
+	return w.Bytes(), w.Err()
+}
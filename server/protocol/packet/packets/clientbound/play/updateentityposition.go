@@ -0,0 +1,42 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UpdateEntityPositionPacket)(nil)
+
+// UpdateEntityPositionPacket moves an entity by less than 8 blocks. Longer
+// moves need Teleport Entity.
+//
+// https://wiki.vg/Protocol#Update_Entity_Position
+type UpdateEntityPositionPacket struct {
+	EntityID int32 `mc:"varint"`
+	// change of the coordinates, in 1/4096 blocks
+	DeltaX   int16 `mc:"short"`
+	DeltaY   int16 `mc:"short"`
+	DeltaZ   int16 `mc:"short"`
+	OnGround bool  `mc:"bool"`
+}
+
+func (UpdateEntityPositionPacket) MCPacketID() uint32 {
+	return 0x2E
+}
+
+var updateEntityPositionUID = util.GetPacketUID(UpdateEntityPositionPacket{})
+
+func (UpdateEntityPositionPacket) PacketUID() string {
+	return updateEntityPositionUID
+}
+
+func (p UpdateEntityPositionPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteShort(p.DeltaX)
+	w.WriteShort(p.DeltaY)
+	w.WriteShort(p.DeltaZ)
+	w.WriteBool(p.OnGround)
+	return w.Bytes(), w.Err()
+}
@@ -0,0 +1,43 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UpdateEntityPositionAndRotationPacket)(nil)
+
+// https://wiki.vg/Protocol#Update_Entity_Position_and_Rotation
+type UpdateEntityPositionAndRotationPacket struct {
+	EntityID int32 `mc:"varint"`
+	// change of the coordinates, in 1/4096 blocks
+	DeltaX   int16   `mc:"short"`
+	DeltaY   int16   `mc:"short"`
+	DeltaZ   int16   `mc:"short"`
+	Yaw      float32 `mc:"angle"`
+	Pitch    float32 `mc:"angle"`
+	OnGround bool    `mc:"bool"`
+}
+
+func (UpdateEntityPositionAndRotationPacket) MCPacketID() uint32 {
+	return 0x2F
+}
+
+var updateEntityPositionAndRotationUID = util.GetPacketUID(UpdateEntityPositionAndRotationPacket{})
+
+func (UpdateEntityPositionAndRotationPacket) PacketUID() string {
+	return updateEntityPositionAndRotationUID
+}
+
+func (p UpdateEntityPositionAndRotationPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteShort(p.DeltaX)
+	w.WriteShort(p.DeltaY)
+	w.WriteShort(p.DeltaZ)
+	w.WriteAngle(p.Yaw)
+	w.WriteAngle(p.Pitch)
+	w.WriteBool(p.OnGround)
+	return w.Bytes(), w.Err()
+}
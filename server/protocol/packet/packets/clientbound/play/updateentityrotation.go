@@ -0,0 +1,36 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UpdateEntityRotationPacket)(nil)
+
+// https://wiki.vg/Protocol#Update_Entity_Rotation
+type UpdateEntityRotationPacket struct {
+	EntityID int32   `mc:"varint"`
+	Yaw      float32 `mc:"angle"`
+	Pitch    float32 `mc:"angle"`
+	OnGround bool    `mc:"bool"`
+}
+
+func (UpdateEntityRotationPacket) MCPacketID() uint32 {
+	return 0x30
+}
+
+var updateEntityRotationUID = util.GetPacketUID(UpdateEntityRotationPacket{})
+
+func (UpdateEntityRotationPacket) PacketUID() string {
+	return updateEntityRotationUID
+}
+
+func (p UpdateEntityRotationPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.EntityID)
+	w.WriteAngle(p.Yaw)
+	w.WriteAngle(p.Pitch)
+	w.WriteBool(p.OnGround)
+	return w.Bytes(), w.Err()
+}
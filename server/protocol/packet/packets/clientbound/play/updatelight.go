@@ -0,0 +1,33 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+var _ common.ClientboundPacket = (*UpdateLightPacket)(nil)
+
+// https://wiki.vg/Protocol#Update_Light
+type UpdateLightPacket struct {
+	Chunk *chunk.Chunk
+}
+
+func (UpdateLightPacket) MCPacketID() uint32 {
+	return 0x2A
+}
+
+var updateLightUID = util.GetPacketUID(UpdateLightPacket{})
+
+func (UpdateLightPacket) PacketUID() string {
+	return updateLightUID
+}
+
+func (p UpdateLightPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(p.Chunk.X)
+	w.WriteVarInt(p.Chunk.Z)
+	p.Chunk.EncodeLight(w)
+	return w.Bytes(), w.Err()
+}
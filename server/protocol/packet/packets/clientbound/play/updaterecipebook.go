@@ -0,0 +1,73 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UpdateRecipeBookPacket)(nil)
+
+// Actions of the Update Recipe Book packet
+const (
+	RecipeBookInit = iota
+	RecipeBookAdd
+	RecipeBookRemove
+)
+
+// RecipeBookSettings are whether each tab of the recipe book is open and
+// filters out the recipes the player can't make.
+type RecipeBookSettings struct {
+	CraftingOpen       bool `mc:"bool"`
+	CraftingFilter     bool `mc:"bool"`
+	SmeltingOpen       bool `mc:"bool"`
+	SmeltingFilter     bool `mc:"bool"`
+	BlastFurnaceOpen   bool `mc:"bool"`
+	BlastFurnaceFilter bool `mc:"bool"`
+	SmokerOpen         bool `mc:"bool"`
+	SmokerFilter       bool `mc:"bool"`
+}
+
+// UpdateRecipeBookPacket unlocks or locks recipes of the recipe book. With the
+// init action, Recipes are those unlocked and Highlighted those of them shown
+// as new.
+//
+// https://wiki.vg/Protocol#Update_Recipe_Book
+type UpdateRecipeBookPacket struct {
+	Action      int32              `mc:"varint"`
+	Settings    RecipeBookSettings `mc:"recipe book settings"`
+	Recipes     []string           `mc:"array"`
+	Highlighted []string           `mc:"array"`
+}
+
+func (UpdateRecipeBookPacket) MCPacketID() uint32 {
+	return 0x41
+}
+
+var updateRecipeBookUID = util.GetPacketUID(UpdateRecipeBookPacket{})
+
+func (UpdateRecipeBookPacket) PacketUID() string {
+	return updateRecipeBookUID
+}
+
+func (p UpdateRecipeBookPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	s := p.Settings
+	w.WriteVarInt(p.Action)
+	w.WriteBool(s.CraftingOpen).WriteBool(s.CraftingFilter)
+	w.WriteBool(s.SmeltingOpen).WriteBool(s.SmeltingFilter)
+	w.WriteBool(s.BlastFurnaceOpen).WriteBool(s.BlastFurnaceFilter)
+	w.WriteBool(s.SmokerOpen).WriteBool(s.SmokerFilter)
+	writeIdentifiers(w, p.Recipes)
+	if p.Action == RecipeBookInit {
+		writeIdentifiers(w, p.Highlighted)
+	}
+	return w.Bytes(), w.Err()
+}
+
+func writeIdentifiers(w *io.Writer, ids []string) {
+	w.WriteVarInt(int32(len(ids)))
+	for _, id := range ids {
+		w.WriteIdentifier(id)
+	}
+}
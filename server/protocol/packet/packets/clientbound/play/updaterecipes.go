@@ -0,0 +1,37 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/recipe"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ClientboundPacket = (*UpdateRecipesPacket)(nil)
+
+// UpdateRecipesPacket sends every recipe of the server, which the client shows
+// in the recipe book once unlocked.
+//
+// https://wiki.vg/Protocol#Update_Recipes
+type UpdateRecipesPacket struct {
+	Recipes []recipe.Recipe `mc:"array"`
+}
+
+func (UpdateRecipesPacket) MCPacketID() uint32 {
+	return 0x77
+}
+
+var updateRecipesUID = util.GetPacketUID(UpdateRecipesPacket{})
+
+func (UpdateRecipesPacket) PacketUID() string {
+	return updateRecipesUID
+}
+
+func (p UpdateRecipesPacket) Encode() ([]byte, error) {
+	w := io.NewWriter()
+	w.WriteVarInt(int32(len(p.Recipes)))
+	for _, r := range p.Recipes {
+		recipe.Write(w, r)
+	}
+	return w.Bytes(), w.Err()
+}
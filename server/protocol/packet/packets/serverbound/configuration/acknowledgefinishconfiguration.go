@@ -0,0 +1,39 @@
+package configuration
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*AcknowledgeFinishConfigurationPacket)(nil)
+var _ common.StateTransition = AcknowledgeFinishConfigurationPacket{}
+
+// AcknowledgeFinishConfigurationPacket answers Finish Configuration, moving
+// the client on to play.
+//
+// https://wiki.vg/Protocol#Acknowledge_Finish_Configuration
+type AcknowledgeFinishConfigurationPacket struct{}
+
+func (AcknowledgeFinishConfigurationPacket) MCPacketID() uint32 {
+	return 0x03
+}
+
+var acknowledgeFinishConfigurationUID = util.GetPacketUID(AcknowledgeFinishConfigurationPacket{})
+
+func (AcknowledgeFinishConfigurationPacket) PacketUID() string {
+	return acknowledgeFinishConfigurationUID
+}
+
+func (AcknowledgeFinishConfigurationPacket) NextState() common.State {
+	return common.Play
+}
+
+func DecodeAcknowledgeFinishConfiguration(r *io.Reader) (common.ServerboundPacket, error) {
+	return &AcknowledgeFinishConfigurationPacket{}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Configuration, AcknowledgeFinishConfigurationPacket{}.MCPacketID(), DecodeAcknowledgeFinishConfiguration)
+}
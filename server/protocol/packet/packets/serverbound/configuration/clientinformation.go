@@ -0,0 +1,80 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*ClientInformationPacket)(nil)
+
+// Chat modes
+const (
+	ChatEnabled      = 0
+	ChatCommandsOnly = 1
+	ChatHidden       = 2
+)
+
+// Main hands
+const (
+	LeftHand  = 0
+	RightHand = 1
+)
+
+// https://wiki.vg/Protocol#Client_Information_.28configuration.29
+type ClientInformationPacket struct {
+	Locale              string `mc:"string,max=16"`
+	ViewDistance        int8   `mc:"byte"`
+	ChatMode            int32  `mc:"varint"`
+	ChatColors          bool   `mc:"bool"`
+	DisplayedSkinParts  uint8  `mc:"ubyte"`
+	MainHand            int32  `mc:"varint"`
+	EnableTextFiltering bool   `mc:"bool"`
+	AllowServerListings bool   `mc:"bool"`
+}
+
+func (ClientInformationPacket) MCPacketID() uint32 {
+	return 0x00
+}
+
+var clientInformationUID = util.GetPacketUID(ClientInformationPacket{})
+
+func (ClientInformationPacket) PacketUID() string {
+	return clientInformationUID
+}
+
+// ReadClientInformation reads the client information fields, which are shared
+// with the play state version of the packet.
+func ReadClientInformation(r *io.Reader) (ClientInformationPacket, error) {
+	p := ClientInformationPacket{
+		Locale:              r.ReadString(),
+		ViewDistance:        r.ReadByteInt8(),
+		ChatMode:            r.ReadVarInt(),
+		ChatColors:          r.ReadBool(),
+		DisplayedSkinParts:  r.ReadUbyte(),
+		MainHand:            r.ReadVarInt(),
+		EnableTextFiltering: r.ReadBool(),
+		AllowServerListings: r.ReadBool(),
+	}
+
+	if r.Err() != nil {
+		return ClientInformationPacket{}, fmt.Errorf("error decoding client information packet: %w", r.Err())
+	}
+
+	return p, nil
+}
+
+func DecodeClientInformation(r *io.Reader) (common.ServerboundPacket, error) {
+	p, err := ReadClientInformation(r)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Configuration, ClientInformationPacket{}.MCPacketID(), DecodeClientInformation)
+}
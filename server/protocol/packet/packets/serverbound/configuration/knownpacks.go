@@ -0,0 +1,65 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*KnownPacksPacket)(nil)
+
+// maxKnownPacks is how many packs vanilla servers accept in the answer.
+const maxKnownPacks = 64
+
+// KnownPack is a data pack by namespace, ID and version.
+type KnownPack struct {
+	Namespace string `mc:"string"`
+	ID        string `mc:"string"`
+	Version   string `mc:"string"`
+}
+
+// KnownPacksPacket answers the server's Known Packs with the packs the client
+// has of them.
+//
+// https://wiki.vg/Protocol#Serverbound_Known_Packs
+type KnownPacksPacket struct {
+	KnownPacks []KnownPack `mc:"array"`
+}
+
+func (KnownPacksPacket) MCPacketID() uint32 {
+	return 0x07
+}
+
+var knownPacksUID = util.GetPacketUID(KnownPacksPacket{})
+
+func (KnownPacksPacket) PacketUID() string {
+	return knownPacksUID
+}
+
+func DecodeKnownPacks(r *io.Reader) (common.ServerboundPacket, error) {
+	count := r.ReadVarInt()
+	if r.Err() == nil && (count < 0 || count > maxKnownPacks) {
+		return nil, fmt.Errorf("error decoding known packs packet: %d packs", count)
+	}
+
+	p := &KnownPacksPacket{}
+	for i := int32(0); i < count && r.Err() == nil; i++ {
+		p.KnownPacks = append(p.KnownPacks, KnownPack{
+			Namespace: r.ReadString(),
+			ID:        r.ReadString(),
+			Version:   r.ReadString(),
+		})
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding known packs packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Configuration, KnownPacksPacket{}.MCPacketID(), DecodeKnownPacks)
+}
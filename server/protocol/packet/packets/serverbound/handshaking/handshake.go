@@ -10,12 +10,13 @@ import (
 )
 
 var _ common.ServerboundPacket = (*HandshakePacket)(nil)
+var _ common.StateTransition = HandshakePacket{}
 
 type HandshakePacket struct {
 	ProtocolVersion int32  `mc:"varint"`
 	ServerAddress   string `mc:"string,max=255"`
 	ServerPort      uint16 `mc:"ushort"`
-	NextState       int32  `mc:"varint"`
+	Intent          int32  `mc:"varint"`
 }
 
 func (HandshakePacket) MCPacketID() uint32 {
@@ -42,10 +43,25 @@ func DecodeHandshake(r *io.Reader) (common.ServerboundPacket, error) {
 		ProtocolVersion: p_version,
 		ServerAddress:   s_address,
 		ServerPort:      s_port,
-		NextState:       n_state,
+		Intent:          n_state,
 	}, nil
 }
 
 func init() {
 	packet.RegisterDecoder(common.Handshaking, HandshakePacket{}.MCPacketID(), DecodeHandshake)
 }
+
+// Handshake intents
+const (
+	IntentStatus   = 1
+	IntentLogin    = 2
+	IntentTransfer = 3
+)
+
+// NextState switches to status or login, which transfers go through too.
+func (p HandshakePacket) NextState() common.State {
+	if p.Intent == IntentStatus {
+		return common.Status
+	}
+	return common.Login
+}
@@ -0,0 +1,39 @@
+package login
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*LoginAcknowledgedPacket)(nil)
+var _ common.StateTransition = LoginAcknowledgedPacket{}
+
+// LoginAcknowledgedPacket answers Login Success, moving the client on to
+// configuration.
+//
+// https://wiki.vg/Protocol#Login_Acknowledged
+type LoginAcknowledgedPacket struct{}
+
+func (LoginAcknowledgedPacket) MCPacketID() uint32 {
+	return 0x03
+}
+
+var loginAcknowledgedUID = util.GetPacketUID(LoginAcknowledgedPacket{})
+
+func (LoginAcknowledgedPacket) PacketUID() string {
+	return loginAcknowledgedUID
+}
+
+func (LoginAcknowledgedPacket) NextState() common.State {
+	return common.Configuration
+}
+
+func DecodeLoginAcknowledged(r *io.Reader) (common.ServerboundPacket, error) {
+	return &LoginAcknowledgedPacket{}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Login, LoginAcknowledgedPacket{}.MCPacketID(), DecodeLoginAcknowledged)
+}
@@ -0,0 +1,50 @@
+package login
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*LoginStartPacket)(nil)
+
+// https://wiki.vg/Protocol#Login_Start
+type LoginStartPacket struct {
+	Name string `mc:"string,max=16"`
+	// the UUID of the client's account, unused by offline servers
+	PlayerUUID uuid.UUID `mc:"uuid"`
+}
+
+func (LoginStartPacket) MCPacketID() uint32 {
+	return 0x00
+}
+
+var loginStartUID = util.GetPacketUID(LoginStartPacket{})
+
+func (LoginStartPacket) PacketUID() string {
+	return loginStartUID
+}
+
+func DecodeLoginStart(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &LoginStartPacket{
+		Name:       r.ReadString(),
+		PlayerUUID: r.ReadUUID(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding login start packet: %w", r.Err())
+	}
+	if len(p.Name) == 0 || len(p.Name) > 16 {
+		return nil, fmt.Errorf("error decoding login start packet: invalid name length %d", len(p.Name))
+	}
+
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Login, LoginStartPacket{}.MCPacketID(), DecodeLoginStart)
+}
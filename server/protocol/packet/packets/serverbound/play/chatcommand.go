@@ -0,0 +1,44 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*ChatCommandPacket)(nil)
+
+// https://wiki.vg/Protocol#Chat_Command
+type ChatCommandPacket struct {
+	// without the leading slash
+	Command string `mc:"string,max=32767"`
+}
+
+func (ChatCommandPacket) MCPacketID() uint32 {
+	return 0x04
+}
+
+var chatCommandUID = util.GetPacketUID(ChatCommandPacket{})
+
+func (ChatCommandPacket) PacketUID() string {
+	return chatCommandUID
+}
+
+func DecodeChatCommand(r *io.Reader) (common.ServerboundPacket, error) {
+	command := r.ReadString()
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding chat command packet: %w", r.Err())
+	}
+
+	return &ChatCommandPacket{
+		Command: command,
+	}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, ChatCommandPacket{}.MCPacketID(), DecodeChatCommand)
+}
@@ -0,0 +1,43 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*ChunkBatchReceivedPacket)(nil)
+
+// https://wiki.vg/Protocol#Chunk_Batch_Received
+type ChunkBatchReceivedPacket struct {
+	ChunksPerTick float32 `mc:"float"`
+}
+
+func (ChunkBatchReceivedPacket) MCPacketID() uint32 {
+	return 0x08
+}
+
+var chunkBatchReceivedUID = util.GetPacketUID(ChunkBatchReceivedPacket{})
+
+func (ChunkBatchReceivedPacket) PacketUID() string {
+	return chunkBatchReceivedUID
+}
+
+func DecodeChunkBatchReceived(r *io.Reader) (common.ServerboundPacket, error) {
+	chunksPerTick := r.ReadFloat()
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding chunk batch received packet: %w", r.Err())
+	}
+
+	return &ChunkBatchReceivedPacket{
+		ChunksPerTick: chunksPerTick,
+	}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, ChunkBatchReceivedPacket{}.MCPacketID(), DecodeChunkBatchReceived)
+}
@@ -0,0 +1,96 @@
+package play
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*ClickContainerPacket)(nil)
+
+// maxChangedSlots is how many slots a click may claim to change, as many as
+// the largest window has.
+const maxChangedSlots = 128
+
+// ClickContainerPacket is sent when the player clicks a slot of a window, with
+// what the client predicts the click changes.
+//
+// https://wiki.vg/Protocol#Click_Container
+type ClickContainerPacket struct {
+	WindowID uint8 `mc:"ubyte"`
+	// StateID is the last state ID the client was sent.
+	StateID int32 `mc:"varint"`
+	Slot    int16 `mc:"short"`
+	Button  int8  `mc:"byte"`
+	Mode    int32 `mc:"varint"`
+	// ChangedSlots are the slots the client changed, by slot.
+	ChangedSlots map[int16]*item.ItemStack `mc:"array"`
+	Carried      *item.ItemStack           `mc:"slot"`
+
+	// Unreadable is set when a slot has components that can't be decoded, in
+	// which case the slots and carried stack are unknown and the window has to
+	// be resent.
+	Unreadable bool
+}
+
+func (ClickContainerPacket) MCPacketID() uint32 {
+	return 0x0E
+}
+
+var clickContainerUID = util.GetPacketUID(ClickContainerPacket{})
+
+func (ClickContainerPacket) PacketUID() string {
+	return clickContainerUID
+}
+
+func DecodeClickContainer(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &ClickContainerPacket{
+		WindowID: r.ReadUbyte(),
+		StateID:  r.ReadVarInt(),
+		Slot:     r.ReadShort(),
+		Button:   r.ReadByteInt8(),
+		Mode:     r.ReadVarInt(),
+	}
+	n := r.ReadVarInt()
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding click container packet: %w", r.Err())
+	}
+	if n < 0 || n > maxChangedSlots {
+		return nil, fmt.Errorf("error decoding click container packet: %d changed slots", n)
+	}
+
+	p.ChangedSlots = make(map[int16]*item.ItemStack, n)
+	for range n {
+		slot := r.ReadShort()
+		s, err := item.ReadSlot(r)
+		if err != nil {
+			return unreadableClick(p, err)
+		}
+		p.ChangedSlots[slot] = s
+	}
+	carried, err := item.ReadSlot(r)
+	if err != nil {
+		return unreadableClick(p, err)
+	}
+	p.Carried = carried
+	return p, nil
+}
+
+// unreadableClick returns a click whose slots couldn't be decoded, unless the
+// packet ended early.
+func unreadableClick(p *ClickContainerPacket, err error) (common.ServerboundPacket, error) {
+	if errors.Is(err, item.ErrUnsupportedComponent) {
+		p.ChangedSlots, p.Unreadable = nil, true
+		return p, nil
+	}
+	return nil, fmt.Errorf("error decoding click container packet: %w", err)
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, ClickContainerPacket{}.MCPacketID(), DecodeClickContainer)
+}
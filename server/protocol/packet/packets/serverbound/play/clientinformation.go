@@ -0,0 +1,38 @@
+package play
+
+import (
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/configuration"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*ClientInformationPacket)(nil)
+
+// https://wiki.vg/Protocol#Client_Information_.28play.29
+type ClientInformationPacket struct {
+	configuration.ClientInformationPacket
+}
+
+func (ClientInformationPacket) MCPacketID() uint32 {
+	return 0x0A
+}
+
+var clientInformationUID = util.GetPacketUID(ClientInformationPacket{})
+
+func (ClientInformationPacket) PacketUID() string {
+	return clientInformationUID
+}
+
+func DecodeClientInformation(r *io.Reader) (common.ServerboundPacket, error) {
+	p, err := configuration.ReadClientInformation(r)
+	if err != nil {
+		return nil, err
+	}
+	return &ClientInformationPacket{p}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, ClientInformationPacket{}.MCPacketID(), DecodeClientInformation)
+}
@@ -0,0 +1,43 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*CloseContainerPacket)(nil)
+
+// CloseContainerPacket is sent when the player closes a window, 0 for its own
+// inventory.
+//
+// https://wiki.vg/Protocol#Close_Container_2
+type CloseContainerPacket struct {
+	WindowID uint8 `mc:"ubyte"`
+}
+
+func (CloseContainerPacket) MCPacketID() uint32 {
+	return 0x0F
+}
+
+var closeContainerUID = util.GetPacketUID(CloseContainerPacket{})
+
+func (CloseContainerPacket) PacketUID() string {
+	return closeContainerUID
+}
+
+func DecodeCloseContainer(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &CloseContainerPacket{WindowID: r.ReadUbyte()}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding close container packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, CloseContainerPacket{}.MCPacketID(), DecodeCloseContainer)
+}
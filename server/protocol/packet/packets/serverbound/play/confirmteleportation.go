@@ -0,0 +1,42 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*ConfirmTeleportationPacket)(nil)
+
+// https://wiki.vg/Protocol#Confirm_Teleportation
+type ConfirmTeleportationPacket struct {
+	TeleportID int32 `mc:"varint"`
+}
+
+func (ConfirmTeleportationPacket) MCPacketID() uint32 {
+	return 0x00
+}
+
+var confirmTeleportationUID = util.GetPacketUID(ConfirmTeleportationPacket{})
+
+func (ConfirmTeleportationPacket) PacketUID() string {
+	return confirmTeleportationUID
+}
+
+func DecodeConfirmTeleportation(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &ConfirmTeleportationPacket{
+		TeleportID: r.ReadVarInt(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding confirm teleportation packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, ConfirmTeleportationPacket{}.MCPacketID(), DecodeConfirmTeleportation)
+}
@@ -0,0 +1,42 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*KeepAlivePacket)(nil)
+
+// https://wiki.vg/Protocol#Serverbound_Keep_Alive_.28play.29
+type KeepAlivePacket struct {
+	KeepAliveID int64 `mc:"long"`
+}
+
+func (KeepAlivePacket) MCPacketID() uint32 {
+	return 0x18
+}
+
+var keepAliveUID = util.GetPacketUID(KeepAlivePacket{})
+
+func (KeepAlivePacket) PacketUID() string {
+	return keepAliveUID
+}
+
+func DecodeKeepAlive(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &KeepAlivePacket{
+		KeepAliveID: r.ReadLong(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding keep alive packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, KeepAlivePacket{}.MCPacketID(), DecodeKeepAlive)
+}
@@ -0,0 +1,50 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*PlaceRecipePacket)(nil)
+
+// PlaceRecipePacket is sent when the player clicks a recipe in the recipe
+// book, to fill the crafting grid of a window with its ingredients, as many
+// times as possible when shift clicking.
+//
+// https://wiki.vg/Protocol#Place_Recipe
+type PlaceRecipePacket struct {
+	WindowID int8   `mc:"byte"`
+	Recipe   string `mc:"identifier"`
+	MakeAll  bool   `mc:"bool"`
+}
+
+func (PlaceRecipePacket) MCPacketID() uint32 {
+	return 0x22
+}
+
+var placeRecipeUID = util.GetPacketUID(PlaceRecipePacket{})
+
+func (PlaceRecipePacket) PacketUID() string {
+	return placeRecipeUID
+}
+
+func DecodePlaceRecipe(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &PlaceRecipePacket{
+		WindowID: r.ReadByteInt8(),
+		Recipe:   r.ReadIdentifier(),
+		MakeAll:  r.ReadBool(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding place recipe packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, PlaceRecipePacket{}.MCPacketID(), DecodePlaceRecipe)
+}
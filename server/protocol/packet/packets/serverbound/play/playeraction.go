@@ -0,0 +1,73 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*PlayerActionPacket)(nil)
+
+// Player Action statuses
+const (
+	StartedDigging = iota
+	CancelledDigging
+	FinishedDigging
+	DropItemStack
+	DropItem
+	ShootArrowOrFinishEating
+	SwapItemInHand
+)
+
+// Faces of blocks, as Player Action and Use Item On send them
+const (
+	FaceBottom = iota
+	FaceTop
+	FaceNorth
+	FaceSouth
+	FaceWest
+	FaceEast
+)
+
+// PlayerActionPacket is sent when the player digs a block, among other
+// actions.
+//
+// https://wiki.vg/Protocol#Player_Action
+type PlayerActionPacket struct {
+	Status int32 `mc:"varint"`
+	X      int32 `mc:"position"`
+	Y      int32 `mc:"position"`
+	Z      int32 `mc:"position"`
+	Face   int8  `mc:"byte"`
+	// Sequence is acknowledged with Acknowledge Block Change.
+	Sequence int32 `mc:"varint"`
+}
+
+func (PlayerActionPacket) MCPacketID() uint32 {
+	return 0x24
+}
+
+var playerActionUID = util.GetPacketUID(PlayerActionPacket{})
+
+func (PlayerActionPacket) PacketUID() string {
+	return playerActionUID
+}
+
+func DecodePlayerAction(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &PlayerActionPacket{Status: r.ReadVarInt()}
+	p.X, p.Y, p.Z = r.ReadPosition()
+	p.Face = r.ReadByteInt8()
+	p.Sequence = r.ReadVarInt()
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding player action packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, PlayerActionPacket{}.MCPacketID(), DecodePlayerAction)
+}
@@ -0,0 +1,63 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*PlayerCommandPacket)(nil)
+
+// Player Command actions
+const (
+	StartSneaking = iota
+	StopSneaking
+	LeaveBed
+	StartSprinting
+	StopSprinting
+	StartHorseJump
+	StopHorseJump
+	OpenVehicleInventory
+	StartFlyingWithElytra
+)
+
+// PlayerCommandPacket is sent when the player starts or stops sneaking or
+// sprinting, among other actions.
+//
+// https://wiki.vg/Protocol#Player_Command
+type PlayerCommandPacket struct {
+	EntityID int32 `mc:"varint"`
+	Action   int32 `mc:"varint"`
+	// strength of a horse jump, from 0 to 100
+	JumpBoost int32 `mc:"varint"`
+}
+
+func (PlayerCommandPacket) MCPacketID() uint32 {
+	return 0x25
+}
+
+var playerCommandUID = util.GetPacketUID(PlayerCommandPacket{})
+
+func (PlayerCommandPacket) PacketUID() string {
+	return playerCommandUID
+}
+
+func DecodePlayerCommand(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &PlayerCommandPacket{
+		EntityID:  r.ReadVarInt(),
+		Action:    r.ReadVarInt(),
+		JumpBoost: r.ReadVarInt(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding player command packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, PlayerCommandPacket{}.MCPacketID(), DecodePlayerCommand)
+}
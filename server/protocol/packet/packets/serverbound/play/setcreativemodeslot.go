@@ -0,0 +1,58 @@
+package play
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SetCreativeModeSlotPacket)(nil)
+
+// SetCreativeModeSlotPacket is sent when a player in creative sets a slot of
+// its inventory window, or with slot -1 throws a stack.
+//
+// https://wiki.vg/Protocol#Set_Creative_Mode_Slot
+type SetCreativeModeSlotPacket struct {
+	Slot  int16           `mc:"short"`
+	Stack *item.ItemStack `mc:"slot"`
+
+	// Unreadable is set when the stack has components that can't be decoded,
+	// in which case it is unknown and the inventory has to be resent.
+	Unreadable bool
+}
+
+func (SetCreativeModeSlotPacket) MCPacketID() uint32 {
+	return 0x32
+}
+
+var setCreativeModeSlotUID = util.GetPacketUID(SetCreativeModeSlotPacket{})
+
+func (SetCreativeModeSlotPacket) PacketUID() string {
+	return setCreativeModeSlotUID
+}
+
+func DecodeSetCreativeModeSlot(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &SetCreativeModeSlotPacket{Slot: r.ReadShort()}
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding set creative mode slot packet: %w", r.Err())
+	}
+	s, err := item.ReadSlot(r)
+	if errors.Is(err, item.ErrUnsupportedComponent) {
+		p.Unreadable = true
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error decoding set creative mode slot packet: %w", err)
+	}
+	p.Stack = s
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SetCreativeModeSlotPacket{}.MCPacketID(), DecodeSetCreativeModeSlot)
+}
@@ -0,0 +1,43 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SetHeldItemPacket)(nil)
+
+// SetHeldItemPacket is sent when the player selects another hotbar slot.
+//
+// https://wiki.vg/Protocol#Set_Held_Item_2
+type SetHeldItemPacket struct {
+	// hotbar slot, 0 to 8
+	Slot int16 `mc:"short"`
+}
+
+func (SetHeldItemPacket) MCPacketID() uint32 {
+	return 0x2F
+}
+
+var setHeldItemUID = util.GetPacketUID(SetHeldItemPacket{})
+
+func (SetHeldItemPacket) PacketUID() string {
+	return setHeldItemUID
+}
+
+func DecodeSetHeldItem(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &SetHeldItemPacket{Slot: r.ReadShort()}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding set held item packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SetHeldItemPacket{}.MCPacketID(), DecodeSetHeldItem)
+}
@@ -0,0 +1,42 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SetPlayerOnGroundPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Player_On_Ground
+type SetPlayerOnGroundPacket struct {
+	OnGround bool `mc:"bool"`
+}
+
+func (SetPlayerOnGroundPacket) MCPacketID() uint32 {
+	return 0x1D
+}
+
+var setPlayerOnGroundUID = util.GetPacketUID(SetPlayerOnGroundPacket{})
+
+func (SetPlayerOnGroundPacket) PacketUID() string {
+	return setPlayerOnGroundUID
+}
+
+func DecodeSetPlayerOnGround(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &SetPlayerOnGroundPacket{
+		OnGround: r.ReadBool(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding set player on ground packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SetPlayerOnGroundPacket{}.MCPacketID(), DecodeSetPlayerOnGround)
+}
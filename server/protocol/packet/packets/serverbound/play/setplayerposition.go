@@ -0,0 +1,52 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SetPlayerPositionPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Player_Position
+type SetPlayerPositionPacket struct {
+	X        float64 `mc:"double"`
+	FeetY    float64 `mc:"double"`
+	Z        float64 `mc:"double"`
+	OnGround bool    `mc:"bool"`
+}
+
+func (SetPlayerPositionPacket) MCPacketID() uint32 {
+	return 0x1A
+}
+
+var setPlayerPositionUID = util.GetPacketUID(SetPlayerPositionPacket{})
+
+func (SetPlayerPositionPacket) PacketUID() string {
+	return setPlayerPositionUID
+}
+
+func DecodeSetPlayerPosition(r *io.Reader) (common.ServerboundPacket, error) {
+	x := r.ReadDouble()
+	y := r.ReadDouble()
+	z := r.ReadDouble()
+	onGround := r.ReadBool()
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding set player position packet: %w", r.Err())
+	}
+
+	return &SetPlayerPositionPacket{
+		X:        x,
+		FeetY:    y,
+		Z:        z,
+		OnGround: onGround,
+	}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SetPlayerPositionPacket{}.MCPacketID(), DecodeSetPlayerPosition)
+}
@@ -0,0 +1,58 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SetPlayerPositionAndRotationPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Player_Position_and_Rotation
+type SetPlayerPositionAndRotationPacket struct {
+	X        float64 `mc:"double"`
+	FeetY    float64 `mc:"double"`
+	Z        float64 `mc:"double"`
+	Yaw      float32 `mc:"float"`
+	Pitch    float32 `mc:"float"`
+	OnGround bool    `mc:"bool"`
+}
+
+func (SetPlayerPositionAndRotationPacket) MCPacketID() uint32 {
+	return 0x1B
+}
+
+var setPlayerPositionAndRotationUID = util.GetPacketUID(SetPlayerPositionAndRotationPacket{})
+
+func (SetPlayerPositionAndRotationPacket) PacketUID() string {
+	return setPlayerPositionAndRotationUID
+}
+
+func DecodeSetPlayerPositionAndRotation(r *io.Reader) (common.ServerboundPacket, error) {
+	x := r.ReadDouble()
+	y := r.ReadDouble()
+	z := r.ReadDouble()
+	yaw := r.ReadFloat()
+	pitch := r.ReadFloat()
+	onGround := r.ReadBool()
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding set player position and rotation packet: %w", r.Err())
+	}
+
+	return &SetPlayerPositionAndRotationPacket{
+		X:        x,
+		FeetY:    y,
+		Z:        z,
+		Yaw:      yaw,
+		Pitch:    pitch,
+		OnGround: onGround,
+	}, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SetPlayerPositionAndRotationPacket{}.MCPacketID(), DecodeSetPlayerPositionAndRotation)
+}
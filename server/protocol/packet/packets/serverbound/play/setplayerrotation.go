@@ -0,0 +1,46 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SetPlayerRotationPacket)(nil)
+
+// https://wiki.vg/Protocol#Set_Player_Rotation
+type SetPlayerRotationPacket struct {
+	Yaw      float32 `mc:"float"`
+	Pitch    float32 `mc:"float"`
+	OnGround bool    `mc:"bool"`
+}
+
+func (SetPlayerRotationPacket) MCPacketID() uint32 {
+	return 0x1C
+}
+
+var setPlayerRotationUID = util.GetPacketUID(SetPlayerRotationPacket{})
+
+func (SetPlayerRotationPacket) PacketUID() string {
+	return setPlayerRotationUID
+}
+
+func DecodeSetPlayerRotation(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &SetPlayerRotationPacket{
+		Yaw:      r.ReadFloat(),
+		Pitch:    r.ReadFloat(),
+		OnGround: r.ReadBool(),
+	}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding set player rotation packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SetPlayerRotationPacket{}.MCPacketID(), DecodeSetPlayerRotation)
+}
@@ -0,0 +1,40 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*SwingArmPacket)(nil)
+
+// https://wiki.vg/Protocol#Swing_Arm
+type SwingArmPacket struct {
+	Hand int32 `mc:"varint"`
+}
+
+func (SwingArmPacket) MCPacketID() uint32 {
+	return 0x36
+}
+
+var swingArmUID = util.GetPacketUID(SwingArmPacket{})
+
+func (SwingArmPacket) PacketUID() string {
+	return swingArmUID
+}
+
+func DecodeSwingArm(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &SwingArmPacket{Hand: r.ReadVarInt()}
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding swing arm packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, SwingArmPacket{}.MCPacketID(), DecodeSwingArm)
+}
@@ -0,0 +1,69 @@
+package play
+
+import (
+	"fmt"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/server/protocol/packet"
+	"github.com/hunterros-s/algernon/server/util"
+)
+
+var _ common.ServerboundPacket = (*UseItemOnPacket)(nil)
+
+// Hands
+const (
+	MainHand = iota
+	OffHand
+)
+
+// UseItemOnPacket is sent when the player right clicks a block, to place a
+// block or use it.
+//
+// https://wiki.vg/Protocol#Use_Item_On
+type UseItemOnPacket struct {
+	Hand int32 `mc:"varint"`
+	// the clicked block
+	X    int32 `mc:"position"`
+	Y    int32 `mc:"position"`
+	Z    int32 `mc:"position"`
+	Face int32 `mc:"varint"`
+	// where the face was clicked, from 0 to 1 within the block
+	CursorX float32 `mc:"float"`
+	CursorY float32 `mc:"float"`
+	CursorZ float32 `mc:"float"`
+	// InsideBlock is whether the player's head is inside a block.
+	InsideBlock bool `mc:"bool"`
+	// Sequence is acknowledged with Acknowledge Block Change.
+	Sequence int32 `mc:"varint"`
+}
+
+func (UseItemOnPacket) MCPacketID() uint32 {
+	return 0x38
+}
+
+var useItemOnUID = util.GetPacketUID(UseItemOnPacket{})
+
+func (UseItemOnPacket) PacketUID() string {
+	return useItemOnUID
+}
+
+func DecodeUseItemOn(r *io.Reader) (common.ServerboundPacket, error) {
+	p := &UseItemOnPacket{Hand: r.ReadVarInt()}
+	p.X, p.Y, p.Z = r.ReadPosition()
+	p.Face = r.ReadVarInt()
+	p.CursorX = r.ReadFloat()
+	p.CursorY = r.ReadFloat()
+	p.CursorZ = r.ReadFloat()
+	p.InsideBlock = r.ReadBool()
+	p.Sequence = r.ReadVarInt()
+
+	if r.Err() != nil {
+		return nil, fmt.Errorf("error decoding use item on packet: %w", r.Err())
+	}
+	return p, nil
+}
+
+func init() {
+	packet.RegisterDecoder(common.Play, UseItemOnPacket{}.MCPacketID(), DecodeUseItemOn)
+}
@@ -41,6 +41,9 @@ func GetNewMessageCallback(handler PacketHandler, logger zerolog.Logger) func(c
 			logger.Warn().Err(err).Msg("Packet error")
 			return
 		}
+		if t, ok := packet.(common.StateTransition); ok {
+			c.SetState(t.NextState())
+		}
 
 		handler(c, packet)
 	}
@@ -69,3 +72,22 @@ func ReadUncompressedPacket(state common.State, b []byte) (common.ServerboundPac
 
 	return packet, nil
 }
+
+// WriteUncompressedPacket frames a clientbound packet as length, packet id and
+// packet data.
+func WriteUncompressedPacket(p common.ClientboundPacket) ([]byte, error) {
+	data, err := p.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("error encoding packet %#x: %w", p.MCPacketID(), err)
+	}
+
+	body := io.NewWriter().
+		WriteVarInt(int32(p.MCPacketID())).
+		WriteFixedByteArray(data)
+
+	frame := io.NewWriter().WriteByteArray(body.Bytes())
+	if frame.Err() != nil {
+		return nil, frame.Err()
+	}
+	return frame.Bytes(), nil
+}
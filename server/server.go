@@ -1,15 +1,23 @@
 package server
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 
 	"github.com/hunterros-s/algernon/config"
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/recipe"
 	"github.com/hunterros-s/algernon/server/common"
 	"github.com/hunterros-s/algernon/server/listener"
 	"github.com/hunterros-s/algernon/server/protocol"
 	"github.com/hunterros-s/algernon/server/supervisor"
+	"github.com/hunterros-s/algernon/world"
+	"github.com/hunterros-s/algernon/world/block"
+	"github.com/hunterros-s/algernon/world/generator"
+	"github.com/hunterros-s/algernon/world/region"
 )
 
 // should not re-create the tcpserver in tcp. just create tcpserver here and add callbacks
@@ -19,6 +27,7 @@ type Server struct {
 	listener   *listener.Listener
 	signals    chan os.Signal
 	supervisor *supervisor.Supervisor
+	world      *world.World
 }
 
 func (svr *Server) Start() {
@@ -34,6 +43,9 @@ func (svr *Server) Stop() {
 	defer svr.config.Logger.Info().Msg("Server shut down.")
 	svr.listener.Stop()
 	svr.supervisor.Stop()
+	if err := svr.world.Close(); err != nil {
+		svr.config.Logger.Error().Err(err).Msg("Unable to save world")
+	}
 }
 
 // this server should be the main processing center/thread i think.
@@ -41,9 +53,36 @@ func NewServer(cfg *config.ServerConfig) (*Server, error) {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
+	if cfg.BlockReport != "" {
+		if err := block.LoadDefault(cfg.BlockReport); err != nil {
+			return nil, fmt.Errorf("error loading block report: %w", err)
+		}
+	}
+	if cfg.ItemReport != "" {
+		if err := item.LoadDefault(cfg.ItemReport); err != nil {
+			return nil, fmt.Errorf("error loading item report: %w", err)
+		}
+	}
+	// recipes are loaded after the items they use
+	if cfg.RecipeDir != "" {
+		skipped, err := recipe.LoadDefault(cfg.RecipeDir)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Logger.Info().Int("skipped", skipped).Int("recipes", len(recipe.Default().Recipes())).Msg("Loaded recipes")
+	}
+
+	w, err := newWorld(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	l := listener.NewListener(cfg)
 
-	sv := supervisor.NewSupervisor(cfg.Logger)
+	sv, err := supervisor.NewSupervisor(cfg, w)
+	if err != nil {
+		return nil, err
+	}
 	// need to give this access to a central processing channel. it will send packets to that.
 	// that will decide what to do to the actual mc server, i.e. change a block, send a chat, leave, join.
 	// cant think how it should be structured.
@@ -53,13 +92,51 @@ func NewServer(cfg *config.ServerConfig) (*Server, error) {
 			Client: c,
 		})
 	}, cfg.Logger))
+	l.SetOnClientDisconnected(func(c common.Client, _ error) {
+		sv.Disconnect(c)
+	})
+	l.SetOnClientError(func(c common.Client, _ error) {
+		sv.Disconnect(c)
+	})
 
 	return &Server{
 		config:     cfg,
 		listener:   l,
 		signals:    signals,
 		supervisor: sv,
+		world:      w,
 	}, nil
 }
 
+func newWorld(cfg *config.ServerConfig) (*world.World, error) {
+	var gen world.Generator
+	switch cfg.Generator {
+	case "flat":
+		flat, err := generator.ParseFlatPreset(cfg.FlatPreset)
+		if err != nil {
+			return nil, err
+		}
+		gen = flat
+	case "noise":
+		terrain, err := generator.NewTerrain(cfg.Seed)
+		if err != nil {
+			return nil, err
+		}
+		gen = terrain
+	default:
+		return nil, fmt.Errorf("unknown world generator: %s", cfg.Generator)
+	}
+
+	var storage *region.Storage
+	if cfg.WorldDir != "" {
+		s, err := region.NewStorage(filepath.Join(cfg.WorldDir, "region"), world.MinY, world.Height, region.DefaultMaxOpen)
+		if err != nil {
+			return nil, fmt.Errorf("error opening world: %w", err)
+		}
+		storage = s
+	}
+
+	return world.New(gen, storage, cfg.GenerationWorkers), nil
+}
+
 // need to create another server that is running. this server will process the packets. do stuff to the server. send packets out to respective clients.
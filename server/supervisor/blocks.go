@@ -0,0 +1,418 @@
+package supervisor
+
+import (
+	"math"
+	"strings"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/inventory"
+	"github.com/hunterros-s/algernon/server/common"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/play"
+	"github.com/hunterros-s/algernon/world"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// Reach of players, in blocks from their eyes, by whether they are in creative
+// mode
+const (
+	blockReach         = 4.5
+	creativeBlockReach = 5
+	// reachTolerance is how much further than their reach players are trusted,
+	// as vanilla allows for latency.
+	reachTolerance = 1
+)
+
+// minBreakProgress is how much of a block players must have broken when they
+// finish digging it, short of all of it for latency as in vanilla.
+const minBreakProgress = 0.7
+
+// toolTiers are the tiers and speeds of tools, by the prefix of their names.
+var toolTiers = map[string]block.Tool{
+	"minecraft:wooden_":    {Tier: block.TierWood, Speed: 2},
+	"minecraft:stone_":     {Tier: block.TierStone, Speed: 4},
+	"minecraft:iron_":      {Tier: block.TierIron, Speed: 6},
+	"minecraft:diamond_":   {Tier: block.TierDiamond, Speed: 8},
+	"minecraft:netherite_": {Tier: block.TierNetherite, Speed: 9},
+	"minecraft:golden_":    {Tier: block.TierGold, Speed: 12},
+}
+
+// heldTool returns the tool a player breaks blocks with, its hand unless it
+// holds a pickaxe, axe, shovel or hoe.
+func (sv *Supervisor) heldTool(p *Player) block.Tool {
+	held := p.Inventory.Held()
+	if held.Empty() {
+		return block.Hand
+	}
+	prefix, kind, ok := strings.Cut(held.Item.Name, "_")
+	if !ok {
+		return block.Hand
+	}
+	tool, ok := toolTiers[prefix+"_"]
+	if !ok || kind != block.Pickaxe && kind != block.Axe && kind != block.Shovel && kind != block.Hoe {
+		return block.Hand
+	}
+	tool.Kind = kind
+	return tool
+}
+
+// heldBlock returns the block a player places with a hand, the block named
+// like the item it holds.
+func (sv *Supervisor) heldBlock(p *Player, hand int32) (*block.State, bool) {
+	held := p.Inventory.Slot(p.Inventory.HandSlot(hand))
+	if held.Empty() {
+		return nil, false
+	}
+	registry := block.Default()
+	b, ok := registry.Block(held.Item.Name)
+	if !ok {
+		return nil, false
+	}
+	return registry.State(b.Default)
+}
+
+// playerAction handles Player Action for digging and throwing the held item.
+func (sv *Supervisor) playerAction(p *Player, a *play.PlayerActionPacket) {
+	x, y, z := int(a.X), int(a.Y), int(a.Z)
+	switch a.Status {
+	case play.DropItem, play.DropItemStack:
+		sv.throwHeld(p, a.Status == play.DropItemStack)
+		return
+	case play.SwapItemInHand:
+		sv.swapHands(p)
+		return
+	case play.StartedDigging:
+		sv.startDigging(p, x, y, z, block.Direction(a.Face))
+	case play.CancelledDigging:
+		sv.stopDigging(p)
+	case play.FinishedDigging:
+		sv.finishDigging(p, x, y, z)
+	default:
+		return
+	}
+	sv.send(p.client, clientbound.AcknowledgeBlockChangePacket{Sequence: a.Sequence})
+}
+
+// startDigging starts breaking a block, breaking it at once for players in
+// creative mode and blocks they break in a tick.
+func (sv *Supervisor) startDigging(p *Player, x, y, z int, face block.Direction) {
+	sv.stopDigging(p)
+	if !sv.mayBuild(p, x, y, z) || !face.Valid() ||
+		!sv.canReach(p, x, y, z) || !sv.canSee(p, x, y, z, faceCenter(x, y, z, face)) {
+		sv.rollBack(p, x, y, z)
+		return
+	}
+
+	state := sv.world.Block(x, y, z)
+	if block.Default().IsAir(state) {
+		return
+	}
+	if p.GameMode == Creative {
+		sv.breakBlock(p, x, y, z)
+		return
+	}
+	progress := sv.breakProgress(p, state)
+	if progress <= 0 {
+		sv.rollBack(p, x, y, z)
+		return
+	}
+	if progress >= 1 {
+		sv.breakBlock(p, x, y, z)
+		return
+	}
+	p.digging = true
+	p.digX, p.digY, p.digZ = x, y, z
+	p.digProgress = progress
+	p.digStage = clientbound.NoDestroyStage
+}
+
+// stopDigging stops breaking the block being dug, removing its cracks.
+func (sv *Supervisor) stopDigging(p *Player) {
+	if !p.digging {
+		return
+	}
+	p.digging = false
+	if p.digStage != clientbound.NoDestroyStage {
+		sv.sendDestroyStage(p, clientbound.NoDestroyStage)
+	}
+}
+
+// finishDigging breaks the block being dug if the player dug it long enough,
+// and otherwise puts it back.
+func (sv *Supervisor) finishDigging(p *Player, x, y, z int) {
+	done := p.digging && p.digX == x && p.digY == y && p.digZ == z && p.digProgress >= minBreakProgress
+	sv.stopDigging(p)
+	if !done || !sv.mayBuild(p, x, y, z) {
+		sv.rollBack(p, x, y, z)
+		return
+	}
+	sv.breakBlock(p, x, y, z)
+}
+
+// tickDigging adds a tick of digging to the block a player digs, showing the
+// cracks to the players around as they grow.
+func (sv *Supervisor) tickDigging(p *Player) {
+	if !p.digging {
+		return
+	}
+	state := sv.world.Block(p.digX, p.digY, p.digZ)
+	if block.Default().IsAir(state) {
+		sv.stopDigging(p)
+		return
+	}
+	p.digProgress += sv.breakProgress(p, state)
+	if stage := uint8(min(p.digProgress, 0.9) * 10); stage != p.digStage {
+		p.digStage = stage
+		sv.sendDestroyStage(p, stage)
+	}
+}
+
+// breakProgress returns how much of a block a player breaks in a tick.
+func (sv *Supervisor) breakProgress(p *Player, state block.StateID) float64 {
+	registry := block.Default()
+	eye := sv.world.Block(int(math.Floor(p.X)), int(math.Floor(p.Y+p.eyeHeight())), int(math.Floor(p.Z)))
+	underWater := false
+	if s, ok := registry.State(eye); ok {
+		underWater = s.Block.Name == "minecraft:water" || s.Get("waterlogged") == "true"
+	}
+	return registry.BreakProgress(state, sv.heldTool(p), p.OnGround, underWater)
+}
+
+// sendDestroyStage shows the cracks of the block a player digs to the other
+// players that have it loaded.
+func (sv *Supervisor) sendDestroyStage(p *Player, stage uint8) {
+	packet := clientbound.SetBlockDestroyStagePacket{
+		EntityID: p.EntityID,
+		X:        int32(p.digX),
+		Y:        int32(p.digY),
+		Z:        int32(p.digZ),
+		Stage:    stage,
+	}
+	for _, other := range sv.players.All() {
+		if other != p && other.playing && other.chunks.sent(world.ChunkPosAt(p.digX, p.digZ)) {
+			sv.send(other.client, packet)
+		}
+	}
+}
+
+// breakBlock replaces a block with air, along with the blocks that can't stay
+// without it.
+func (sv *Supervisor) breakBlock(p *Player, x, y, z int) {
+	// the block entity goes with the block
+	data, _ := sv.world.BlockEntity(x, y, z)
+	if !sv.setBlocks(block.Change{X: x, Y: y, Z: z, State: block.Air}) {
+		sv.rollBack(p, x, y, z)
+		return
+	}
+	sv.dropContainer(x, y, z, data)
+}
+
+// useItemOn handles Use Item On, opening the window of the clicked block or
+// placing the block the player holds against the clicked face, or in place of
+// the clicked block if it can be replaced. Sneaking players holding items
+// place them rather than open windows, as in vanilla.
+func (sv *Supervisor) useItemOn(p *Player, u *play.UseItemOnPacket) {
+	defer sv.send(p.client, clientbound.AcknowledgeBlockChangePacket{Sequence: u.Sequence})
+	x, y, z := int(u.X), int(u.Y), int(u.Z)
+	face := block.Direction(u.Face)
+	if !face.Valid() || !validCursor(u.CursorX, u.CursorY, u.CursorZ) {
+		return
+	}
+	sneaking := p.Metadata.Flag(entity.Flags, entity.FlagSneaking)
+	holding := !p.Inventory.Held().Empty() || !p.Inventory.Slot(inventory.Offhand).Empty()
+	if u.Hand == play.MainHand && (!sneaking || !holding) && sv.canReach(p, x, y, z) && sv.useBlock(p, x, y, z) {
+		return
+	}
+	if !sv.placeBlock(p, u, x, y, z, face) {
+		sv.rollBack(p, x, y, z)
+		dx, dy, dz := face.Offset()
+		sv.rollBack(p, x+dx, y+dy, z+dz)
+	}
+}
+
+// placeBlock places the block a player holds against a face of the block at
+// x, y, z, reporting whether it did.
+func (sv *Supervisor) placeBlock(p *Player, u *play.UseItemOnPacket, x, y, z int, face block.Direction) bool {
+	state, ok := sv.heldBlock(p, u.Hand)
+	if !ok || !sv.canReach(p, x, y, z) {
+		return false
+	}
+	cursor := [3]float64{
+		float64(x) + 0.5 + (float64(u.CursorX)-0.5)*0.98,
+		float64(y) + 0.5 + (float64(u.CursorY)-0.5)*0.98,
+		float64(z) + 0.5 + (float64(u.CursorZ)-0.5)*0.98,
+	}
+	if !sv.canSee(p, x, y, z, cursor) {
+		return false
+	}
+
+	registry := block.Default()
+	placement := &block.Placement{
+		X: x, Y: y, Z: z,
+		Face:             face,
+		HitX:             float64(x) + float64(u.CursorX),
+		HitY:             float64(y) + float64(u.CursorY),
+		HitZ:             float64(z) + float64(u.CursorZ),
+		ReplacingClicked: true,
+		Yaw:              p.Yaw,
+		Pitch:            p.Pitch,
+		Sneaking:         p.Metadata.Flag(entity.Flags, entity.FlagSneaking),
+		World:            sv.world,
+	}
+	if !registry.CanReplace(sv.world.Block(x, y, z), state, placement) {
+		dx, dy, dz := face.Offset()
+		placement.X, placement.Y, placement.Z = x+dx, y+dy, z+dz
+		placement.ReplacingClicked = false
+		if !registry.CanReplace(sv.world.Block(placement.X, placement.Y, placement.Z), state, placement) {
+			return false
+		}
+	}
+
+	changes := registry.Place(state, placement)
+	for _, c := range changes {
+		if !sv.mayBuild(p, c.X, c.Y, c.Z) || sv.obstructed(c.State, c.X, c.Y, c.Z) {
+			return false
+		}
+	}
+	if len(changes) == 0 || !sv.setBlocks(changes...) {
+		return false
+	}
+	if p.GameMode != Creative {
+		slot := p.Inventory.HandSlot(u.Hand)
+		held := p.Inventory.Slot(slot)
+		p.Inventory.SetSlot(slot, held.WithCount(held.Count-1))
+	}
+	return true
+}
+
+// validCursor reports whether the cursor of Use Item On is within the clicked
+// block.
+func validCursor(x, y, z float32) bool {
+	return x >= 0 && x <= 1 && y >= 0 && y <= 1 && z >= 0 && z <= 1
+}
+
+// obstructed reports whether a player or a living entity stands where a state
+// would collide with it.
+func (sv *Supervisor) obstructed(state block.StateID, x, y, z int) bool {
+	var boxes []block.Box
+	for _, p := range sv.players.All() {
+		if p.playing && p.GameMode != Spectator {
+			boxes = append(boxes, entity.BoundingBox{Width: 0.6, Height: 1.8}.At(p.X, p.Y, p.Z))
+		}
+	}
+	for _, id := range sv.entities.Entities() {
+		identity, _ := sv.entities.Identity(id)
+		if entity.TypeOf(identity.Type).Motion != entity.MotionLiving {
+			continue
+		}
+		pos, _ := sv.entities.Position(id)
+		size, _ := sv.entities.BoundingBox(id)
+		boxes = append(boxes, size.At(pos.X, pos.Y, pos.Z))
+	}
+
+	for _, shape := range block.Default().CollisionShape(state) {
+		shape = shape.Offset(float64(x), float64(y), float64(z))
+		for _, b := range boxes {
+			if shape.Intersects(b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setBlocks changes blocks and the blocks around them that change with them,
+// and sends every change to the players that have it loaded. It reports
+// whether the blocks were loaded to change them in.
+func (sv *Supervisor) setBlocks(changes ...block.Change) bool {
+	changed, ok := sv.world.SetBlocks(changes...)
+	if !ok {
+		return false
+	}
+	for _, c := range changed {
+		packet := clientbound.BlockUpdatePacket{X: int32(c.X), Y: int32(c.Y), Z: int32(c.Z), BlockState: int32(c.State)}
+		sv.sendToChunk(world.ChunkPosAt(c.X, c.Z), packet)
+	}
+	return true
+}
+
+// sendToChunk sends packets to the players that have a chunk loaded.
+func (sv *Supervisor) sendToChunk(pos world.ChunkPos, packets ...common.ClientboundPacket) {
+	for _, p := range sv.players.All() {
+		if p.playing && p.chunks.sent(pos) {
+			sv.send(p.client, packets...)
+		}
+	}
+}
+
+// rollBack sends a player the actual state of a block it predicted a change
+// of, which it shows once the change is acknowledged.
+func (sv *Supervisor) rollBack(p *Player, x, y, z int) {
+	state := sv.world.Block(x, y, z)
+	sv.send(p.client, clientbound.BlockUpdatePacket{X: int32(x), Y: int32(y), Z: int32(z), BlockState: int32(state)})
+}
+
+// mayBuild reports whether a player may change a block: players in adventure
+// or spectator mode can't and neither can anyone around the spawn point, when
+// it is protected.
+func (sv *Supervisor) mayBuild(p *Player, x, y, z int) bool {
+	if !p.playing || p.GameMode == Adventure || p.GameMode == Spectator {
+		return false
+	}
+	if sv.spawnProtection <= 0 {
+		return true
+	}
+	return max(abs(x-sv.spawn.x), abs(z-sv.spawn.z)) > sv.spawnProtection
+}
+
+// canReach reports whether a block is within the reach of a player, measured
+// from its eyes to the nearest point of the block.
+func (sv *Supervisor) canReach(p *Player, x, y, z int) bool {
+	reach := blockReach
+	if p.GameMode == Creative {
+		reach = creativeBlockReach
+	}
+	ex, ey, ez := p.X, p.Y+p.eyeHeight(), p.Z
+	dx := distanceToRange(ex, float64(x), float64(x+1))
+	dy := distanceToRange(ey, float64(y), float64(y+1))
+	dz := distanceToRange(ez, float64(z), float64(z+1))
+	limit := reach + reachTolerance
+	return dx*dx+dy*dy+dz*dz < limit*limit
+}
+
+// canSee reports whether nothing solid stands between the eyes of a player and
+// a point of a block, falling back on the middle of the block.
+func (sv *Supervisor) canSee(p *Player, x, y, z int, point [3]float64) bool {
+	ex, ey, ez := p.X, p.Y+p.eyeHeight(), p.Z
+	for _, to := range [][3]float64{point, {float64(x) + 0.5, float64(y) + 0.5, float64(z) + 0.5}} {
+		hx, hy, hz, hit := sv.world.FirstObstruction(ex, ey, ez, to[0], to[1], to[2])
+		if !hit || hx == x && hy == y && hz == z {
+			return true
+		}
+	}
+	return false
+}
+
+// faceCenter returns the middle of a face of a block, just inside it.
+func faceCenter(x, y, z int, face block.Direction) [3]float64 {
+	dx, dy, dz := face.Offset()
+	return [3]float64{
+		float64(x) + 0.5 + float64(dx)*0.49,
+		float64(y) + 0.5 + float64(dy)*0.49,
+		float64(z) + 0.5 + float64(dz)*0.49,
+	}
+}
+
+// distanceToRange returns how far a coordinate is from a range of them.
+func distanceToRange(v, from, to float64) float64 {
+	return math.Max(0, math.Max(from-v, v-to))
+}
+
+// swingArm shows a player swinging its arm to the players seeing it.
+func (sv *Supervisor) swingArm(p *Player, hand int32) {
+	animation := clientbound.AnimationSwingMainArm
+	if hand == play.OffHand {
+		animation = clientbound.AnimationSwingOffhand
+	}
+	sv.tracker.sendToViewers(p.EntityID, clientbound.EntityAnimationPacket{EntityID: p.EntityID, Animation: animation})
+}
@@ -0,0 +1,218 @@
+package supervisor
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/world"
+)
+
+// Chunk sending rates, as negotiated by vanilla clients with Chunk Batch
+// Received.
+const (
+	minChunksPerTick     = 0.01
+	maxChunksPerTick     = 64
+	initialChunksPerTick = 9
+
+	// batches that may be in flight once the client acknowledged one. Only a
+	// single batch is sent before that.
+	maxUnacknowledgedBatches = 10
+)
+
+// minViewDistance is the smallest view distance clients get.
+const minViewDistance = 2
+
+// chunkTracker keeps track of the chunks a client has loaded. Chunks coming
+// into view are sent nearest first in batches paced by the client, and chunks
+// leaving it are unloaded. Chunks in view are retained in the world.
+type chunkTracker struct {
+	world *world.World
+	send  func(packets ...common.ClientboundPacket)
+
+	maxDistance int // the server's view distance
+	distance    int
+	center      world.ChunkPos
+	started     bool // whether the center is known yet
+
+	// chunks in view, true once sent to the client
+	tracked map[world.ChunkPos]bool
+	// chunks in view not sent yet, nearest first
+	queue []world.ChunkPos
+
+	chunksPerTick     float32
+	quota             float32
+	unacknowledged    int
+	maxUnacknowledged int
+}
+
+func newChunkTracker(w *world.World, viewDistance int, send func(packets ...common.ClientboundPacket)) *chunkTracker {
+	return &chunkTracker{
+		world:             w,
+		send:              send,
+		maxDistance:       max(viewDistance, minViewDistance),
+		distance:          max(viewDistance, minViewDistance),
+		tracked:           make(map[world.ChunkPos]bool),
+		chunksPerTick:     initialChunksPerTick,
+		maxUnacknowledged: 1,
+	}
+}
+
+// setViewDistance applies the view distance the client asked for, limited by
+// the server's.
+func (t *chunkTracker) setViewDistance(distance int) {
+	distance = max(minViewDistance, min(distance, t.maxDistance))
+	if distance == t.distance {
+		return
+	}
+	t.distance = distance
+	if t.started {
+		t.update()
+	}
+}
+
+// move centers the view on the chunk containing a block position.
+func (t *chunkTracker) move(x, z float64) {
+	pos := chunkPosOf(x, z)
+	if t.started && pos == t.center {
+		return
+	}
+	t.center = pos
+	t.started = true
+
+	t.send(play.SetCenterChunkPacket{ChunkX: pos.X, ChunkZ: pos.Z})
+	t.update()
+}
+
+// update unloads the chunks that left the view and queues the ones that came
+// into it.
+func (t *chunkTracker) update() {
+	for pos, sent := range t.tracked {
+		if inView(t.center, t.distance, pos) {
+			continue
+		}
+		if sent {
+			t.send(play.UnloadChunkPacket{ChunkZ: pos.Z, ChunkX: pos.X})
+		}
+		t.world.Release(pos)
+		delete(t.tracked, pos)
+	}
+
+	t.queue = t.queue[:0]
+	spiral(t.center, t.distance+1, func(pos world.ChunkPos) {
+		if !inView(t.center, t.distance, pos) {
+			return
+		}
+		sent, ok := t.tracked[pos]
+		if !ok {
+			t.tracked[pos] = false
+			t.world.Retain(pos)
+		}
+		if !sent {
+			t.queue = append(t.queue, pos)
+		}
+	})
+}
+
+// tick requests the queued chunks from the world and sends the loaded ones in
+// a batch, as many as the client's rate allows.
+func (t *chunkTracker) tick() {
+	if len(t.queue) == 0 {
+		return
+	}
+
+	limit := 0
+	if t.unacknowledged < t.maxUnacknowledged {
+		t.quota = min(t.quota+t.chunksPerTick, max(1, t.chunksPerTick))
+		limit = int(t.quota)
+	}
+
+	packets := []common.ClientboundPacket{play.ChunkBatchStartPacket{}}
+	remaining := t.queue[:0]
+	for _, pos := range t.queue {
+		// requesting chunks that won't be sent yet gets them generating
+		c, ok := t.world.Request(pos)
+		if ok && len(packets)-1 < limit {
+			packets = append(packets, play.ChunkDataPacket{Chunk: c})
+			t.tracked[pos] = true
+			continue
+		}
+		remaining = append(remaining, pos)
+	}
+	t.queue = remaining
+
+	count := len(packets) - 1
+	if count == 0 {
+		return
+	}
+	t.quota -= float32(count)
+	t.unacknowledged++
+	t.send(append(packets, play.ChunkBatchFinishedPacket{BatchSize: int32(count)})...)
+}
+
+// acknowledge handles Chunk Batch Received, which carries the rate the client
+// wants chunks at.
+func (t *chunkTracker) acknowledge(chunksPerTick float32) {
+	t.unacknowledged = max(0, t.unacknowledged-1)
+	t.maxUnacknowledged = maxUnacknowledgedBatches
+
+	if math.IsNaN(float64(chunksPerTick)) {
+		chunksPerTick = minChunksPerTick
+	}
+	t.chunksPerTick = max(minChunksPerTick, min(chunksPerTick, maxChunksPerTick))
+}
+
+// sent reports whether the client has a chunk loaded.
+func (t *chunkTracker) sent(pos world.ChunkPos) bool {
+	return t.tracked[pos]
+}
+
+// close releases every chunk in view.
+func (t *chunkTracker) close() {
+	for pos := range t.tracked {
+		t.world.Release(pos)
+	}
+	t.tracked = make(map[world.ChunkPos]bool)
+	t.queue = nil
+	t.started = false
+}
+
+// inView reports whether a chunk is close enough to the center to be sent.
+// The view is round, like vanilla's, with the ring of chunks bordering it
+// included so the visible ones have all their neighbors.
+func inView(center world.ChunkPos, distance int, pos world.ChunkPos) bool {
+	dx := max(0, abs(int(pos.X-center.X))-1)
+	dz := max(0, abs(int(pos.Z-center.Z))-1)
+	far := max(0, max(dx, dz)-1)
+	near := min(dx, dz)
+	return near*near+far*far < distance*distance
+}
+
+// spiral calls f for the chunks of a square around center, walking outwards
+// ring by ring.
+func spiral(center world.ChunkPos, radius int, f func(world.ChunkPos)) {
+	f(center)
+	for r := 1; r <= radius; r++ {
+		// each ring starts at its corner with the lowest x and z, clockwise
+		x, z := -r, -r
+		for _, d := range [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}} {
+			for i := 0; i < 2*r; i++ {
+				f(world.ChunkPos{X: center.X + int32(x), Z: center.Z + int32(z)})
+				x += d[0]
+				z += d[1]
+			}
+		}
+	}
+}
+
+// chunkPosOf returns the chunk containing a position.
+func chunkPosOf(x, z float64) world.ChunkPos {
+	return world.ChunkPosAt(int(math.Floor(x)), int(math.Floor(z)))
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,73 @@
+package supervisor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hunterros-s/algernon/logger"
+	"github.com/hunterros-s/algernon/server/common"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// command handles a chat command. args are the words after its name.
+type command func(sv *Supervisor, c common.Client, args []string)
+
+var commands = map[string]command{
+	"tps": tpsCommand,
+}
+
+// runCommand runs a command typed in chat, given without the leading slash.
+func (sv *Supervisor) runCommand(c common.Client, line string) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+
+	cmd, ok := commands[strings.ToLower(fields[0])]
+	if !ok {
+		sv.sendMessage(c, text.TextComponent{
+			Text:     "",
+			Color:    "red",
+			Children: []text.TextComponent{text.Translatable("command.unknown.command")},
+		})
+		return
+	}
+	cmd(sv, c, fields[1:])
+}
+
+// sendMessage sends a system message, translated into the client's locale.
+func (sv *Supervisor) sendMessage(c common.Client, comp text.TextComponent) {
+	sv.logger.Debug().Str("client uuid", c.GetUUID().String()).Str("message", logger.Text(comp)).Msg("Sent system message")
+	sv.send(c, clientbound.SystemChatMessagePacket{Content: sv.Localize(c, comp)})
+}
+
+// tpsCommand reports the ticks per second and milliseconds per tick.
+func tpsCommand(sv *Supervisor, c common.Client, _ []string) {
+	stats := sv.TickStats()
+	target := float64(stats.Target.Milliseconds())
+
+	sv.sendMessage(c, text.TextComponent{
+		Color: "gold",
+		Children: []text.TextComponent{
+			{Text: "TPS: "},
+			{Text: fmt.Sprintf("%.1f", stats.TPS), Color: loadColor(stats.MSPT, target)},
+			{Text: "  MSPT: "},
+			{Text: fmt.Sprintf("%.2f", stats.MSPT), Color: loadColor(stats.MSPT, target)},
+			{Text: " avg, "},
+			{Text: fmt.Sprintf("%.2f", stats.MaxMSPT), Color: loadColor(stats.MaxMSPT, target)},
+			{Text: " max"},
+		},
+	})
+}
+
+// loadColor colors a tick duration by how much of the tick it uses.
+func loadColor(mspt, target float64) string {
+	switch {
+	case mspt > target:
+		return "red"
+	case mspt > target*0.8:
+		return "yellow"
+	}
+	return "green"
+}
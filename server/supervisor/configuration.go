@@ -0,0 +1,65 @@
+package supervisor
+
+import (
+	"slices"
+
+	"github.com/hunterros-s/algernon/registry"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	clientconfig "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/configuration"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/configuration"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// startConfiguration begins configuring a client that finished logging in by
+// asking which of the server's data packs it has.
+//
+// https://wiki.vg/Protocol_FAQ#What.27s_the_normal_login_sequence_for_a_client.3F
+func (sv *Supervisor) startConfiguration(p *Player) {
+	p.configuring = true
+	core := clientconfig.KnownPack(registry.Core)
+	sv.send(p.client,
+		clientconfig.PluginMessagePacket{
+			Channel: clientconfig.BrandChannel,
+			Data:    io.NewWriter().WriteString(sv.brand).Bytes(),
+		},
+		clientconfig.FeatureFlagsPacket{FeatureFlags: []string{"minecraft:vanilla"}},
+		clientconfig.KnownPacksPacket{KnownPacks: []clientconfig.KnownPack{core}},
+	)
+}
+
+// sendRegistries sends the registries and tags once the client said which
+// packs it has, and finishes configuration. Entries of packs the client has
+// are sent without their data.
+func (sv *Supervisor) sendRegistries(p *Player, packet *configuration.KnownPacksPacket) {
+	knowsCore := slices.Contains(packet.KnownPacks, configuration.KnownPack(registry.Core))
+
+	for _, r := range registry.Synchronized() {
+		if !knowsCore && !r.Complete() {
+			sv.logger.Info().Str("name", p.Profile.Name).Str("registry", r.Name).Msg("Client doesn't have the vanilla data pack")
+			sv.kick(p.client, text.Translatable("multiplayer.disconnect.outdated_client", text.TextComponent{Text: registry.Core.Version}))
+			return
+		}
+	}
+
+	for _, r := range registry.Synchronized() {
+		entries := make([]clientconfig.RegistryEntry, len(r.Entries))
+		for i, e := range r.Entries {
+			entries[i].ID = e.Name
+			if !knowsCore {
+				entries[i].Data = e.Data
+			}
+		}
+		sv.send(p.client, clientconfig.RegistryDataPacket{RegistryID: r.Name, Entries: entries})
+	}
+
+	tags := make(map[string]map[string][]int32, len(sv.tags))
+	for _, t := range sv.tags {
+		tags[t.Registry] = t.Tags
+	}
+	sv.send(p.client,
+		clientconfig.UpdateTagsPacket{Tags: tags},
+		clientconfig.FinishConfigurationPacket{},
+	)
+	// the client reads what follows as play packets
+	p.configuring = false
+}
@@ -0,0 +1,98 @@
+package supervisor
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/server/common"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+)
+
+// maxVelocity is the fastest velocity clients are sent, in blocks per tick.
+const maxVelocity = 3.9
+
+// managedEntity is an entity of the entity manager, as the tracker shows it.
+type managedEntity struct {
+	manager *entity.Manager
+	id      entity.ID
+}
+
+var _ tracked = managedEntity{}
+
+func (e managedEntity) entityID() int32 {
+	return int32(e.id)
+}
+
+func (e managedEntity) position() (x, y, z float64) {
+	pos, _ := e.manager.Position(e.id)
+	return pos.X, pos.Y, pos.Z
+}
+
+func (e managedEntity) rotation() (yaw, pitch, headYaw float32) {
+	pos, _ := e.manager.Position(e.id)
+	return pos.Yaw, pos.Pitch, pos.HeadYaw
+}
+
+func (e managedEntity) onGround() bool {
+	pos, _ := e.manager.Position(e.id)
+	return pos.OnGround
+}
+
+func (e managedEntity) velocity() (x, y, z float64, ok bool) {
+	identity, _ := e.manager.Identity(e.id)
+	v, _ := e.manager.Velocity(e.id)
+	return v.X, v.Y, v.Z, entity.TypeOf(identity.Type).TrackVelocity
+}
+
+func (e managedEntity) metadata() *entity.Metadata {
+	m, _ := e.manager.Metadata(e.id)
+	return m
+}
+
+func (e managedEntity) trackingRange() float64 {
+	identity, _ := e.manager.Identity(e.id)
+	return float64(entity.TypeOf(identity.Type).TrackingRange * 16)
+}
+
+func (e managedEntity) spawnPackets() []common.ClientboundPacket {
+	identity, _ := e.manager.Identity(e.id)
+	pos, _ := e.manager.Position(e.id)
+	velocity, _ := e.manager.Velocity(e.id)
+
+	packets := []common.ClientboundPacket{clientbound.SpawnEntityPacket{
+		EntityID:  int32(e.id),
+		UUID:      identity.UUID,
+		Type:      identity.TypeID,
+		X:         pos.X,
+		Y:         pos.Y,
+		Z:         pos.Z,
+		Pitch:     pos.Pitch,
+		Yaw:       pos.Yaw,
+		HeadYaw:   pos.HeadYaw,
+		Data:      identity.Data,
+		VelocityX: encodeVelocity(velocity.X),
+		VelocityY: encodeVelocity(velocity.Y),
+		VelocityZ: encodeVelocity(velocity.Z),
+	}}
+	if metadata := e.metadata().NonDefault(); len(metadata) > 0 {
+		packets = append(packets, clientbound.SetEntityMetadataPacket{EntityID: int32(e.id), Metadata: metadata})
+	}
+	return packets
+}
+
+// encodeVelocity converts a velocity to the 1/8000 blocks per tick it is sent
+// in.
+func encodeVelocity(v float64) int16 {
+	return int16(math.Max(-maxVelocity, math.Min(v, maxVelocity)) * 8000)
+}
+
+// trackEntities shows the entities of the manager to players as they spawn
+// and removes them as they despawn.
+func (sv *Supervisor) trackEntities() {
+	sv.entities.OnSpawn(func(id entity.ID) {
+		sv.tracker.add(managedEntity{sv.entities, id})
+	})
+	sv.entities.OnDespawn(func(id entity.ID) {
+		sv.tracker.remove(int32(id))
+	})
+}
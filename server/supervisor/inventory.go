@@ -0,0 +1,476 @@
+package supervisor
+
+import (
+	"maps"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/inventory"
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/recipe"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/play"
+	"github.com/hunterros-s/algernon/text"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// maxWindowID is the highest ID of container windows, after which they start
+// over from 1 as in vanilla.
+const maxWindowID = 100
+
+// containerReach is how much further than their reach players may be from
+// the container they have open before it closes.
+const containerReach = 4
+
+// Speeds of thrown items, in blocks per tick
+const (
+	throwSpeed = 0.3
+	throwLift  = 0.1
+	throwNoise = 0.02
+	// dropHeight is how far below the eyes of players the items they throw
+	// appear.
+	dropHeight = 0.3
+)
+
+// blockPos is the position of a block.
+type blockPos struct {
+	x, y, z int
+}
+
+// containerTypes are the windows of the blocks keeping a container, by block
+// name. Shulker boxes of every color are found by suffix.
+var containerTypes = map[string]*inventory.Type{
+	"minecraft:chest":         inventory.Generic9x3,
+	"minecraft:trapped_chest": inventory.Generic9x3,
+	"minecraft:barrel":        inventory.Generic9x3,
+	"minecraft:dispenser":     inventory.Generic3x3,
+	"minecraft:dropper":       inventory.Generic3x3,
+	"minecraft:hopper":        inventory.Hopper,
+}
+
+func containerType(name string) (*inventory.Type, bool) {
+	if strings.HasSuffix(name, "shulker_box") {
+		return inventory.ShulkerBox, true
+	}
+	t, ok := containerTypes[name]
+	return t, ok
+}
+
+// openWindow opens a container window for a player over its inventory,
+// closing the one it had open. The contents are sent with the next sync.
+func (sv *Supervisor) openWindow(p *Player, t *inventory.Type, title text.TextComponent, at *blockPos, containers ...*inventory.Inventory) {
+	sv.closeWindow(p, false)
+	sv.windowID = sv.windowID%maxWindowID + 1
+	p.window = inventory.NewWindow(sv.windowID, t, title, p.Inventory, containers...)
+	p.windowAt = at
+	sv.send(p.client, clientbound.OpenScreenPacket{WindowID: p.window.ID, WindowType: t.ID, Title: sv.Localize(p.client, title)})
+}
+
+// closeWindow closes the container window a player has open, giving it back
+// what it carried, and tells the client to when it didn't close it itself.
+func (sv *Supervisor) closeWindow(p *Player, notify bool) {
+	if p.window == nil {
+		return
+	}
+	if notify {
+		sv.send(p.client, clientbound.CloseContainerPacket{WindowID: uint8(p.window.ID)})
+	}
+	sv.give(p, p.window.Close()...)
+	closed := p.window
+	p.window = nil
+	p.windowAt = nil
+	sv.forgetContainers(closed)
+	// the slots of the inventory the client has are those of the closed window
+	p.playerWindow.Resync()
+}
+
+// currentWindow returns the window a player has open, its inventory when no
+// container is.
+func (p *Player) currentWindow() *inventory.Window {
+	if p.window != nil {
+		return p.window
+	}
+	return p.playerWindow
+}
+
+// clickContainer handles Click Container. Clicks in windows that aren't open
+// are ignored, and spectators only get their window back.
+func (sv *Supervisor) clickContainer(p *Player, c *play.ClickContainerPacket) {
+	w := p.currentWindow()
+	if int32(c.WindowID) != w.ID {
+		return
+	}
+	if p.GameMode == Spectator || c.Unreadable {
+		w.Resync()
+		return
+	}
+
+	changed := make(map[int]*item.ItemStack, len(c.ChangedSlots))
+	for slot, s := range c.ChangedSlots {
+		changed[int(slot)] = s
+	}
+	thrown := w.Click(inventory.Click{
+		StateID: c.StateID,
+		Slot:    int(c.Slot),
+		Button:  c.Button,
+		Mode:    c.Mode,
+		Changed: changed,
+		Carried: c.Carried,
+	}, p.GameMode == Creative)
+	if w == p.window {
+		sv.storeContainers(w)
+	}
+	for _, s := range thrown {
+		sv.throw(p, s)
+	}
+}
+
+// closeContainer handles Close Container.
+func (sv *Supervisor) closeContainer(p *Player, id uint8) {
+	if id == inventory.PlayerWindowID {
+		sv.give(p, p.playerWindow.Close()...)
+		return
+	}
+	if p.window != nil && int32(id) == p.window.ID {
+		sv.closeWindow(p, false)
+	}
+}
+
+// placeRecipe handles Place Recipe, filling the crafting grid of the open
+// window from the recipe book, or showing the client the ingredients as ghost
+// items when the player lacks them.
+func (sv *Supervisor) placeRecipe(p *Player, r *play.PlaceRecipePacket) {
+	w := p.currentWindow()
+	if int32(r.WindowID) != w.ID || p.GameMode == Spectator {
+		return
+	}
+	rec, ok := recipe.Default().Recipe(r.Recipe)
+	if !ok {
+		return
+	}
+	crafting, ok := rec.(recipe.CraftingRecipe)
+	if !ok {
+		return
+	}
+	if !w.PlaceRecipe(crafting, r.MakeAll) {
+		sv.send(p.client, clientbound.PlaceGhostRecipePacket{WindowID: r.WindowID, Recipe: rec.ID()})
+	}
+}
+
+// setHeldItem handles Set Held Item, ignoring slots outside the hotbar.
+func (sv *Supervisor) setHeldItem(p *Player, slot int16) {
+	if slot < 0 || slot >= inventory.HotbarSize {
+		sv.logger.Warn().Str("name", p.Profile.Name).Int16("slot", slot).Msg("Invalid held slot")
+		return
+	}
+	p.Inventory.Selected = int(slot)
+}
+
+// setCreativeSlot handles Set Creative Mode Slot, which sets a slot of the
+// inventory window of players in creative mode to anything, or throws it.
+func (sv *Supervisor) setCreativeSlot(p *Player, c *play.SetCreativeModeSlotPacket) {
+	if c.Unreadable {
+		// the client shows a stack the server doesn't know
+		p.playerWindow.Resync()
+		return
+	}
+	slot, s := c.Slot, c.Stack
+	if p.GameMode != Creative || !s.Empty() && s.Count > s.MaxStackSize() {
+		return
+	}
+	switch {
+	case slot == -1:
+		if !s.Empty() {
+			sv.throw(p, s)
+		}
+	case slot > inventory.PlayerResultSlot && int(slot) < p.playerWindow.Size():
+		p.playerWindow.SetSlot(int(slot), s)
+	}
+}
+
+// syncWindow sends a player the slots of its open window that changed, or all
+// of them when the client lost track.
+func (sv *Supervisor) syncWindow(p *Player) {
+	if p.windowAt != nil && !sv.canUseContainer(p, *p.windowAt) {
+		sv.closeWindow(p, true)
+	}
+
+	w := p.currentWindow()
+	if w.Desynced() {
+		stateID, slots, carried := w.Content()
+		sv.send(p.client, clientbound.SetContainerContentPacket{WindowID: uint8(w.ID), StateID: stateID, Slots: slots, Carried: carried})
+		return
+	}
+	for _, u := range w.Changes() {
+		packet := clientbound.SetContainerSlotPacket{WindowID: int8(w.ID), StateID: u.StateID, Slot: int16(u.Slot), Stack: u.Stack}
+		if u.Slot < 0 {
+			packet.WindowID = -1
+		}
+		sv.send(p.client, packet)
+	}
+}
+
+// throwHeld throws one of the held stack, or all of it.
+func (sv *Supervisor) throwHeld(p *Player, all bool) {
+	if p.GameMode == Spectator {
+		return
+	}
+	held := p.Inventory.Held()
+	if held.Empty() {
+		return
+	}
+	n := int32(1)
+	if all {
+		n = held.Count
+	}
+	p.Inventory.SetSlot(p.Inventory.Selected, held.WithCount(held.Count-n))
+	sv.throw(p, held.WithCount(n))
+}
+
+// swapHands swaps the held stack with the one in the offhand.
+func (sv *Supervisor) swapHands(p *Player) {
+	if p.GameMode == Spectator {
+		return
+	}
+	held, offhand := p.Inventory.Held(), p.Inventory.Slot(inventory.Offhand)
+	p.Inventory.SetSlot(p.Inventory.Selected, offhand)
+	p.Inventory.SetSlot(inventory.Offhand, held)
+}
+
+// give adds stacks to the inventory of a player, throwing what doesn't fit.
+func (sv *Supervisor) give(p *Player, stacks ...*item.ItemStack) {
+	for _, s := range stacks {
+		if rest := p.Inventory.Add(s); !rest.Empty() {
+			sv.throw(p, rest)
+		}
+	}
+}
+
+// throw throws a stack from the eyes of a player the way it looks.
+func (sv *Supervisor) throw(p *Player, s *item.ItemStack) {
+	yaw, pitch := float64(p.Yaw)*math.Pi/180, float64(p.Pitch)*math.Pi/180
+	angle := rand.Float64() * 2 * math.Pi
+	spread := rand.Float64() * throwNoise
+	velocity := entity.Velocity{
+		X: -math.Sin(yaw)*math.Cos(pitch)*throwSpeed + math.Cos(angle)*spread,
+		Y: -math.Sin(pitch)*throwSpeed + throwLift + (rand.Float64()-rand.Float64())*0.1,
+		Z: math.Cos(yaw)*math.Cos(pitch)*throwSpeed + math.Sin(angle)*spread,
+	}
+	sv.dropItem(p.X, p.Y+p.eyeHeight()-dropHeight, p.Z, s, velocity)
+}
+
+// dropItem spawns a stack as an item entity.
+func (sv *Supervisor) dropItem(x, y, z float64, s *item.ItemStack, velocity entity.Velocity) {
+	id, err := sv.entities.Spawn("minecraft:item", entity.Position{X: x, Y: y, Z: z})
+	if err != nil {
+		sv.logger.Error().Err(err).Msg("Unable to drop item")
+		return
+	}
+	m, _ := sv.entities.Metadata(id)
+	m.Set(entity.Item, s)
+	v, _ := sv.entities.Velocity(id)
+	*v = velocity
+}
+
+// useBlock opens the window of a block a player clicked, reporting whether it
+// has one.
+func (sv *Supervisor) useBlock(p *Player, x, y, z int) bool {
+	registry := block.Default()
+	state, ok := registry.State(sv.world.Block(x, y, z))
+	if !ok || p.GameMode == Spectator {
+		return false
+	}
+	name := state.Block.Name
+	at := &blockPos{x, y, z}
+	if name == "minecraft:crafting_table" {
+		sv.openWindow(p, inventory.Crafting, inventory.Crafting.Title, at)
+		return true
+	}
+	t, ok := containerType(name)
+	if !ok {
+		return false
+	}
+	if name != "minecraft:chest" && name != "minecraft:trapped_chest" {
+		sv.openWindow(p, t, t.Title, at, sv.container(*at, t.Size))
+		return true
+	}
+
+	// chests don't open under solid blocks, and open with their other half
+	if registry.IsFullCube(sv.world.Block(x, y+1, z)) {
+		return true
+	}
+	facing, _ := block.DirectionNamed(state.Get("facing"))
+	var partner block.Direction
+	switch state.Get("type") {
+	case "left":
+		partner = facing.Clockwise()
+	case "right":
+		partner = facing.CounterClockwise()
+	default:
+		sv.openWindow(p, t, t.Title, at, sv.container(*at, t.Size))
+		return true
+	}
+	dx, _, dz := partner.Offset()
+	other := blockPos{x + dx, y, z + dz}
+	if registry.IsFullCube(sv.world.Block(other.x, other.y+1, other.z)) {
+		return true
+	}
+	// the right half is shown on top
+	first, second := sv.container(*at, t.Size), sv.container(other, t.Size)
+	if state.Get("type") == "left" {
+		first, second = second, first
+	}
+	double := inventory.Generic9x6
+	sv.openWindow(p, double, double.Title, at, first, second)
+	return true
+}
+
+// container returns the container of the block at a position, loading it from
+// the block entity there the first time. Containers are kept while windows show
+// them.
+func (sv *Supervisor) container(pos blockPos, size int) *inventory.Inventory {
+	if inv, ok := sv.containers[pos]; ok && inv.Size() == size {
+		return inv
+	}
+	data, _ := sv.world.BlockEntity(pos.x, pos.y, pos.z)
+	inv := sv.containerFromNBT(data, size)
+	sv.containers[pos] = inv
+	return inv
+}
+
+// containerFromNBT reads the stacks of a container block entity, as vanilla
+// stores them.
+func (sv *Supervisor) containerFromNBT(data nbt.Compound, size int) *inventory.Inventory {
+	inv := inventory.New(size)
+	items, _ := data.Compounds("Items")
+	for _, tag := range items {
+		slot, ok := tag.Byte("Slot")
+		if !ok || slot < 0 || int(slot) >= size {
+			continue
+		}
+		s, err := item.FromNBT(tag)
+		if err != nil {
+			sv.logger.Warn().Err(err).Msg("Unable to load container item")
+			continue
+		}
+		inv.SetSlot(int(slot), s)
+	}
+	return inv
+}
+
+// storeContainers stores the containers a window shows in the block entities
+// of their blocks, to be saved with their chunks.
+func (sv *Supervisor) storeContainers(w *inventory.Window) {
+	for pos, inv := range sv.containers {
+		if w.Shows(inv) {
+			sv.storeContainer(pos, inv)
+		}
+	}
+}
+
+// storeContainer stores a container in the block entity of its block, keeping
+// what else vanilla stores there.
+func (sv *Supervisor) storeContainer(pos blockPos, inv *inventory.Inventory) {
+	state, ok := block.Default().State(sv.world.Block(pos.x, pos.y, pos.z))
+	if !ok {
+		return
+	}
+	if _, ok := containerType(state.Block.Name); !ok {
+		return
+	}
+	items := nbt.List{}
+	for i := range inv.Size() {
+		s := inv.Slot(i)
+		if s.Empty() {
+			continue
+		}
+		tag, err := s.ToNBT()
+		if err != nil {
+			sv.logger.Error().Err(err).Msg("Unable to store container item")
+			continue
+		}
+		tag["Slot"] = int8(i)
+		items = append(items, tag)
+	}
+
+	old, _ := sv.world.BlockEntity(pos.x, pos.y, pos.z)
+	data := maps.Clone(old)
+	if data == nil {
+		data = nbt.Compound{}
+	}
+	data["id"] = containerEntity(state.Block.Name)
+	data["Items"] = items
+	sv.world.SetBlockEntity(pos.x, pos.y, pos.z, data)
+}
+
+// containerEntity returns the type of the block entity of a container block.
+func containerEntity(name string) string {
+	if strings.HasSuffix(name, "shulker_box") {
+		return "minecraft:shulker_box"
+	}
+	return name
+}
+
+// forgetContainers stores and forgets the containers a closed window showed
+// that no other window shows.
+func (sv *Supervisor) forgetContainers(w *inventory.Window) {
+	for pos, inv := range sv.containers {
+		if !w.Shows(inv) {
+			continue
+		}
+		sv.storeContainer(pos, inv)
+		shown := false
+		for _, p := range sv.players.All() {
+			shown = shown || p.window != nil && p.window.Shows(inv)
+		}
+		if !shown {
+			delete(sv.containers, pos)
+		}
+	}
+}
+
+// canUseContainer reports whether a player is close enough to a block whose
+// window it has open, and the block is still there.
+func (sv *Supervisor) canUseContainer(p *Player, pos blockPos) bool {
+	if block.Default().IsAir(sv.world.Block(pos.x, pos.y, pos.z)) {
+		return false
+	}
+	ex, ey, ez := p.X, p.Y+p.eyeHeight(), p.Z
+	dx := distanceToRange(ex, float64(pos.x), float64(pos.x+1))
+	dy := distanceToRange(ey, float64(pos.y), float64(pos.y+1))
+	dz := distanceToRange(ez, float64(pos.z), float64(pos.z+1))
+	limit := blockReach + containerReach
+	if p.GameMode == Creative {
+		limit = creativeBlockReach + containerReach
+	}
+	return dx*dx+dy*dy+dz*dz <= limit*limit
+}
+
+// dropContainer drops the items of the container of a broken block, closing
+// the windows showing it. data is the block entity the block had.
+func (sv *Supervisor) dropContainer(x, y, z int, data nbt.Compound) {
+	pos := blockPos{x, y, z}
+	inv, ok := sv.containers[pos]
+	if ok {
+		delete(sv.containers, pos)
+		for _, p := range sv.players.All() {
+			if p.window != nil && p.window.Shows(inv) {
+				sv.closeWindow(p, true)
+			}
+		}
+	} else {
+		// slots are stored as bytes
+		inv = sv.containerFromNBT(data, math.MaxInt8+1)
+	}
+	for _, s := range inv.Clear() {
+		velocity := entity.Velocity{
+			X: rand.NormFloat64() * 0.05,
+			Y: 0.2,
+			Z: rand.NormFloat64() * 0.05,
+		}
+		sv.dropItem(float64(x)+0.5, float64(y)+0.5, float64(z)+0.5, s, velocity)
+	}
+}
@@ -0,0 +1,158 @@
+package supervisor
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/hunterros-s/algernon/recipe"
+	"github.com/hunterros-s/algernon/registry"
+	clientconfig "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/configuration"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// Abilities of players that aren't flying
+const (
+	flyingSpeed         = 0.05
+	fieldOfViewModifier = 0.1
+)
+
+// keepAliveInterval is how often clients are sent Keep Alive. A client that
+// hasn't answered the previous one by the next is timed out.
+const keepAliveInterval = 15 * time.Second
+
+// playerInfoActions are the Player Info Update actions adding a player to the
+// player list.
+const playerInfoActions = clientbound.PlayerInfoAddPlayer | clientbound.PlayerInfoUpdateGameMode |
+	clientbound.PlayerInfoUpdateListed | clientbound.PlayerInfoUpdateLatency
+
+// hashSeed hashes the world seed the way Login (play) carries it.
+func hashSeed(seed int64) int64 {
+	hash := sha256.Sum256(binary.BigEndian.AppendUint64(nil, uint64(seed)))
+	return int64(binary.BigEndian.Uint64(hash[:8]))
+}
+
+// join spawns a player that finished configuration into the world: the client
+// is told about the world, put at the spawn point and sent the chunks around
+// it.
+//
+// https://wiki.vg/Protocol_FAQ#What.27s_the_normal_login_sequence_for_a_client.3F
+func (sv *Supervisor) join(p *Player) {
+	p.EntityID = int32(sv.entities.AllocateID())
+	p.X, p.Y, p.Z = float64(sv.spawn.x)+0.5, float64(sv.spawn.y), float64(sv.spawn.z)+0.5
+	p.playing = true
+
+	dimensionType, _ := registry.DimensionTypes().ID("minecraft:overworld")
+	recipes := recipe.Default().Recipes()
+	ids := make([]string, len(recipes))
+	for i, r := range recipes {
+		ids[i] = r.ID()
+	}
+	sv.send(p.client,
+		clientbound.LoginPacket{
+			EntityID:            p.EntityID,
+			DimensionNames:      []string{"minecraft:overworld"},
+			MaxPlayers:          int32(sv.maxPlayers),
+			ViewDistance:        int32(sv.viewDistance),
+			SimulationDistance:  int32(sv.simulationDistance),
+			EnableRespawnScreen: true,
+			DimensionType:       dimensionType,
+			DimensionName:       "minecraft:overworld",
+			HashedSeed:          sv.hashedSeed,
+			GameMode:            uint8(p.GameMode),
+			PreviousGameMode:    -1,
+			IsFlat:              sv.flat,
+		},
+		clientbound.PlayerAbilitiesPacket{
+			Flags:               p.GameMode.abilities(),
+			FlyingSpeed:         flyingSpeed,
+			FieldOfViewModifier: fieldOfViewModifier,
+		},
+		clientbound.SetHeldItemPacket{Slot: int8(p.Inventory.Selected)},
+		clientbound.UpdateRecipesPacket{Recipes: recipes},
+		// every recipe is unlocked
+		clientbound.UpdateRecipeBookPacket{Action: clientbound.RecipeBookInit, Recipes: ids},
+		clientbound.SetDefaultSpawnPositionPacket{X: int32(sv.spawn.x), Y: int32(sv.spawn.y), Z: int32(sv.spawn.z)},
+	)
+	sv.teleport(p, p.X, p.Y, p.Z, p.Yaw, p.Pitch)
+	sv.send(p.client, clientbound.GameEventPacket{Event: clientbound.GameEventStartWaitingForChunks})
+	p.chunks.move(p.X, p.Z)
+
+	// players have to be in the player list before their entities spawn
+	others := []clientbound.PlayerInfo{}
+	for _, other := range sv.players.All() {
+		if other.playing && other != p {
+			others = append(others, other.info())
+		}
+	}
+	if len(others) > 0 {
+		sv.send(p.client, clientbound.PlayerInfoUpdatePacket{Actions: playerInfoActions, Players: others})
+	}
+	sv.broadcast(clientbound.PlayerInfoUpdatePacket{Actions: playerInfoActions, Players: []clientbound.PlayerInfo{p.info()}})
+	sv.tracker.add(p)
+
+	sv.logger.Info().Str("name", p.Profile.Name).Int32("entity id", p.EntityID).Msg("Player joined")
+}
+
+// teleport moves a player. Until the client confirms the teleport, the moves it
+// sends are from before it.
+func (sv *Supervisor) teleport(p *Player, x, y, z float64, yaw, pitch float32) {
+	p.X, p.Y, p.Z = x, y, z
+	p.Yaw, p.Pitch = yaw, pitch
+	p.teleportID++
+	p.awaitingTeleport = true
+
+	sv.send(p.client, clientbound.SynchronizePlayerPositionPacket{
+		X: x, Y: y, Z: z,
+		Yaw: yaw, Pitch: pitch,
+		TeleportID: p.teleportID,
+	})
+}
+
+// confirmTeleport handles Confirm Teleportation.
+func (sv *Supervisor) confirmTeleport(p *Player, id int32) {
+	if p.awaitingTeleport && id == p.teleportID {
+		p.awaitingTeleport = false
+	}
+}
+
+// keepAlive times out the players that didn't answer the last Keep Alive and
+// sends the others a new one, both in play and while they are configured. The
+// latencies measured with the last one are sent to everyone.
+func (sv *Supervisor) keepAlive() {
+	now := time.Now().UnixMilli()
+	var latencies []clientbound.PlayerInfo
+	for _, p := range sv.players.All() {
+		if !p.playing && !p.configuring {
+			continue
+		}
+		if p.playing {
+			latencies = append(latencies, p.info())
+		}
+		if p.awaitingKeepAlive {
+			sv.logger.Info().Str("name", p.Profile.Name).Msg("Player timed out")
+			sv.kick(p.client, text.Translatable("disconnect.timeout"))
+			continue
+		}
+		p.keepAliveID = now
+		p.awaitingKeepAlive = true
+		if p.playing {
+			sv.send(p.client, clientbound.KeepAlivePacket{KeepAliveID: now})
+		} else {
+			sv.send(p.client, clientconfig.KeepAlivePacket{KeepAliveID: now})
+		}
+	}
+	if len(latencies) > 0 {
+		sv.broadcast(clientbound.PlayerInfoUpdatePacket{Actions: clientbound.PlayerInfoUpdateLatency, Players: latencies})
+	}
+}
+
+// answerKeepAlive handles a client answering Keep Alive.
+func (sv *Supervisor) answerKeepAlive(p *Player, id int64) {
+	if !p.awaitingKeepAlive || id != p.keepAliveID {
+		return
+	}
+	p.awaitingKeepAlive = false
+	p.Latency = time.Since(time.UnixMilli(id))
+}
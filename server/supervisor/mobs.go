@@ -0,0 +1,89 @@
+package supervisor
+
+import (
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/entity/ai"
+	"github.com/hunterros-s/algernon/registry"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/world"
+)
+
+// Hunger isn't simulated, players are always full.
+const (
+	maxFood         = 20
+	spawnSaturation = 5
+)
+
+// minHealth is the least health mobs leave players with, as players can't die
+// yet.
+const minHealth = 1
+
+// mobWorld is the world as the AI of mobs sees it, with the players as their
+// targets.
+type mobWorld struct {
+	*world.World
+	sv *Supervisor
+}
+
+var _ ai.World = mobWorld{}
+
+func (w mobWorld) Targets() []ai.Target {
+	var targets []ai.Target
+	for _, p := range w.sv.players.All() {
+		if !p.playing || p.GameMode == Spectator {
+			continue
+		}
+		targets = append(targets, ai.Target{
+			EntityID:   p.EntityID,
+			X:          p.X,
+			Y:          p.Y,
+			Z:          p.Z,
+			EyeHeight:  p.eyeHeight(),
+			Attackable: p.GameMode == Survival || p.GameMode == Adventure,
+		})
+	}
+	return targets
+}
+
+func (w mobWorld) Attack(attacker entity.ID, target ai.Target, damage float64) {
+	w.sv.tracker.sendToViewers(int32(attacker), clientbound.EntityAnimationPacket{
+		EntityID:  int32(attacker),
+		Animation: clientbound.AnimationSwingMainArm,
+	})
+	for _, p := range w.sv.players.All() {
+		if p.EntityID == target.EntityID {
+			w.sv.hurt(p, attacker, damage)
+		}
+	}
+}
+
+// giveMobsAI gives the mobs spawning the AI of their type.
+func (sv *Supervisor) giveMobsAI() {
+	w := mobWorld{sv.world, sv}
+	sv.entities.OnSpawn(func(id entity.ID) {
+		identity, _ := sv.entities.Identity(id)
+		if brain := ai.BrainFor(identity.Type, w, sv.paths); brain != nil {
+			sv.entities.SetAI(id, brain)
+		}
+	})
+}
+
+// hurt damages a player hit by an entity, showing it to the players around.
+func (sv *Supervisor) hurt(p *Player, attacker entity.ID, damage float64) {
+	if !p.playing || p.GameMode == Creative || p.GameMode == Spectator {
+		return
+	}
+	p.Health = max(p.Health-float32(damage), minHealth)
+	p.Metadata.Set(entity.Health, p.Health)
+
+	damageTypes, _ := registry.Lookup("minecraft:damage_type")
+	source, _ := damageTypes.ID("minecraft:mob_attack")
+	event := clientbound.DamageEventPacket{
+		EntityID:       p.EntityID,
+		SourceType:     source,
+		SourceCauseID:  int32(attacker) + 1,
+		SourceDirectID: int32(attacker) + 1,
+	}
+	sv.send(p.client, event, clientbound.SetHealthPacket{Health: p.Health, Food: maxFood, FoodSaturation: spawnSaturation})
+	sv.tracker.sendToViewers(p.EntityID, event)
+}
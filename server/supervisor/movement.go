@@ -0,0 +1,218 @@
+package supervisor
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/play"
+	"github.com/hunterros-s/algernon/text"
+	"github.com/hunterros-s/algernon/world"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// Player dimensions while standing
+const (
+	playerWidth  = 0.6
+	playerHeight = 1.8
+)
+
+// poseHeights are the heights of the poses players take to fit where they
+// are, tallest first: standing, sneaking, and swimming or crawling.
+var poseHeights = []float64{playerHeight, 1.5, 0.6}
+
+// Movement limits, like vanilla's.
+const (
+	// maxCoordinate is how far from the origin on each axis players may go.
+	maxCoordinate = 3.0e7
+	// maxMoveSquared is the squared distance players may move per packet.
+	maxMoveSquared = 100
+	// maxFloatingTicks is how long players that can't fly may stay in the air
+	// without falling.
+	maxFloatingTicks = 80
+	// collisionEpsilon shrinks boxes for collision checks so touching a block
+	// isn't moving into it.
+	collisionEpsilon = 1e-5
+	// stepHeight is how high the blocks players walk up without jumping are.
+	stepHeight = 0.6
+	// maxMoveErrorSquared is the squared distance between where a move ends
+	// and where the client says it did that is put down to rounding.
+	maxMoveErrorSquared = 0.0625
+	// maxStepError is how far off heights may be, as clients and servers step
+	// and fall differently.
+	maxStepError = 0.5
+)
+
+// boundingBox returns the box of a player at a position, as tall as its pose.
+func boundingBox(x, y, z, height float64) block.Box {
+	half := playerWidth / 2
+	return block.Box{
+		MinX: x - half, MinY: y, MinZ: z - half,
+		MaxX: x + half, MaxY: y + height, MaxZ: z + half,
+	}
+}
+
+func deflate(b block.Box, d float64) block.Box {
+	return block.Box{
+		MinX: b.MinX + d, MinY: b.MinY + d, MinZ: b.MinZ + d,
+		MaxX: b.MaxX - d, MaxY: b.MaxY - d, MaxZ: b.MaxZ - d,
+	}
+}
+
+// validCoordinates reports whether a position is one players can be at.
+func validCoordinates(x, y, z float64) bool {
+	for _, v := range []float64{x, y, z} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+	}
+	return math.Abs(x) <= maxCoordinate && math.Abs(z) <= maxCoordinate && math.Abs(y) <= maxCoordinate
+}
+
+// canFly reports whether a player's game mode lets it fly.
+func (p *Player) canFly() bool {
+	return p.GameMode == Creative || p.GameMode == Spectator
+}
+
+// move applies a position the client sent, after checking it is a move the
+// player could have made. Moves that fail are undone by teleporting the
+// client back.
+func (sv *Supervisor) move(p *Player, x, y, z float64, yaw, pitch float32, onGround bool) {
+	if !validCoordinates(x, y, z) || math.IsNaN(float64(yaw)) || math.IsNaN(float64(pitch)) {
+		sv.logger.Warn().Str("name", p.Profile.Name).Msg("Player sent invalid coordinates")
+		sv.kick(p.client, text.Translatable("multiplayer.disconnect.invalid_player_movement"))
+		return
+	}
+	// moves sent before the client confirmed a teleport are from where it was
+	if p.awaitingTeleport {
+		return
+	}
+
+	p.movePackets++
+	dx, dy, dz := x-p.X, y-p.Y, z-p.Z
+	distance := dx*dx + dy*dy + dz*dz
+	if !p.canFly() && distance > maxMoveSquared*float64(p.movePackets) {
+		sv.logger.Warn().Str("name", p.Profile.Name).Float64("distance", math.Sqrt(distance)).Msg("Player moved too quickly")
+		sv.resync(p)
+		return
+	}
+	if p.GameMode != Spectator && sv.movesIntoBlock(p, x, y, z) {
+		sv.logger.Warn().Str("name", p.Profile.Name).Msg("Player moved wrongly")
+		sv.resync(p)
+		return
+	}
+
+	p.X, p.Y, p.Z = x, y, z
+	p.Yaw, p.Pitch = yaw, float32(math.Max(-90, math.Min(90, float64(pitch))))
+	// the client's word is only taken when there is something to stand on
+	p.OnGround = onGround && sv.supported(p)
+	p.chunks.move(x, z)
+}
+
+// rotate applies a rotation the client sent.
+func (sv *Supervisor) rotate(p *Player, yaw, pitch float32, onGround bool) {
+	sv.move(p, p.X, p.Y, p.Z, yaw, pitch, onGround)
+}
+
+// movesIntoBlock reports whether a move goes through or into blocks. Like
+// vanilla the move is run through the blocks, stepping up ones as high as
+// slabs, and where it gets to must be where the client says it ended up. It
+// may end in any pose the player fits in there. Players stuck in blocks may
+// move out of them.
+func (sv *Supervisor) movesIntoBlock(p *Player, x, y, z float64) bool {
+	smallest := poseHeights[len(poseHeights)-1]
+	if sv.world.Collides(deflate(boundingBox(p.X, p.Y, p.Z, smallest), collisionEpsilon)) {
+		return false
+	}
+	for _, height := range poseHeights {
+		if sv.world.Collides(deflate(boundingBox(p.X, p.Y, p.Z, height), collisionEpsilon)) {
+			continue
+		}
+		if sv.world.Collides(deflate(boundingBox(x, y, z, height), collisionEpsilon)) {
+			continue
+		}
+		dx, dy, dz := sv.sweep(boundingBox(p.X, p.Y, p.Z, height), x-p.X, y-p.Y, z-p.Z)
+		ex, ey, ez := x-(p.X+dx), y-(p.Y+dy), z-(p.Z+dz)
+		// heights are off by up to half a block when stepping
+		if math.Abs(ey) < maxStepError {
+			ey = 0
+		}
+		if ex*ex+ey*ey+ez*ez <= maxMoveErrorSquared {
+			return false
+		}
+	}
+	return true
+}
+
+// sweep returns how far a box gets moving through the blocks, stepping up
+// blocks as high as stepHeight when walking into them.
+func (sv *Supervisor) sweep(box block.Box, dx, dy, dz float64) (float64, float64, float64) {
+	obstacles := sv.world.CollisionBoxes(box.Expand(dx, dy, dz))
+	mx, my, mz := block.Sweep(box, obstacles, dx, dy, dz)
+	if mx == dx && mz == dz {
+		return mx, my, mz
+	}
+
+	obstacles = sv.world.CollisionBoxes(box.Expand(dx, stepHeight, dz).Expand(0, dy, 0))
+	_, up, _ := block.Sweep(box, obstacles, 0, stepHeight, 0)
+	lifted := box.Offset(0, up, 0)
+	sx, _, sz := block.Sweep(lifted, obstacles, dx, 0, dz)
+	_, down, _ := block.Sweep(lifted.Offset(sx, 0, sz), obstacles, 0, dy-up, 0)
+	if sx*sx+sz*sz > mx*mx+mz*mz {
+		return sx, up + down, sz
+	}
+	return mx, my, mz
+}
+
+// supported reports whether a player stands on a block or is held up by one,
+// like water or a ladder.
+func (sv *Supervisor) supported(p *Player) bool {
+	box := boundingBox(p.X, p.Y, p.Z, playerHeight)
+	below := box
+	below.MinY -= 1.0 / 16
+	below.MaxY = box.MinY + collisionEpsilon
+	return sv.world.Collides(below) || sv.world.KeepsAfloat(box)
+}
+
+// resync teleports a client back to where the server has its player.
+func (sv *Supervisor) resync(p *Player) {
+	sv.teleport(p, p.X, p.Y, p.Z, p.Yaw, p.Pitch)
+}
+
+// tickMovement runs once a tick for each player in the world. Players that
+// can't fly are kicked for staying in the air too long without falling.
+func (sv *Supervisor) tickMovement(p *Player) {
+	p.movePackets = 0
+
+	falling := p.Y < p.lastTickY
+	p.lastTickY = p.Y
+	// nothing is known about the ground in chunks that aren't loaded
+	_, loaded := sv.world.LoadedChunk(world.ChunkPosAt(int(math.Floor(p.X)), int(math.Floor(p.Z))))
+	if p.canFly() || p.awaitingTeleport || falling || !loaded || sv.supported(p) {
+		p.floatingTicks = 0
+		return
+	}
+
+	p.floatingTicks++
+	if p.floatingTicks > maxFloatingTicks {
+		sv.logger.Warn().Str("name", p.Profile.Name).Msg("Player was floating too long")
+		sv.kick(p.client, text.Translatable("multiplayer.disconnect.flying"))
+		p.floatingTicks = 0
+	}
+}
+
+// playerCommand handles Player Command, which tells when the player sneaks and
+// sprints.
+func (sv *Supervisor) playerCommand(p *Player, action int32) {
+	switch action {
+	case play.StartSneaking, play.StopSneaking:
+		sneaking := action == play.StartSneaking
+		p.Metadata.SetFlag(entity.Flags, entity.FlagSneaking, sneaking)
+		if sneaking {
+			p.Metadata.Set(entity.EntityPose, entity.PoseSneaking)
+		} else {
+			p.Metadata.Set(entity.EntityPose, entity.PoseStanding)
+		}
+	case play.StartSprinting, play.StopSprinting:
+		p.Metadata.SetFlag(entity.Flags, entity.FlagSprinting, action == play.StartSprinting)
+	}
+}
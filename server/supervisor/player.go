@@ -0,0 +1,326 @@
+package supervisor
+
+import (
+	"crypto/md5"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/inventory"
+	"github.com/hunterros-s/algernon/registry"
+	"github.com/hunterros-s/algernon/server/common"
+	clientlogin "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/login"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/configuration"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// GameMode is a player's game mode.
+//
+// https://wiki.vg/Protocol#Game_Event
+type GameMode uint8
+
+const (
+	Survival  GameMode = 0
+	Creative  GameMode = 1
+	Adventure GameMode = 2
+	Spectator GameMode = 3
+)
+
+// Eye heights of players, by whether they sneak
+const (
+	eyeHeight         = 1.62
+	sneakingEyeHeight = 1.27
+)
+
+// MaxHealth is the health players spawn with.
+const MaxHealth = 20
+
+// abilities returns the Player Abilities flags of a game mode.
+func (m GameMode) abilities() int8 {
+	switch m {
+	case Creative:
+		return clientbound.AbilityInvulnerable | clientbound.AbilityAllowFlying | clientbound.AbilityInstantBreak
+	case Spectator:
+		return clientbound.AbilityInvulnerable | clientbound.AbilityFlying | clientbound.AbilityAllowFlying
+	}
+	return 0
+}
+
+// Profile identifies the account a player logged in with.
+type Profile struct {
+	UUID       uuid.UUID
+	Name       string
+	Properties []ProfileProperty
+}
+
+// ProfileProperty is a game profile property, like the textures of a skin.
+// Signature is empty for unsigned properties.
+type ProfileProperty struct {
+	Name      string
+	Value     string
+	Signature string
+}
+
+// offlineUUID is the UUID vanilla servers in offline mode give a player name.
+func offlineUUID(name string) uuid.UUID {
+	id := uuid.UUID(md5.Sum([]byte("OfflinePlayer:" + name)))
+	id[6] = id[6]&0x0F | 0x30 // version 3
+	id[8] = id[8]&0x3F | 0x80 // RFC 4122 variant
+	return id
+}
+
+// ClientSettings are the options a client reports in Client Information.
+type ClientSettings struct {
+	Locale              string
+	ViewDistance        int
+	ChatMode            int32
+	ChatColors          bool
+	DisplayedSkinParts  uint8
+	MainHand            int32
+	EnableTextFiltering bool
+	AllowServerListings bool
+}
+
+func clientSettings(p configuration.ClientInformationPacket) ClientSettings {
+	return ClientSettings{
+		Locale:              p.Locale,
+		ViewDistance:        int(p.ViewDistance),
+		ChatMode:            p.ChatMode,
+		ChatColors:          p.ChatColors,
+		DisplayedSkinParts:  p.DisplayedSkinParts,
+		MainHand:            p.MainHand,
+		EnableTextFiltering: p.EnableTextFiltering,
+		AllowServerListings: p.AllowServerListings,
+	}
+}
+
+// Player is a logged in player. Profile and Client never change and may be
+// read from any goroutine, everything else belongs to the game loop.
+type Player struct {
+	Profile Profile
+	client  common.Client
+
+	// EntityID is assigned when the player joins the world.
+	EntityID   int32
+	GameMode   GameMode
+	X, Y, Z    float64
+	Yaw, Pitch float32
+	OnGround   bool
+	Health     float32
+	Inventory  *inventory.PlayerInventory
+	Settings   ClientSettings
+	Metadata   *entity.Metadata
+	// Latency is the round trip time of the last keep alive.
+	Latency time.Duration
+
+	// whether the client is being configured and may be sent configuration
+	// packets, until Finish Configuration is sent
+	configuring bool
+	// whether the player finished configuration and is in the world
+	playing bool
+
+	// movement packets since the last tick, how many ticks in a row the player
+	// was in the air without falling, and its height at the last tick
+	movePackets   int
+	floatingTicks int
+	lastTickY     float64
+
+	// the last teleport sent, and whether the client hasn't confirmed it yet
+	teleportID       int32
+	awaitingTeleport bool
+
+	// the last keep alive sent, and whether the client hasn't answered it yet
+	keepAliveID       int64
+	awaitingKeepAlive bool
+
+	// chunks the client has loaded
+	chunks *chunkTracker
+
+	// the block being dug, how much of it is broken and the destroy stage
+	// others were last shown
+	digging          bool
+	digX, digY, digZ int
+	digProgress      float64
+	digStage         uint8
+
+	// the window of the player's inventory, and the container window open
+	// over it with the block it belongs to, if any
+	playerWindow *inventory.Window
+	window       *inventory.Window
+	windowAt     *blockPos
+}
+
+func newPlayer(c common.Client, profile Profile, chunks *chunkTracker) *Player {
+	p := &Player{
+		Profile:   profile,
+		client:    c,
+		GameMode:  Survival,
+		Health:    MaxHealth,
+		Inventory: inventory.NewPlayer(),
+		Settings:  ClientSettings{Locale: text.DefaultLocale},
+		Metadata:  entity.NewMetadata(entity.PlayerSchema),
+		chunks:    chunks,
+	}
+	p.Metadata.Set(entity.Health, p.Health)
+	p.playerWindow = inventory.NewPlayerWindow(p.Inventory)
+	return p
+}
+
+// Client returns the connection of the player.
+func (p *Player) Client() common.Client {
+	return p.client
+}
+
+// eyeHeight returns how high above its feet the player sees from.
+func (p *Player) eyeHeight() float64 {
+	if p.Metadata.Flag(entity.Flags, entity.FlagSneaking) {
+		return sneakingEyeHeight
+	}
+	return eyeHeight
+}
+
+var _ tracked = (*Player)(nil)
+
+func (p *Player) entityID() int32 {
+	return p.EntityID
+}
+
+func (p *Player) position() (x, y, z float64) {
+	return p.X, p.Y, p.Z
+}
+
+// rotation returns the rotation of the player, whose head turns with it.
+func (p *Player) rotation() (yaw, pitch, headYaw float32) {
+	return p.Yaw, p.Pitch, p.Yaw
+}
+
+func (p *Player) onGround() bool {
+	return p.OnGround
+}
+
+// velocity isn't sent for players, whose clients move them.
+func (p *Player) velocity() (x, y, z float64, ok bool) {
+	return 0, 0, 0, false
+}
+
+func (p *Player) metadata() *entity.Metadata {
+	return p.Metadata
+}
+
+func (p *Player) trackingRange() float64 {
+	return float64(entity.TypeOf("minecraft:player").TrackingRange * 16)
+}
+
+func (p *Player) spawnPackets() []common.ClientboundPacket {
+	playerType, _ := registry.EntityTypes().ID("minecraft:player")
+	packets := []common.ClientboundPacket{clientbound.SpawnEntityPacket{
+		EntityID: p.EntityID,
+		UUID:     p.Profile.UUID,
+		Type:     playerType,
+		X:        p.X,
+		Y:        p.Y,
+		Z:        p.Z,
+		Pitch:    p.Pitch,
+		Yaw:      p.Yaw,
+		HeadYaw:  p.Yaw,
+	}}
+	if metadata := p.Metadata.NonDefault(); len(metadata) > 0 {
+		packets = append(packets, clientbound.SetEntityMetadataPacket{EntityID: p.EntityID, Metadata: metadata})
+	}
+	return packets
+}
+
+// info returns the entry of the player in the player list.
+func (p *Player) info() clientbound.PlayerInfo {
+	properties := make([]clientlogin.Property, len(p.Profile.Properties))
+	for i, prop := range p.Profile.Properties {
+		properties[i] = clientlogin.Property(prop)
+	}
+	return clientbound.PlayerInfo{
+		UUID:       p.Profile.UUID,
+		Name:       p.Profile.Name,
+		Properties: properties,
+		GameMode:   int32(p.GameMode),
+		Listed:     true,
+		Latency:    int32(p.Latency.Milliseconds()),
+	}
+}
+
+// PlayerList holds the logged in players, looked up by their connection or by
+// their profile. It is safe to use from any goroutine.
+type PlayerList struct {
+	mutex        sync.RWMutex
+	byConnection map[uuid.UUID]*Player
+	byProfile    map[uuid.UUID]*Player
+}
+
+func newPlayerList() *PlayerList {
+	return &PlayerList{
+		byConnection: make(map[uuid.UUID]*Player),
+		byProfile:    make(map[uuid.UUID]*Player),
+	}
+}
+
+// add adds a player, failing if one with the same profile is online.
+func (l *PlayerList) add(p *Player) bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, ok := l.byProfile[p.Profile.UUID]; ok {
+		return false
+	}
+	l.byConnection[p.client.GetUUID()] = p
+	l.byProfile[p.Profile.UUID] = p
+	return true
+}
+
+// remove removes the player of a connection and returns it.
+func (l *PlayerList) remove(connection uuid.UUID) (*Player, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	p, ok := l.byConnection[connection]
+	if !ok {
+		return nil, false
+	}
+	delete(l.byConnection, connection)
+	delete(l.byProfile, p.Profile.UUID)
+	return p, true
+}
+
+// ByConnection returns the player logged in on a connection.
+func (l *PlayerList) ByConnection(connection uuid.UUID) (*Player, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	p, ok := l.byConnection[connection]
+	return p, ok
+}
+
+// ByProfile returns the player logged in with a profile UUID.
+func (l *PlayerList) ByProfile(id uuid.UUID) (*Player, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	p, ok := l.byProfile[id]
+	return p, ok
+}
+
+// All returns the players online, in no particular order.
+func (l *PlayerList) All() []*Player {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	players := make([]*Player, 0, len(l.byConnection))
+	for _, p := range l.byConnection {
+		players = append(players, p)
+	}
+	return players
+}
+
+// Len returns how many players are online.
+func (l *PlayerList) Len() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return len(l.byConnection)
+}
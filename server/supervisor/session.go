@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/logger"
+	"github.com/hunterros-s/algernon/server/common"
+	clientconfig "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/configuration"
+	clientlogin "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/login"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/login"
+	"github.com/hunterros-s/algernon/text"
+)
+
+// login creates the player of a client that started logging in. The server
+// runs in offline mode, so profiles get vanilla's offline UUIDs.
+func (sv *Supervisor) login(c common.Client, packet *login.LoginStartPacket) {
+	if p, ok := sv.players.ByConnection(c.GetUUID()); ok {
+		sv.logger.Warn().Str("name", p.Profile.Name).Str("login", packet.Name).Msg("Player logged in again")
+		sv.kick(c, text.Translatable("multiplayer.disconnect.generic"))
+		return
+	}
+	profile := Profile{UUID: offlineUUID(packet.Name), Name: packet.Name}
+	chunks := newChunkTracker(sv.world, sv.viewDistance, func(packets ...common.ClientboundPacket) {
+		sv.send(c, packets...)
+	})
+	p := newPlayer(c, profile, chunks)
+
+	if sv.players.Len() >= sv.maxPlayers {
+		sv.logger.Info().Str("name", profile.Name).Msg("Refused login, the server is full")
+		sv.kick(c, text.Translatable("multiplayer.disconnect.server_full"))
+		return
+	}
+	if !sv.players.add(p) {
+		sv.logger.Info().Str("name", profile.Name).Msg("Refused login of player already online")
+		sv.kick(c, text.Translatable("multiplayer.disconnect.duplicate_login"))
+		return
+	}
+
+	sv.send(c, clientlogin.LoginSuccessPacket{
+		UUID:                profile.UUID,
+		Username:            profile.Name,
+		Properties:          p.info().Properties,
+		StrictErrorHandling: true,
+	})
+	sv.logger.Info().Str("name", profile.Name).Str("uuid", profile.UUID.String()).Msg("Player logged in")
+}
+
+// kick disconnects a client with a reason shown to it, using the Disconnect
+// packet of the state it is in. The connection is closed after the tick's
+// packets are sent.
+func (sv *Supervisor) kick(c common.Client, reason text.TextComponent) {
+	event := sv.logger.Info().Str("client uuid", c.GetUUID().String())
+	if p, ok := sv.players.ByConnection(c.GetUUID()); ok {
+		event = event.Str("name", p.Profile.Name)
+	}
+	event.Str("reason", logger.Text(reason)).Msg("Kicked client")
+
+	reason = sv.Localize(c, reason)
+	switch c.GetState() {
+	case common.Login:
+		sv.send(c, clientlogin.DisconnectPacket{Reason: reason})
+	case common.Configuration:
+		sv.send(c, clientconfig.DisconnectPacket{Reason: reason})
+	case common.Play:
+		sv.send(c, clientbound.DisconnectPacket{Reason: reason})
+	}
+	sv.closing = append(sv.closing, c)
+}
+
+// disconnect removes the player of a client that disconnected.
+func (sv *Supervisor) disconnect(c common.Client) {
+	delete(sv.outgoing, c.GetUUID())
+
+	p, ok := sv.players.remove(c.GetUUID())
+	if !ok {
+		return
+	}
+	p.chunks.close()
+	if p.playing {
+		sv.stopDigging(p)
+		sv.closeWindow(p, false)
+		sv.tracker.removeViewer(p)
+		sv.tracker.remove(p.EntityID)
+		sv.broadcast(clientbound.PlayerInfoRemovePacket{UUIDs: []uuid.UUID{p.Profile.UUID}})
+	}
+	sv.logger.Info().Str("name", p.Profile.Name).Msg("Player left")
+}
+
+// updateSettings applies the options from Client Information.
+func (sv *Supervisor) updateSettings(p *Player, settings ClientSettings) {
+	if settings.Locale == "" {
+		settings.Locale = text.DefaultLocale
+	}
+	p.Settings = settings
+	p.chunks.setViewDistance(settings.ViewDistance)
+	p.Metadata.Set(entity.DisplayedSkinParts, int8(settings.DisplayedSkinParts))
+	p.Metadata.Set(entity.MainHand, int8(settings.MainHand))
+}
@@ -1,54 +1,358 @@
 package supervisor
 
 import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hunterros-s/algernon/config"
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/entity/ai"
+	"github.com/hunterros-s/algernon/inventory"
+	"github.com/hunterros-s/algernon/item"
+	"github.com/hunterros-s/algernon/registry"
 	"github.com/hunterros-s/algernon/server/common"
+	"github.com/hunterros-s/algernon/server/protocol"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/configuration"
 	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/handshaking"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/login"
+	"github.com/hunterros-s/algernon/server/protocol/packet/packets/serverbound/play"
+	"github.com/hunterros-s/algernon/server/tick"
+	"github.com/hunterros-s/algernon/text"
+	"github.com/hunterros-s/algernon/world"
+	"github.com/hunterros-s/algernon/world/block"
 	"github.com/rs/zerolog"
 )
 
+// voidDepth is how far below the bottom of the world entities fall before
+// they despawn.
+const voidDepth = 64
+
+// incomingQueueSize is how many packets may wait for the next tick before
+// the connections receiving them block.
+const incomingQueueSize = 4096
+
 type Supervisor struct {
-	incoming chan common.IncomingEntry
-	logger   zerolog.Logger
+	// packets and, as entries without a packet, disconnects, in the order
+	// they happened
+	incoming     chan common.IncomingEntry
+	logger       zerolog.Logger
+	translations *text.Translations
+	brand        string
+
+	// bundled tags, sent during configuration
+	tags []registry.Tags
+
+	loop      *tick.Loop
+	scheduler *tick.Scheduler
+	// packets sent during a tick, flushed at its end
+	outgoing map[uuid.UUID]*outgoing
 
-	// players  map[uuid.UUID]*Player
-	// world    *World
+	world              *world.World
+	viewDistance       int
+	simulationDistance int
+	hashedSeed         int64
+	flat               bool
+	spawnProtection    int
+
+	players    *PlayerList
+	maxPlayers int
+	entities   *entity.Manager
+	paths      *ai.Pathfinder
+	tracker    *entityTracker
+
+	// inventories of container blocks by position, loaded from their block
+	// entities while windows show them, and the ID of the last container
+	// window opened
+	containers map[blockPos]*inventory.Inventory
+	windowID   int32
+	// clients kicked during the tick, closed once it is flushed
+	closing []common.Client
+	// spawn is the block players spawn at, found once as the server starts
+	spawn blockPos
 }
 
-func NewSupervisor(logger zerolog.Logger) *Supervisor {
-	return &Supervisor{
-		incoming: make(chan common.IncomingEntry),
-		logger:   logger,
+// NewSupervisor creates the supervisor of a world, finding its spawn point.
+func NewSupervisor(cfg *config.ServerConfig, w *world.World) (*Supervisor, error) {
+	x, y, z, err := w.Spawn()
+	if err != nil {
+		return nil, fmt.Errorf("error finding the spawn point: %w", err)
 	}
+
+	translations := text.NewTranslations()
+	if cfg.LanguageDir != "" {
+		if err := translations.LoadDir(cfg.LanguageDir); err != nil {
+			cfg.Logger.Error().Err(err).Msg("Unable to load languages")
+		}
+	}
+
+	tags, err := registry.AllTags(
+		registry.FromNames("minecraft:block", block.Default().Names()),
+		registry.FromNames("minecraft:item", item.Default().Names()),
+	)
+	if err != nil {
+		cfg.Logger.Error().Err(err).Msg("Unable to load tags")
+	}
+
+	sv := &Supervisor{
+		incoming:     make(chan common.IncomingEntry, incomingQueueSize),
+		logger:       cfg.Logger,
+		translations: translations,
+		brand:        cfg.Brand,
+		tags:         tags,
+		scheduler:    tick.NewScheduler(),
+		outgoing:     make(map[uuid.UUID]*outgoing),
+		world:        w,
+		viewDistance: cfg.ViewDistance,
+		players:      newPlayerList(),
+		maxPlayers:   cfg.MaxPlayers,
+		entities:     entity.NewManager(),
+		containers:   make(map[blockPos]*inventory.Inventory),
+
+		simulationDistance: cfg.SimulationDistance,
+		hashedSeed:         hashSeed(cfg.Seed),
+		flat:               cfg.Generator == "flat",
+		spawnProtection:    cfg.SpawnProtection,
+		spawn:              blockPos{x, y, z},
+	}
+	sv.tracker = newEntityTracker(cfg.ViewDistance, func(p *Player, packets ...common.ClientboundPacket) {
+		sv.send(p.client, packets...)
+	})
+	sv.paths = ai.NewPathfinder(w)
+	sv.entities.AddSystem(entity.RunAI)
+	sv.entities.AddSystem(sv.paths.Tick)
+	sv.entities.AddSystem(entity.Physics(w, world.MinY-voidDepth))
+	sv.trackEntities()
+	sv.giveMobsAI()
+	sv.loop = tick.NewLoop(cfg.TPS, sv.tick, cfg.Logger)
+	sv.scheduler.Every(uint64(keepAliveInterval.Seconds())*uint64(cfg.TPS), sv.keepAlive)
+	return sv, nil
 }
 
-// Handle adds a new packet entry to the channel.
+// Handle queues a packet entry to be handled on the next tick.
 func (sv *Supervisor) Handle(entry common.IncomingEntry) {
 	sv.incoming <- entry
 }
 
-// Start initializes the goroutine that handles incoming entries.
+// Disconnect queues the removal of a client that disconnected, after the
+// packets it sent before are handled.
+func (sv *Supervisor) Disconnect(c common.Client) {
+	sv.incoming <- common.IncomingEntry{Client: c}
+}
+
+// Players returns the players online.
+func (sv *Supervisor) Players() *PlayerList {
+	return sv.players
+}
+
+// Entities returns the entities other than players. It may only be used from
+// handlers and tasks.
+func (sv *Supervisor) Entities() *entity.Manager {
+	return sv.entities
+}
+
+// Start starts the game loop, which handles incoming entries.
 func (sv *Supervisor) Start() {
-	go sv.supervise()
+	sv.loop.Start()
 }
 
+// Stop stops the game loop after the current tick.
 func (sv *Supervisor) Stop() {
-	close(sv.incoming)
+	sv.loop.Stop()
+}
+
+// TickStats returns how the game loop is keeping up, for the tps command and
+// metrics. It is safe to call from any goroutine.
+func (sv *Supervisor) TickStats() tick.Stats {
+	return sv.loop.Stats()
+}
+
+// Scheduler runs tasks on the game loop. It may only be used from handlers and
+// other tasks.
+func (sv *Supervisor) Scheduler() *tick.Scheduler {
+	return sv.scheduler
 }
 
-func (sv *Supervisor) supervise() {
-	for {
-		entry, ok := <-sv.incoming
+// Localize translates the server's own messages into the client's locale.
+// Handlers should build messages with text.Translatable and pass them through
+// here before sending.
+func (sv *Supervisor) Localize(c common.Client, comp text.TextComponent) text.TextComponent {
+	locale := text.DefaultLocale
+	if p, ok := sv.players.ByConnection(c.GetUUID()); ok {
+		locale = p.Settings.Locale
+	}
+	return sv.translations.Localize(comp, locale)
+}
+
+// outgoing is the data waiting to be sent to a client at the end of the tick.
+type outgoing struct {
+	client common.Client
+	data   []byte
+}
+
+// send encodes packets and queues them for a client, to be sent together with
+// the rest of the tick's packets.
+func (sv *Supervisor) send(c common.Client, packets ...common.ClientboundPacket) {
+	out, ok := sv.outgoing[c.GetUUID()]
+	if !ok {
+		out = &outgoing{client: c}
+		sv.outgoing[c.GetUUID()] = out
+	}
+
+	for _, p := range packets {
+		frame, err := protocol.WriteUncompressedPacket(p)
+		if err != nil {
+			sv.logger.Error().Err(err).Msg("Unable to encode packet")
+			continue
+		}
+		out.data = append(out.data, frame...)
+	}
+}
+
+// broadcast sends packets to every player in the world.
+func (sv *Supervisor) broadcast(packets ...common.ClientboundPacket) {
+	for _, p := range sv.players.All() {
+		if p.playing {
+			sv.send(p.client, packets...)
+		}
+	}
+}
+
+// flush sends the packets queued during the tick.
+func (sv *Supervisor) flush() {
+	for id, out := range sv.outgoing {
+		if len(out.data) > 0 {
+			out.client.Send(out.data)
+		}
+		delete(sv.outgoing, id)
+	}
+}
+
+// sendLightUpdates sends the new light of chunks to the clients that have them
+// loaded.
+func (sv *Supervisor) sendLightUpdates(positions []world.ChunkPos) {
+	for _, pos := range positions {
+		c, ok := sv.world.LoadedChunk(pos)
 		if !ok {
-			return
+			continue
+		}
+		for _, p := range sv.players.All() {
+			if p.chunks.sent(pos) {
+				sv.send(p.client, clientbound.UpdateLightPacket{Chunk: c})
+			}
+		}
+	}
+}
+
+// tick runs one tick of the game loop: it handles the packets that arrived
+// since the last tick, advances the world and flushes the packets it sent.
+func (sv *Supervisor) tick() {
+	// only what is queued now, so a flood of packets can't stall the tick
+	for n := len(sv.incoming); n > 0; n-- {
+		entry := <-sv.incoming
+		if entry.Packet == nil {
+			sv.disconnect(entry.Client)
+			continue
 		}
-		switch packet := entry.Packet.(type) {
-		case *handshaking.HandshakePacket:
-			sv.logger.Info().Msg("Handshake packet recieved")
-		default:
-			sv.logger.Warn().Int("packet id", int(packet.MCPacketID())).Msg("Unknown packet type")
+		sv.handle(entry)
+	}
+
+	for n := len(sv.world.Ready()); n > 0; n-- {
+		if result := <-sv.world.Ready(); result.Err != nil {
+			sv.logger.Error().Err(result.Err).Msg("Unable to load chunk")
+		}
+	}
+
+	sv.scheduler.Tick()
+
+	sv.sendLightUpdates(sv.world.UpdateLight())
+	for _, p := range sv.players.All() {
+		if p.playing {
+			sv.tickMovement(p)
+			sv.tickDigging(p)
+			sv.syncWindow(p)
+		}
+		p.chunks.tick()
+	}
+	sv.entities.Tick()
+	sv.tracker.tick(sv.players.All())
+
+	sv.flush()
+	for _, c := range sv.closing {
+		c.Close()
+	}
+	sv.closing = sv.closing[:0]
+}
+
+func (sv *Supervisor) handle(entry common.IncomingEntry) {
+	switch packet := entry.Packet.(type) {
+	case *handshaking.HandshakePacket:
+		sv.logger.Info().Msg("Handshake packet recieved")
+	case *login.LoginStartPacket:
+		sv.login(entry.Client, packet)
+	default:
+		p, ok := sv.players.ByConnection(entry.Client.GetUUID())
+		if !ok {
+			sv.logger.Warn().Int("packet id", int(packet.MCPacketID())).Msg("Packet from client not logged in")
+			break
 		}
-		sv.logger.Info().Str("client uuid", entry.Client.GetUUID().String()).Send()
-		sv.logger.Info().Int("packet id", int(entry.Packet.MCPacketID())).Send()
-		sv.logger.Info().Str("packet uid", entry.Packet.PacketUID()).Send()
+		sv.handlePlayer(p, packet)
+	}
+	sv.logger.Info().Str("client uuid", entry.Client.GetUUID().String()).Send()
+	sv.logger.Info().Int("packet id", int(entry.Packet.MCPacketID())).Send()
+	sv.logger.Info().Str("packet uid", entry.Packet.PacketUID()).Send()
+}
+
+// handlePlayer handles a packet from a logged in player.
+func (sv *Supervisor) handlePlayer(p *Player, packet common.Packet) {
+	switch packet := packet.(type) {
+	case *login.LoginAcknowledgedPacket:
+		sv.startConfiguration(p)
+	case *configuration.KnownPacksPacket:
+		sv.sendRegistries(p, packet)
+	case *configuration.AcknowledgeFinishConfigurationPacket:
+		sv.join(p)
+	case *play.ConfirmTeleportationPacket:
+		sv.confirmTeleport(p, packet.TeleportID)
+	case *play.KeepAlivePacket:
+		sv.answerKeepAlive(p, packet.KeepAliveID)
+	case *configuration.KeepAlivePacket:
+		sv.answerKeepAlive(p, packet.KeepAliveID)
+	case *configuration.ClientInformationPacket:
+		sv.updateSettings(p, clientSettings(*packet))
+	case *play.ClientInformationPacket:
+		sv.updateSettings(p, clientSettings(packet.ClientInformationPacket))
+	case *play.SetPlayerPositionPacket:
+		sv.move(p, packet.X, packet.FeetY, packet.Z, p.Yaw, p.Pitch, packet.OnGround)
+	case *play.SetPlayerPositionAndRotationPacket:
+		sv.move(p, packet.X, packet.FeetY, packet.Z, packet.Yaw, packet.Pitch, packet.OnGround)
+	case *play.SetPlayerRotationPacket:
+		sv.rotate(p, packet.Yaw, packet.Pitch, packet.OnGround)
+	case *play.SetPlayerOnGroundPacket:
+		sv.rotate(p, p.Yaw, p.Pitch, packet.OnGround)
+	case *play.PlayerCommandPacket:
+		sv.playerCommand(p, packet.Action)
+	case *play.PlayerActionPacket:
+		sv.playerAction(p, packet)
+	case *play.UseItemOnPacket:
+		sv.useItemOn(p, packet)
+	case *play.ClickContainerPacket:
+		sv.clickContainer(p, packet)
+	case *play.CloseContainerPacket:
+		sv.closeContainer(p, packet.WindowID)
+	case *play.SetHeldItemPacket:
+		sv.setHeldItem(p, packet.Slot)
+	case *play.PlaceRecipePacket:
+		sv.placeRecipe(p, packet)
+	case *play.SetCreativeModeSlotPacket:
+		sv.setCreativeSlot(p, packet)
+	case *play.SwingArmPacket:
+		sv.swingArm(p, packet.Hand)
+	case *play.ChunkBatchReceivedPacket:
+		p.chunks.acknowledge(packet.ChunksPerTick)
+	case *play.ChatCommandPacket:
+		sv.runCommand(p.client, packet.Command)
+	default:
+		sv.logger.Warn().Int("packet id", int(packet.MCPacketID())).Msg("Unknown packet type")
 	}
 }
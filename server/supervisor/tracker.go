@@ -0,0 +1,314 @@
+package supervisor
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/entity"
+	"github.com/hunterros-s/algernon/server/common"
+	clientbound "github.com/hunterros-s/algernon/server/protocol/packet/packets/clientbound/play"
+	"github.com/hunterros-s/algernon/world"
+)
+
+// teleportInterval is how many ticks an entity is moved with deltas before its
+// absolute position is sent again, so rounding errors of the client don't
+// add up.
+const teleportInterval = 400
+
+// tracked is something shown to the players around it, like another player.
+type tracked interface {
+	entityID() int32
+	position() (x, y, z float64)
+	rotation() (yaw, pitch, headYaw float32)
+	onGround() bool
+	// velocity returns the velocity of the entity, if clients are sent it.
+	velocity() (x, y, z float64, ok bool)
+	metadata() *entity.Metadata
+	// trackingRange is how far away, in blocks, players see the entity. It
+	// is limited by the view distance.
+	trackingRange() float64
+	// spawnPackets are the packets showing the entity to a player.
+	spawnPackets() []common.ClientboundPacket
+}
+
+// trackedEntity is an entity with the players seeing it and the state they
+// were last sent.
+type trackedEntity struct {
+	entity  tracked
+	chunk   world.ChunkPos
+	viewers map[*Player]bool
+
+	// positions in 1/4096 blocks and angles in steps of 1/256 turns, as they
+	// are sent
+	x, y, z             int64
+	yaw, pitch, headYaw uint8
+	onGround            bool
+	sinceTeleport       int
+	// in blocks per tick
+	vx, vy, vz float64
+}
+
+// sync records the current state of the entity as sent.
+func (t *trackedEntity) sync() {
+	x, y, z := t.entity.position()
+	yaw, pitch, headYaw := t.entity.rotation()
+	t.x, t.y, t.z = encodePosition(x), encodePosition(y), encodePosition(z)
+	t.yaw, t.pitch, t.headYaw = angleStep(yaw), angleStep(pitch), angleStep(headYaw)
+	t.onGround = t.entity.onGround()
+	t.vx, t.vy, t.vz, _ = t.entity.velocity()
+}
+
+// entityTracker shows entities to the players in range of them and keeps the
+// players seeing an entity updated on its movement. Entities are indexed by
+// the chunk they are in, so players only look at the chunks around them.
+type entityTracker struct {
+	send func(p *Player, packets ...common.ClientboundPacket)
+	// the server's view distance, in blocks
+	maxRange float64
+
+	entities map[int32]*trackedEntity
+	chunks   map[world.ChunkPos]map[int32]*trackedEntity
+}
+
+func newEntityTracker(viewDistance int, send func(p *Player, packets ...common.ClientboundPacket)) *entityTracker {
+	return &entityTracker{
+		send:     send,
+		maxRange: float64(max(viewDistance, minViewDistance) * 16),
+		entities: make(map[int32]*trackedEntity),
+		chunks:   make(map[world.ChunkPos]map[int32]*trackedEntity),
+	}
+}
+
+// add starts tracking an entity. Players see it from the next tick.
+func (t *entityTracker) add(e tracked) {
+	x, _, z := e.position()
+	te := &trackedEntity{
+		entity:  e,
+		chunk:   chunkPosOf(x, z),
+		viewers: make(map[*Player]bool),
+	}
+	te.sync()
+	t.entities[e.entityID()] = te
+	t.index(te)
+}
+
+// remove stops tracking an entity, removing it for the players seeing it.
+func (t *entityTracker) remove(id int32) {
+	te, ok := t.entities[id]
+	if !ok {
+		return
+	}
+	for viewer := range te.viewers {
+		t.send(viewer, clientbound.RemoveEntitiesPacket{EntityIDs: []int32{id}})
+	}
+	t.unindex(te)
+	delete(t.entities, id)
+}
+
+// removeViewer forgets a player that left as a viewer of every entity.
+func (t *entityTracker) removeViewer(p *Player) {
+	for _, te := range t.entities {
+		delete(te.viewers, p)
+	}
+}
+
+// sendToViewers sends packets to the players seeing an entity.
+func (t *entityTracker) sendToViewers(id int32, packets ...common.ClientboundPacket) {
+	te, ok := t.entities[id]
+	if !ok {
+		return
+	}
+	for viewer := range te.viewers {
+		t.send(viewer, packets...)
+	}
+}
+
+func (t *entityTracker) index(te *trackedEntity) {
+	entities, ok := t.chunks[te.chunk]
+	if !ok {
+		entities = make(map[int32]*trackedEntity)
+		t.chunks[te.chunk] = entities
+	}
+	entities[te.entity.entityID()] = te
+}
+
+func (t *entityTracker) unindex(te *trackedEntity) {
+	entities := t.chunks[te.chunk]
+	delete(entities, te.entity.entityID())
+	if len(entities) == 0 {
+		delete(t.chunks, te.chunk)
+	}
+}
+
+// tick removes entities from the players that can't see them anymore, sends
+// the movement of entities to the players seeing them and shows entities to
+// the players that came in range.
+func (t *entityTracker) tick(players []*Player) {
+	removed := make(map[*Player][]int32)
+	for id, te := range t.entities {
+		x, _, z := te.entity.position()
+		if chunk := chunkPosOf(x, z); chunk != te.chunk {
+			t.unindex(te)
+			te.chunk = chunk
+			t.index(te)
+		}
+
+		for viewer := range te.viewers {
+			if !t.canSee(viewer, te) {
+				delete(te.viewers, viewer)
+				removed[viewer] = append(removed[viewer], id)
+			}
+		}
+		t.sendMovement(te)
+		t.sendMetadata(te)
+	}
+	for viewer, ids := range removed {
+		t.send(viewer, clientbound.RemoveEntitiesPacket{EntityIDs: ids})
+	}
+
+	for _, p := range players {
+		if !p.playing {
+			continue
+		}
+		radius := int(math.Ceil(t.maxRange/16)) + 1
+		spiral(chunkPosOf(p.X, p.Z), radius, func(pos world.ChunkPos) {
+			for _, te := range t.chunks[pos] {
+				if !te.viewers[p] && t.canSee(p, te) {
+					te.viewers[p] = true
+					t.send(p, te.entity.spawnPackets()...)
+				}
+			}
+		})
+	}
+}
+
+// canSee reports whether an entity is in range of a player and in a chunk the
+// player has loaded.
+func (t *entityTracker) canSee(p *Player, te *trackedEntity) bool {
+	if !p.playing || te.entity == tracked(p) || !p.chunks.sent(te.chunk) {
+		return false
+	}
+	x, _, z := te.entity.position()
+	dx, dz := x-p.X, z-p.Z
+	r := min(te.entity.trackingRange(), t.maxRange)
+	return dx*dx+dz*dz <= r*r
+}
+
+// sendMovement sends the viewers of an entity how it moved since the last
+// tick, as deltas when they fit and as a teleport otherwise.
+func (t *entityTracker) sendMovement(te *trackedEntity) {
+	x, y, z := te.entity.position()
+	yaw, pitch, headYaw := te.entity.rotation()
+	onGround := te.entity.onGround()
+	vx, vy, vz, tracksVelocity := te.entity.velocity()
+	id := te.entity.entityID()
+
+	dx := encodePosition(x) - te.x
+	dy := encodePosition(y) - te.y
+	dz := encodePosition(z) - te.z
+	moved := dx != 0 || dy != 0 || dz != 0
+	rotated := angleStep(yaw) != te.yaw || angleStep(pitch) != te.pitch
+	turnedHead := angleStep(headYaw) != te.headYaw
+	te.sinceTeleport++
+
+	var packets []common.ClientboundPacket
+	switch {
+	case te.sinceTeleport > teleportInterval || !fitsDelta(dx) || !fitsDelta(dy) || !fitsDelta(dz):
+		te.sinceTeleport = 0
+		packets = append(packets, clientbound.TeleportEntityPacket{
+			EntityID: id,
+			X:        x,
+			Y:        y,
+			Z:        z,
+			Yaw:      yaw,
+			Pitch:    pitch,
+			OnGround: onGround,
+		})
+	case moved && rotated:
+		packets = append(packets, clientbound.UpdateEntityPositionAndRotationPacket{
+			EntityID: id,
+			DeltaX:   int16(dx),
+			DeltaY:   int16(dy),
+			DeltaZ:   int16(dz),
+			Yaw:      yaw,
+			Pitch:    pitch,
+			OnGround: onGround,
+		})
+	case moved:
+		packets = append(packets, clientbound.UpdateEntityPositionPacket{
+			EntityID: id,
+			DeltaX:   int16(dx),
+			DeltaY:   int16(dy),
+			DeltaZ:   int16(dz),
+			OnGround: onGround,
+		})
+	case rotated || onGround != te.onGround:
+		packets = append(packets, clientbound.UpdateEntityRotationPacket{
+			EntityID: id,
+			Yaw:      yaw,
+			Pitch:    pitch,
+			OnGround: onGround,
+		})
+	}
+	if turnedHead {
+		packets = append(packets, clientbound.SetHeadRotationPacket{EntityID: id, HeadYaw: headYaw})
+	}
+	if tracksVelocity && velocityChanged(te.vx, te.vy, te.vz, vx, vy, vz) {
+		packets = append(packets, clientbound.SetEntityVelocityPacket{
+			EntityID:  id,
+			VelocityX: encodeVelocity(vx),
+			VelocityY: encodeVelocity(vy),
+			VelocityZ: encodeVelocity(vz),
+		})
+	}
+	te.sync()
+
+	if len(packets) == 0 {
+		return
+	}
+	for viewer := range te.viewers {
+		t.send(viewer, packets...)
+	}
+}
+
+// sendMetadata sends the metadata that changed to the viewers of an entity.
+// Players are sent their own metadata too.
+func (t *entityTracker) sendMetadata(te *trackedEntity) {
+	changes := te.entity.metadata().Changes()
+	if len(changes) == 0 {
+		return
+	}
+	packet := clientbound.SetEntityMetadataPacket{EntityID: te.entity.entityID(), Metadata: changes}
+	for viewer := range te.viewers {
+		t.send(viewer, packet)
+	}
+	if p, ok := te.entity.(*Player); ok {
+		t.send(p, packet)
+	}
+}
+
+// velocityChanged reports whether a velocity changed enough to be sent again,
+// or came to a stop.
+func velocityChanged(oldX, oldY, oldZ, x, y, z float64) bool {
+	dx, dy, dz := x-oldX, y-oldY, z-oldZ
+	if dx*dx+dy*dy+dz*dz > 1e-7 {
+		return true
+	}
+	stopped := x == 0 && y == 0 && z == 0
+	return stopped && (oldX != 0 || oldY != 0 || oldZ != 0)
+}
+
+// encodePosition converts a coordinate to the 1/4096 blocks deltas are in.
+func encodePosition(v float64) int64 {
+	return int64(math.Round(v * 4096))
+}
+
+// fitsDelta reports whether a change of position fits in a delta, which is
+// less than 8 blocks.
+func fitsDelta(d int64) bool {
+	return d >= math.MinInt16 && d <= math.MaxInt16
+}
+
+// angleStep converts degrees to the steps of 1/256 turns angles are sent in.
+func angleStep(degrees float32) uint8 {
+	return uint8(int32(math.Floor(float64(degrees) * 256 / 360)))
+}
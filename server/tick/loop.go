@@ -0,0 +1,150 @@
+// Package tick runs the game loop at a fixed rate and measures how long ticks
+// take.
+package tick
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// maxLag is how far the loop may fall behind before it gives up catching up
+// and skips the missed ticks, like vanilla's "Can't keep up!".
+const maxLag = 2 * time.Second
+
+// sampleCount is how many of the latest ticks the statistics cover.
+const sampleCount = 100
+
+// Stats describes how the loop has been keeping up.
+type Stats struct {
+	TPS     float64       // ticks per second over the latest ticks
+	MSPT    float64       // average milliseconds spent per tick
+	MaxMSPT float64       // slowest of the latest ticks
+	Ticks   uint64        // ticks run since start
+	Skipped uint64        // ticks skipped because the loop fell too far behind
+	Target  time.Duration // time between ticks
+}
+
+// Loop calls a function at a fixed rate. When a tick runs late the following
+// ticks run back to back until the loop catches up.
+type Loop struct {
+	rate   time.Duration
+	tick   func()
+	logger zerolog.Logger
+
+	stop chan struct{}
+	done chan struct{}
+
+	mutex     sync.Mutex
+	durations [sampleCount]time.Duration
+	starts    [sampleCount]time.Time
+	ticks     uint64
+	skipped   uint64
+}
+
+// NewLoop creates a loop running tick tps times per second.
+func NewLoop(tps int, tick func(), logger zerolog.Logger) *Loop {
+	if tps <= 0 {
+		tps = 20
+	}
+	return &Loop{
+		rate:   time.Second / time.Duration(tps),
+		tick:   tick,
+		logger: logger,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start runs the loop in a new goroutine.
+func (l *Loop) Start() {
+	go l.run()
+}
+
+// Stop stops the loop after the current tick and waits for it.
+func (l *Loop) Stop() {
+	close(l.stop)
+	<-l.done
+}
+
+func (l *Loop) run() {
+	defer close(l.done)
+
+	next := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-timer.C:
+		}
+
+		start := time.Now()
+		if lag := start.Sub(next); lag > maxLag {
+			skipped := uint64(lag / l.rate)
+			l.logger.Warn().
+				Dur("behind", lag).
+				Uint64("ticks", skipped).
+				Msg("Can't keep up! Is the server overloaded?")
+
+			l.mutex.Lock()
+			l.skipped += skipped
+			l.mutex.Unlock()
+			next = start
+		}
+
+		l.tick()
+		l.record(start, time.Since(start))
+
+		next = next.Add(l.rate)
+		timer.Reset(time.Until(next))
+	}
+}
+
+func (l *Loop) record(start time.Time, duration time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	i := l.ticks % sampleCount
+	l.starts[i] = start
+	l.durations[i] = duration
+	l.ticks++
+}
+
+// Stats returns the loop's statistics. It is safe to call from any goroutine.
+func (l *Loop) Stats() Stats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	stats := Stats{Ticks: l.ticks, Skipped: l.skipped, Target: l.rate}
+	n := int(min(l.ticks, sampleCount))
+	if n == 0 {
+		return stats
+	}
+
+	var total time.Duration
+	for _, d := range l.durations[:n] {
+		total += d
+		stats.MaxMSPT = max(stats.MaxMSPT, milliseconds(d))
+	}
+	stats.MSPT = milliseconds(total) / float64(n)
+
+	target := float64(time.Second) / float64(l.rate)
+	stats.TPS = target
+	if n > 1 {
+		newest := l.starts[(l.ticks-1)%sampleCount]
+		oldest := l.starts[(l.ticks-uint64(n))%sampleCount]
+		if elapsed := newest.Sub(oldest); elapsed > 0 {
+			// ticks running back to back to catch up don't count as faster
+			stats.TPS = min(target, float64(n-1)/elapsed.Seconds())
+		}
+	}
+	return stats
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,88 @@
+package tick
+
+import "container/heap"
+
+// Scheduler runs tasks a number of ticks in the future. It isn't safe for
+// concurrent use, tasks are scheduled and run on the game loop.
+type Scheduler struct {
+	tick  uint64
+	seq   uint64
+	tasks taskQueue
+}
+
+// Task is a scheduled function.
+type Task struct {
+	due       uint64
+	seq       uint64 // keeps tasks due on the same tick in scheduling order
+	period    uint64 // 0 for tasks that run once
+	run       func()
+	cancelled bool
+}
+
+// Cancel stops a task from running again.
+func (t *Task) Cancel() {
+	t.cancelled = true
+}
+
+// NewScheduler creates a scheduler without tasks.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// After runs f once, delay ticks from now. A delay of 0 runs it on the next
+// tick.
+func (s *Scheduler) After(delay uint64, f func()) *Task {
+	return s.schedule(delay, 0, f)
+}
+
+// Every runs f every period ticks, starting period ticks from now.
+func (s *Scheduler) Every(period uint64, f func()) *Task {
+	period = max(period, 1)
+	return s.schedule(period, period, f)
+}
+
+func (s *Scheduler) schedule(delay, period uint64, f func()) *Task {
+	s.seq++
+	t := &Task{due: s.tick + max(delay, 1), seq: s.seq, period: period, run: f}
+	heap.Push(&s.tasks, t)
+	return t
+}
+
+// Tick advances the scheduler one tick and runs the tasks that are due.
+func (s *Scheduler) Tick() {
+	s.tick++
+	for len(s.tasks) > 0 && s.tasks[0].due <= s.tick {
+		t := heap.Pop(&s.tasks).(*Task)
+		if t.cancelled {
+			continue
+		}
+		t.run()
+		if t.period > 0 && !t.cancelled {
+			t.due = s.tick + t.period
+			heap.Push(&s.tasks, t)
+		}
+	}
+}
+
+// taskQueue is a heap of tasks ordered by due tick.
+type taskQueue []*Task
+
+func (q taskQueue) Len() int { return len(q) }
+
+func (q taskQueue) Less(i, j int) bool {
+	if q[i].due != q[j].due {
+		return q[i].due < q[j].due
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q taskQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *taskQueue) Push(x any) { *q = append(*q, x.(*Task)) }
+
+func (q *taskQueue) Pop() any {
+	old := *q
+	t := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return t
+}
@@ -1,21 +1,75 @@
 package tcpserver
 
 import (
+	"errors"
 	"net"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/uuid"
 )
 
+// sendQueueSize is how many messages may wait to be written to a client before
+// it is disconnected for not keeping up.
+const sendQueueSize = 256
+
+// ErrSendQueueFull is reported when a client is disconnected because it didn't
+// read what was sent to it fast enough.
+var ErrSendQueueFull = errors.New("tcpserver: send queue full")
+
 type Client struct {
 	conn   net.Conn
 	server *TCPServer
 	uuid   uuid.UUID
 	send   chan []byte
+
+	// guards send, which is closed once the client is gone
+	sendMutex sync.Mutex
+	closed    bool
+	// set by Close, so the client isn't reported as having failed
+	closing atomic.Bool
+	// set when the send queue overflowed
+	overflowed atomic.Bool
 }
 
+// Send queues a message to be written without waiting. Messages to clients that
+// are gone are dropped, and clients whose queue is full are disconnected.
 func (c *Client) Send(message []byte) {
-	c.send <- message
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+	if c.closed {
+		return
+	}
+	select {
+	case c.send <- message:
+	default:
+		c.overflowed.Store(true)
+		c.closed = true
+		close(c.send)
+		// ends both loops without writing the rest
+		c.conn.Close()
+	}
+}
+
+// Close disconnects the client once the messages sent before are written.
+func (c *Client) Close() {
+	c.closing.Store(true)
+	if tcp, ok := c.conn.(*net.TCPConn); ok {
+		// ends the read loop, which stops the writer after what is queued
+		tcp.CloseRead()
+		return
+	}
+	c.conn.Close()
+}
+
+// closeSend stops the writer once it has written the queued messages.
+func (c *Client) closeSend() {
+	c.sendMutex.Lock()
+	defer c.sendMutex.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
 }
 
 func (c *Client) GetUUID() uuid.UUID {
@@ -31,7 +85,7 @@ func NewClient(conn net.Conn, server *TCPServer) *Client {
 		conn:   conn,
 		server: server,
 		uuid:   uuid.New(),
-		send:   make(chan []byte),
+		send:   make(chan []byte, sendQueueSize),
 	}
 }
 
@@ -122,7 +176,7 @@ func (s *TCPServer) Stop() {
 	s.mutex.Lock()
 	for _, client := range s.clients {
 		client.conn.Close()
-		close(client.send)
+		client.closeSend()
 	}
 	s.mutex.Unlock()
 
@@ -162,13 +216,20 @@ func (s *TCPServer) acceptConnections() {
 }
 
 func (s *TCPServer) handleClient(client *Client) {
-	defer client.conn.Close()
 	defer s.wg.Done()
 
+	// the writer closes the connection, so messages queued before the read loop
+	// ended still go out
 	go func() {
+		failed := false
 		for message := range client.send {
-			client.conn.Write(message)
+			// drained after a failed write so Send doesn't see a full queue
+			if !failed {
+				_, err := client.conn.Write(message)
+				failed = err != nil
+			}
 		}
+		client.conn.Close()
 	}()
 
 	buffer := make([]byte, 4096)
@@ -181,11 +242,16 @@ func (s *TCPServer) handleClient(client *Client) {
 			return
 		}
 		if err != nil {
-			close(client.send)
+			client.closeSend()
 			s.mutex.Lock()
 			delete(s.clients, client.GetUUID())
 			s.mutex.Unlock()
 
+			if client.overflowed.Load() {
+				err = ErrSendQueueFull
+			} else if client.closing.Load() {
+				err = nil
+			}
 			if s.onClientClosed != nil {
 				s.onClientClosed(client, err)
 			}
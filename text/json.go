@@ -0,0 +1,318 @@
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// componentJSON is the wire layout of a component object. Text is a pointer so
+// it is only written for text components, vanilla decides the content type by
+// the first content key it finds.
+type componentJSON struct {
+	Type  string          `json:"type,omitempty"`
+	Extra []TextComponent `json:"extra,omitempty"`
+
+	Text      *string         `json:"text,omitempty"`
+	Translate string          `json:"translate,omitempty"`
+	Fallback  string          `json:"fallback,omitempty"`
+	With      []TextComponent `json:"with,omitempty"`
+	Score     *ScoreContent   `json:"score,omitempty"`
+	Selector  string          `json:"selector,omitempty"`
+	Separator *TextComponent  `json:"separator,omitempty"`
+	Keybind   string          `json:"keybind,omitempty"`
+	NBT       string          `json:"nbt,omitempty"`
+	Interpret bool            `json:"interpret,omitempty"`
+	Block     string          `json:"block,omitempty"`
+	Entity    string          `json:"entity,omitempty"`
+	Storage   string          `json:"storage,omitempty"`
+	Source    string          `json:"source,omitempty"`
+
+	Color         string          `json:"color,omitempty"`
+	Bold          bool            `json:"bold,omitempty"`
+	Italic        bool            `json:"italic,omitempty"`
+	Underlined    bool            `json:"underlined,omitempty"`
+	Strikethrough bool            `json:"strikethrough,omitempty"`
+	Obfuscated    bool            `json:"obfuscated,omitempty"`
+	Font          string          `json:"font,omitempty"`
+	Insertion     string          `json:"insertion,omitempty"`
+	ClickEvent    *ClickEventData `json:"clickEvent,omitempty"`
+	HoverEvent    *HoverEventData `json:"hoverEvent,omitempty"`
+
+	// newer clients spell the events in snake case, accept both when decoding
+	ClickEventSnake *ClickEventData `json:"click_event,omitempty"`
+	HoverEventSnake *HoverEventData `json:"hover_event,omitempty"`
+}
+
+func (comp TextComponent) MarshalJSON() ([]byte, error) {
+	raw := componentJSON{
+		Type:          comp.Type,
+		Extra:         comp.Children,
+		Color:         comp.Color,
+		Bold:          comp.Bold,
+		Italic:        comp.Italic,
+		Underlined:    comp.Underlined,
+		Strikethrough: comp.Strikethrough,
+		Obfuscated:    comp.Obfuscated,
+		Font:          comp.Font,
+		Insertion:     comp.Insertion,
+		ClickEvent:    comp.ClickEvent,
+		HoverEvent:    comp.HoverEvent,
+	}
+
+	switch comp.ContentType() {
+	case TextType:
+		text := comp.Text
+		raw.Text = &text
+	case TranslateType:
+		raw.Translate = comp.Translate
+		raw.Fallback = comp.Fallback
+		raw.With = comp.With
+	case ScoreType:
+		raw.Score = comp.Score
+	case SelectorType:
+		raw.Selector = comp.Selector
+		raw.Separator = comp.Separator
+	case KeybindType:
+		raw.Keybind = comp.Keybind
+	case NBTType:
+		raw.NBT = comp.NBT
+		raw.Interpret = comp.Interpret
+		raw.Separator = comp.Separator
+		raw.Block = comp.Block
+		raw.Entity = comp.Entity
+		raw.Storage = comp.Storage
+		raw.Source = comp.Source
+	default:
+		return nil, fmt.Errorf("unknown text component type: %s", comp.Type)
+	}
+
+	return json.Marshal(raw)
+}
+
+// UnmarshalJSON accepts every form vanilla does: a bare string or primitive, an
+// array whose first element is the parent of the rest, or a component object.
+func (comp *TextComponent) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errors.New("empty text component")
+	}
+
+	switch data[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*comp = TextComponent{Text: s}
+		return nil
+
+	case '[':
+		var list []TextComponent
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return errors.New("text component array must not be empty")
+		}
+		*comp = list[0]
+		comp.Children = append(comp.Children, list[1:]...)
+		return nil
+
+	case '{':
+		var raw componentJSON
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return err
+		}
+		return comp.fromJSON(raw)
+
+	case 'n':
+		return errors.New("text component must not be null")
+
+	default:
+		// numbers and booleans are shown as literal text
+		*comp = TextComponent{Text: string(data)}
+		return nil
+	}
+}
+
+func (comp *TextComponent) fromJSON(raw componentJSON) error {
+	*comp = TextComponent{
+		Type:          raw.Type,
+		Children:      raw.Extra,
+		Color:         raw.Color,
+		Bold:          raw.Bold,
+		Italic:        raw.Italic,
+		Underlined:    raw.Underlined,
+		Strikethrough: raw.Strikethrough,
+		Obfuscated:    raw.Obfuscated,
+		Font:          raw.Font,
+		Insertion:     raw.Insertion,
+		ClickEvent:    raw.ClickEvent,
+		HoverEvent:    raw.HoverEvent,
+	}
+	if comp.ClickEvent == nil {
+		comp.ClickEvent = raw.ClickEventSnake
+	}
+	if comp.HoverEvent == nil {
+		comp.HoverEvent = raw.HoverEventSnake
+	}
+
+	contentType := raw.Type
+	if contentType == "" {
+		contentType = inferContentType(raw)
+	}
+
+	switch contentType {
+	case TextType:
+		if raw.Text == nil {
+			return errors.New("text component is missing text")
+		}
+		comp.Text = *raw.Text
+	case TranslateType:
+		if raw.Translate == "" {
+			return errors.New("translate component is missing translate")
+		}
+		comp.Translate = raw.Translate
+		comp.Fallback = raw.Fallback
+		comp.With = raw.With
+	case ScoreType:
+		if raw.Score == nil {
+			return errors.New("score component is missing score")
+		}
+		comp.Score = raw.Score
+	case SelectorType:
+		comp.Selector = raw.Selector
+		comp.Separator = raw.Separator
+	case KeybindType:
+		comp.Keybind = raw.Keybind
+	case NBTType:
+		comp.NBT = raw.NBT
+		comp.Interpret = raw.Interpret
+		comp.Separator = raw.Separator
+		comp.Block = raw.Block
+		comp.Entity = raw.Entity
+		comp.Storage = raw.Storage
+		comp.Source = raw.Source
+	default:
+		return fmt.Errorf("unknown text component type: %s", raw.Type)
+	}
+
+	return nil
+}
+
+func inferContentType(raw componentJSON) string {
+	switch {
+	case raw.Text != nil:
+		return TextType
+	case raw.Translate != "":
+		return TranslateType
+	case raw.Score != nil:
+		return ScoreType
+	case raw.Selector != "":
+		return SelectorType
+	case raw.Keybind != "":
+		return KeybindType
+	case raw.NBT != "":
+		return NBTType
+	default:
+		// vanilla rejects these, but an empty object is harmless to show as ""
+		return TextType
+	}
+}
+
+func (h HoverEventData) MarshalJSON() ([]byte, error) {
+	raw := struct {
+		Action   string `json:"action"`
+		Contents any    `json:"contents,omitempty"`
+	}{Action: h.Action}
+
+	if h.Contents != nil {
+		if h.Action == ShowTextAction && h.Contents.Text != nil {
+			raw.Contents = h.Contents.Text
+		} else {
+			raw.Contents = h.Contents
+		}
+	}
+
+	return json.Marshal(raw)
+}
+
+func (h *HoverEventData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Action   string          `json:"action"`
+		Contents json.RawMessage `json:"contents"`
+		Value    json.RawMessage `json:"value"` // pre 1.16
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	h.Action = raw.Action
+	h.Contents = nil
+
+	contents := raw.Contents
+	if len(contents) == 0 {
+		contents = raw.Value
+	}
+	if len(contents) == 0 {
+		return nil
+	}
+
+	h.Contents = &HoverContents{}
+	switch {
+	case raw.Action == ShowTextAction:
+		var t TextComponent
+		if err := json.Unmarshal(contents, &t); err != nil {
+			return err
+		}
+		h.Contents.Text = &t
+		return nil
+	case raw.Action == ShowItemAction && contents[0] == '"':
+		// an item can be given by its id alone
+		return json.Unmarshal(contents, &h.Contents.ID)
+	default:
+		return json.Unmarshal(contents, h.Contents)
+	}
+}
+
+func (h *HoverContents) UnmarshalJSON(data []byte) error {
+	type alias HoverContents
+	raw := struct {
+		*alias
+		ID json.RawMessage `json:"id,omitempty"`
+	}{alias: (*alias)(h)}
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.ID) == 0 {
+		return nil
+	}
+
+	id, err := decodeID(raw.ID)
+	if err != nil {
+		return err
+	}
+	h.ID = id
+	return nil
+}
+
+// decodeID reads an item id or entity uuid, which may be a string or, for
+// entities, a uuid stored as four ints.
+func decodeID(data json.RawMessage) (string, error) {
+	if data[0] == '"' {
+		var s string
+		err := json.Unmarshal(data, &s)
+		return s, err
+	}
+
+	var ints [4]int32
+	if err := json.Unmarshal(data, &ints); err != nil {
+		return "", fmt.Errorf("invalid hover event id: %w", err)
+	}
+	return fmt.Sprintf("%08x-%04x-%04x-%04x-%04x%08x",
+		uint32(ints[0]), uint32(ints[1])>>16, uint32(ints[1])&0xFFFF,
+		uint32(ints[2])>>16, uint32(ints[2])&0xFFFF, uint32(ints[3])), nil
+}
@@ -0,0 +1,109 @@
+package text
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the language vanilla falls back to when a key is missing.
+const DefaultLocale = "en_us"
+
+// A subset of the vanilla en_us language file covering the keys the server
+// itself produces. Anything missing renders as the raw translation key.
+//
+//go:embed lang/en_us.json
+var enUSData []byte
+
+var enUS = mustLoadLanguage(enUSData)
+
+func mustLoadLanguage(data []byte) map[string]string {
+	lang := map[string]string{}
+	if err := json.Unmarshal(data, &lang); err != nil {
+		panic("text: invalid bundled language file: " + err.Error())
+	}
+	return lang
+}
+
+// Lookup returns the en_us format string for a translation key.
+func Lookup(key string) (string, bool) {
+	format, ok := enUS[key]
+	return format, ok
+}
+
+// segment is either a literal piece of a translation format string or, when
+// arg is >= 0, a reference to one of its arguments.
+type segment struct {
+	literal string
+	arg     int
+}
+
+// splitTranslation parses a vanilla translation format string. Both sequential
+// (%s) and positional (%1$s) placeholders are supported, and %% escapes a
+// literal percent sign.
+func splitTranslation(format string) []segment {
+	segments := []segment{}
+	var sb strings.Builder
+	next := 0
+
+	flush := func() {
+		if sb.Len() > 0 {
+			segments = append(segments, segment{literal: sb.String(), arg: -1})
+			sb.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 >= len(format) {
+			sb.WriteByte(format[i])
+			continue
+		}
+
+		if format[i+1] == '%' {
+			sb.WriteByte('%')
+			i++
+			continue
+		}
+
+		if format[i+1] == 's' {
+			flush()
+			segments = append(segments, segment{arg: next})
+			next++
+			i++
+			continue
+		}
+
+		// positional argument, e.g. %2$s
+		end := strings.Index(format[i+1:], "$s")
+		if end < 0 {
+			sb.WriteByte(format[i])
+			continue
+		}
+		index, err := strconv.Atoi(format[i+1 : i+1+end])
+		if err != nil || index < 1 {
+			sb.WriteByte(format[i])
+			continue
+		}
+		flush()
+		segments = append(segments, segment{arg: index - 1})
+		i += end + 2
+	}
+
+	flush()
+	return segments
+}
+
+// formatTranslation substitutes args into a translation format string. Missing
+// args render as empty strings.
+func formatTranslation(format string, args []string) string {
+	var sb strings.Builder
+	for _, seg := range splitTranslation(format) {
+		if seg.arg < 0 {
+			sb.WriteString(seg.literal)
+		} else if seg.arg < len(args) {
+			sb.WriteString(args[seg.arg])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+package text
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hunterros-s/algernon/nbt"
+)
+
+// MarshalNBT converts a component to the NBT form sent in play since 1.20.3, built
+// from the same layout as its JSON form. Booleans become bytes and lists of
+// components become lists of compounds.
+//
+// https://wiki.vg/Text_formatting#Text_components
+func (comp TextComponent) MarshalNBT() (any, error) {
+	data, err := json.Marshal(comp)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return jsonToNBT(v)
+}
+
+func jsonToNBT(v any) (any, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return v, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil && i >= math.MinInt32 && i <= math.MaxInt32 {
+			return int32(i), nil
+		}
+		return v.Float64()
+	case []any:
+		list := make([]any, len(v))
+		for i, elem := range v {
+			converted, err := jsonToNBT(elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case map[string]any:
+		compound := make(map[string]any, len(v))
+		for key, elem := range v {
+			converted, err := jsonToNBT(elem)
+			if err != nil {
+				return nil, err
+			}
+			compound[key] = converted
+		}
+		return compound, nil
+	}
+	return nil, fmt.Errorf("text component value %v has no NBT form", v)
+}
+
+// UnmarshalNBT reads a component from its NBT form, the reverse of MarshalNBT.
+// Bytes are taken as booleans, which is all components use them for.
+func (comp *TextComponent) UnmarshalNBT(v any) error {
+	j, err := nbtToJSON(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return comp.UnmarshalJSON(data)
+}
+
+func nbtToJSON(v any) (any, error) {
+	switch v := v.(type) {
+	case string, int16, int32, int64, float32, float64:
+		return v, nil
+	case int8:
+		return v != 0, nil
+	case nbt.List:
+		list := make([]any, len(v))
+		for i, elem := range v {
+			converted, err := nbtToJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case nbt.Compound:
+		object := make(map[string]any, len(v))
+		for key, elem := range v {
+			converted, err := nbtToJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = converted
+		}
+		return object, nil
+	}
+	return nil, fmt.Errorf("NBT tag %T has no text component form", v)
+}
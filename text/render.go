@@ -0,0 +1,216 @@
+package text
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColorMode selects which escape sequences ANSI uses for colors.
+type ColorMode int
+
+const (
+	ANSI256   ColorMode = iota // xterm 256 color palette
+	TrueColor                  // 24-bit color
+)
+
+var namedColors = map[string][3]uint8{
+	"black":        {0x00, 0x00, 0x00},
+	"dark_blue":    {0x00, 0x00, 0xAA},
+	"dark_green":   {0x00, 0xAA, 0x00},
+	"dark_aqua":    {0x00, 0xAA, 0xAA},
+	"dark_red":     {0xAA, 0x00, 0x00},
+	"dark_purple":  {0xAA, 0x00, 0xAA},
+	"gold":         {0xFF, 0xAA, 0x00},
+	"gray":         {0xAA, 0xAA, 0xAA},
+	"dark_gray":    {0x55, 0x55, 0x55},
+	"blue":         {0x55, 0x55, 0xFF},
+	"green":        {0x55, 0xFF, 0x55},
+	"aqua":         {0x55, 0xFF, 0xFF},
+	"red":          {0xFF, 0x55, 0x55},
+	"light_purple": {0xFF, 0x55, 0xFF},
+	"yellow":       {0xFF, 0xFF, 0x55},
+	"white":        {0xFF, 0xFF, 0xFF},
+}
+
+const ansiReset = "\x1b[0m"
+
+// style is the formatting a component inherits from its parents.
+type style struct {
+	color         string
+	bold          bool
+	italic        bool
+	underlined    bool
+	strikethrough bool
+	obfuscated    bool
+}
+
+func (s style) inherit(comp TextComponent) style {
+	if comp.Color != "" {
+		s.color = comp.Color
+	}
+	s.bold = s.bold || comp.Bold
+	s.italic = s.italic || comp.Italic
+	s.underlined = s.underlined || comp.Underlined
+	s.strikethrough = s.strikethrough || comp.Strikethrough
+	s.obfuscated = s.obfuscated || comp.Obfuscated
+	return s
+}
+
+// PlainText flattens a component tree into unformatted text, resolving
+// translate components against the bundled en_us language.
+func PlainText(comp TextComponent) string {
+	var sb strings.Builder
+	writePlain(&sb, comp)
+	return sb.String()
+}
+
+func writePlain(sb *strings.Builder, comp TextComponent) {
+	sb.WriteString(resolveContent(comp))
+	for _, child := range comp.Children {
+		writePlain(sb, child)
+	}
+}
+
+// ANSI flattens a component tree into text colored with terminal escape codes.
+func ANSI(comp TextComponent, mode ColorMode) string {
+	var sb strings.Builder
+	writeANSI(&sb, comp, style{}, mode)
+	sb.WriteString(ansiReset)
+	return sb.String()
+}
+
+func writeANSI(sb *strings.Builder, comp TextComponent, parent style, mode ColorMode) {
+	s := parent.inherit(comp)
+
+	if content := resolveContent(comp); content != "" {
+		sb.WriteString(ansiReset)
+		sb.WriteString(s.escape(mode))
+		sb.WriteString(content)
+	}
+
+	for _, child := range comp.Children {
+		writeANSI(sb, child, s, mode)
+	}
+}
+
+func (s style) escape(mode ColorMode) string {
+	codes := []string{}
+	if s.bold {
+		codes = append(codes, "1")
+	}
+	if s.italic {
+		codes = append(codes, "3")
+	}
+	if s.underlined {
+		codes = append(codes, "4")
+	}
+	if s.obfuscated {
+		codes = append(codes, "5")
+	}
+	if s.strikethrough {
+		codes = append(codes, "9")
+	}
+	if rgb, ok := parseColor(s.color); ok {
+		codes = append(codes, colorCode(rgb, mode))
+	}
+
+	if len(codes) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func colorCode(rgb [3]uint8, mode ColorMode) string {
+	if mode == TrueColor {
+		return fmt.Sprintf("38;2;%d;%d;%d", rgb[0], rgb[1], rgb[2])
+	}
+	return fmt.Sprintf("38;5;%d", to256(rgb))
+}
+
+// parseColor accepts both named colors and #RRGGBB hex colors.
+func parseColor(color string) ([3]uint8, bool) {
+	if rgb, ok := namedColors[color]; ok {
+		return rgb, true
+	}
+	if len(color) != 7 || color[0] != '#' {
+		return [3]uint8{}, false
+	}
+	v, err := strconv.ParseUint(color[1:], 16, 32)
+	if err != nil {
+		return [3]uint8{}, false
+	}
+	return [3]uint8{uint8(v >> 16), uint8(v >> 8), uint8(v)}, true
+}
+
+// to256 picks the closest color from the 6x6x6 cube or the grayscale ramp of
+// the xterm 256 color palette.
+func to256(rgb [3]uint8) int {
+	cube := func(c uint8) int {
+		if c < 48 {
+			return 0
+		}
+		if c < 115 {
+			return 1
+		}
+		return (int(c) - 35) / 40
+	}
+	levels := [6]int{0, 95, 135, 175, 215, 255}
+
+	r, g, b := cube(rgb[0]), cube(rgb[1]), cube(rgb[2])
+	cubeIndex := 16 + 36*r + 6*g + b
+	cubeDist := distance(rgb, levels[r], levels[g], levels[b])
+
+	avg := (int(rgb[0]) + int(rgb[1]) + int(rgb[2])) / 3
+	gray := 23
+	if avg < 238 {
+		gray = (avg - 3) / 10
+		if gray < 0 {
+			gray = 0
+		}
+	}
+	level := 8 + 10*gray
+	grayDist := distance(rgb, level, level, level)
+
+	if grayDist < cubeDist {
+		return 232 + gray
+	}
+	return cubeIndex
+}
+
+func distance(rgb [3]uint8, r, g, b int) int {
+	dr, dg, db := int(rgb[0])-r, int(rgb[1])-g, int(rgb[2])-b
+	return dr*dr + dg*dg + db*db
+}
+
+// resolveContent returns the text a single component contributes, excluding
+// its children. Scores and nbt paths need game state and render empty.
+func resolveContent(comp TextComponent) string {
+	switch comp.ContentType() {
+	case TranslateType:
+		format, ok := Lookup(comp.Translate)
+		if !ok {
+			if comp.Fallback != "" {
+				format = comp.Fallback
+			} else {
+				return comp.Translate
+			}
+		}
+		args := make([]string, len(comp.With))
+		for i, arg := range comp.With {
+			args[i] = PlainText(arg)
+		}
+		return formatTranslation(format, args)
+	case KeybindType:
+		if name, ok := Lookup(comp.Keybind); ok {
+			return name
+		}
+		return comp.Keybind
+	case SelectorType:
+		return comp.Selector
+	case TextType:
+		return comp.Text
+	default:
+		return ""
+	}
+}
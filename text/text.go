@@ -1,6 +1,9 @@
 package text
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // Content types
 const (
@@ -42,34 +45,86 @@ type ClickEventData struct {
 }
 
 type HoverEventData struct {
-	Action   string        `json:"action" nbt:"action"`
-	Contents HoverContents `json:"contents" nbt:"contents"`
+	Action   string         `json:"action" nbt:"action"`
+	Contents *HoverContents `json:"contents,omitempty" nbt:"contents,omitempty"`
 }
 
+// HoverContents holds the payload of a hover event. Text is only used by
+// show_text, the remaining fields by show_item and show_entity.
 type HoverContents struct {
-	ID    string `json:"id,omitempty" nbt:"id,omitempty"`
-	Count int    `json:"count,omitempty" nbt:"count,omitempty"`
-	Tag   string `json:"tag,omitempty" nbt:"tag,omitempty"`
-	Type  string `json:"type,omitempty" nbt:"type,omitempty"`
-	Name  string `json:"name,omitempty" nbt:"name,omitempty"`
+	Text *TextComponent `json:"-" nbt:"-"`
+
+	// show_item
+	ID         string                     `json:"id,omitempty" nbt:"id,omitempty"`
+	Count      int                        `json:"count,omitempty" nbt:"count,omitempty"`
+	Components map[string]json.RawMessage `json:"components,omitempty" nbt:"components,omitempty"`
+	Tag        string                     `json:"tag,omitempty" nbt:"tag,omitempty"`
+
+	// show_entity, ID holds the entity uuid
+	Type string         `json:"type,omitempty" nbt:"type,omitempty"`
+	Name *TextComponent `json:"name,omitempty" nbt:"name,omitempty"`
+}
+
+// ScoreContent is the content of a score component.
+type ScoreContent struct {
+	Name      string `json:"name" nbt:"name"`
+	Objective string `json:"objective" nbt:"objective"`
 }
 
 type TextComponent struct {
 	Type     string          `json:"type,omitempty" nbt:"type,omitempty"`
 	Children []TextComponent `json:"extra,omitempty" nbt:"extra,omitempty"`
-	Text     string          `json:"text" nbt:"text"`
+
+	// Content, only the fields of the component's content type are used.
+	Text      string          `json:"text" nbt:"text"`
+	Translate string          `json:"translate,omitempty" nbt:"translate,omitempty"`
+	Fallback  string          `json:"fallback,omitempty" nbt:"fallback,omitempty"`
+	With      []TextComponent `json:"with,omitempty" nbt:"with,omitempty"`
+	Score     *ScoreContent   `json:"score,omitempty" nbt:"score,omitempty"`
+	Selector  string          `json:"selector,omitempty" nbt:"selector,omitempty"`
+	Separator *TextComponent  `json:"separator,omitempty" nbt:"separator,omitempty"`
+	Keybind   string          `json:"keybind,omitempty" nbt:"keybind,omitempty"`
+	NBT       string          `json:"nbt,omitempty" nbt:"nbt,omitempty"`
+	Interpret bool            `json:"interpret,omitempty" nbt:"interpret,omitempty"`
+	Block     string          `json:"block,omitempty" nbt:"block,omitempty"`
+	Entity    string          `json:"entity,omitempty" nbt:"entity,omitempty"`
+	Storage   string          `json:"storage,omitempty" nbt:"storage,omitempty"`
+	Source    string          `json:"source,omitempty" nbt:"source,omitempty"`
 
 	// Styling
-	Color         string         `json:"color,omitempty" nbt:"color,omitempty"`
-	Bold          bool           `json:"bold,omitempty" nbt:"bold,omitempty"`
-	Italic        bool           `json:"italic,omitempty" nbt:"italic,omitempty"`
-	Underlined    bool           `json:"underlined,omitempty" nbt:"underlined,omitempty"`
-	Strikethrough bool           `json:"strikethrough,omitempty" nbt:"strikethrough,omitempty"`
-	Obfuscated    bool           `json:"obfuscated,omitempty" nbt:"obfuscated,omitempty"`
-	Font          string         `json:"font,omitempty" nbt:"font,omitempty"`
-	Insertion     string         `json:"insertion,omitempty" nbt:"insertion,omitempty"`
-	ClickEvent    ClickEventData `json:"click_event,omitempty" nbt:"click_event,omitempty"`
-	HoverEvent    HoverEventData `json:"hover_event,omitempty" nbt:"hover_event,omitempty"`
+	Color         string          `json:"color,omitempty" nbt:"color,omitempty"`
+	Bold          bool            `json:"bold,omitempty" nbt:"bold,omitempty"`
+	Italic        bool            `json:"italic,omitempty" nbt:"italic,omitempty"`
+	Underlined    bool            `json:"underlined,omitempty" nbt:"underlined,omitempty"`
+	Strikethrough bool            `json:"strikethrough,omitempty" nbt:"strikethrough,omitempty"`
+	Obfuscated    bool            `json:"obfuscated,omitempty" nbt:"obfuscated,omitempty"`
+	Font          string          `json:"font,omitempty" nbt:"font,omitempty"`
+	Insertion     string          `json:"insertion,omitempty" nbt:"insertion,omitempty"`
+	ClickEvent    *ClickEventData `json:"clickEvent,omitempty" nbt:"clickEvent,omitempty"`
+	HoverEvent    *HoverEventData `json:"hoverEvent,omitempty" nbt:"hoverEvent,omitempty"`
+}
+
+// ContentType returns the content type of the component. When Type is not set
+// it is inferred from the populated fields in the same order vanilla checks them.
+func (comp TextComponent) ContentType() string {
+	switch {
+	case comp.Type != "":
+		return comp.Type
+	case comp.Text != "":
+		return TextType
+	case comp.Translate != "":
+		return TranslateType
+	case comp.Score != nil:
+		return ScoreType
+	case comp.Selector != "":
+		return SelectorType
+	case comp.Keybind != "":
+		return KeybindType
+	case comp.NBT != "":
+		return NBTType
+	default:
+		return TextType
+	}
 }
 
 func ParseFormatted(format string, args ...interface{}) TextComponent {
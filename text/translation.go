@@ -0,0 +1,140 @@
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Translations holds the language files the server translates its own
+// messages with, keyed by locale (en_us, de_de, ...). They hold the keys the
+// server defines and overrides of vanilla keys; the client translates the
+// other vanilla keys itself.
+type Translations struct {
+	mutex     sync.RWMutex
+	languages map[string]map[string]string
+}
+
+// NewTranslations creates an empty registry.
+func NewTranslations() *Translations {
+	return &Translations{languages: map[string]map[string]string{}}
+}
+
+// Translatable creates a translate component with the given arguments.
+func Translatable(key string, args ...TextComponent) TextComponent {
+	return TextComponent{Type: TranslateType, Translate: key, With: args}
+}
+
+// Load adds the keys of a language JSON file to a locale, overriding existing
+// keys.
+func (t *Translations) Load(locale string, data []byte) error {
+	lang := map[string]string{}
+	if err := json.Unmarshal(data, &lang); err != nil {
+		return fmt.Errorf("error loading language %s: %w", locale, err)
+	}
+
+	locale = normalizeLocale(locale)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	merged := map[string]string{}
+	for k, v := range t.languages[locale] {
+		merged[k] = v
+	}
+	for k, v := range lang {
+		merged[k] = v
+	}
+	t.languages[locale] = merged
+	return nil
+}
+
+// LoadDir loads every <locale>.json file in a directory.
+func (t *Translations) LoadDir(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		locale := strings.TrimSuffix(filepath.Base(file), ".json")
+		if err := t.Load(locale, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Lookup finds the format string the server translates a key with in a
+// locale: the one loaded for that locale or, for keys vanilla doesn't have,
+// the en_us one. Vanilla keys without an override in the locale aren't found,
+// as every client has them.
+func (t *Translations) Lookup(locale, key string) (string, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	if format, ok := t.languages[normalizeLocale(locale)][key]; ok {
+		return format, true
+	}
+	if _, vanilla := enUS[key]; vanilla {
+		return "", false
+	}
+	format, ok := t.languages[DefaultLocale][key]
+	return format, ok
+}
+
+// Localize resolves the translate components the server translates, as found
+// by Lookup, into literal text in the given locale. The others are left for
+// the client to translate.
+func (t *Translations) Localize(comp TextComponent, locale string) TextComponent {
+	if len(comp.Children) > 0 {
+		children := make([]TextComponent, len(comp.Children))
+		for i, child := range comp.Children {
+			children[i] = t.Localize(child, locale)
+		}
+		comp.Children = children
+	}
+
+	if comp.ContentType() != TranslateType {
+		return comp
+	}
+
+	format, ok := t.Lookup(locale, comp.Translate)
+	if !ok {
+		return comp
+	}
+
+	args := make([]TextComponent, len(comp.With))
+	for i, arg := range comp.With {
+		args[i] = t.Localize(arg, locale)
+	}
+
+	// the translated parts become children so each argument keeps its style
+	parts := []TextComponent{}
+	for _, seg := range splitTranslation(format) {
+		if seg.arg < 0 {
+			parts = append(parts, TextComponent{Text: seg.literal})
+		} else if seg.arg < len(args) {
+			parts = append(parts, args[seg.arg])
+		}
+	}
+
+	comp.Type = TextType
+	comp.Text = ""
+	comp.Translate = ""
+	comp.Fallback = ""
+	comp.With = nil
+	comp.Children = append(parts, comp.Children...)
+	return comp
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(locale, "-", "_"))
+}
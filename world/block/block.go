@@ -0,0 +1,152 @@
+package block
+
+import (
+	"strings"
+)
+
+// StateID is a block state's index in the global palette.
+type StateID uint32
+
+// Air is the global ID of minecraft:air, which vanilla always registers first.
+const Air StateID = 0
+
+// Property is a block state property and its possible values, in report order.
+type Property struct {
+	Name   string
+	Values []string
+}
+
+func (p Property) index(value string) int {
+	for i, v := range p.Values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Block is a block type with every state it can be in.
+type Block struct {
+	ID         int32  // in the block registry, ordered like the states
+	Name       string // e.g. minecraft:oak_stairs
+	Type       string // block class from the report definition, e.g. minecraft:stair
+	Properties []Property
+	Default    StateID
+
+	// states are ordered by ID, with the last property changing fastest.
+	states []*State
+}
+
+// States returns every state of the block ordered by ID.
+func (b *Block) States() []*State {
+	return b.states
+}
+
+// MinStateID returns the lowest global ID of the block's states.
+func (b *Block) MinStateID() StateID {
+	return b.states[0].ID
+}
+
+// MaxStateID returns the highest global ID of the block's states.
+func (b *Block) MaxStateID() StateID {
+	return b.states[len(b.states)-1].ID
+}
+
+// DefaultState returns the state the block is placed in without context.
+func (b *Block) DefaultState() *State {
+	return b.states[b.Default-b.MinStateID()]
+}
+
+// Property returns a property definition by name.
+func (b *Block) Property(name string) (Property, bool) {
+	for _, p := range b.Properties {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Property{}, false
+}
+
+// state finds the state with the given value index for every property.
+func (b *Block) state(indices []int) *State {
+	offset := 0
+	for i, p := range b.Properties {
+		offset = offset*len(p.Values) + indices[i]
+	}
+	return b.states[offset]
+}
+
+// State is one combination of property values of a block.
+type State struct {
+	ID    StateID
+	Block *Block
+
+	// values holds the value index of each of Block.Properties
+	values []int
+}
+
+// Get returns the value of a property, or "" if the block doesn't have it.
+func (s *State) Get(name string) string {
+	for i, p := range s.Block.Properties {
+		if p.Name == name {
+			return p.Values[s.values[i]]
+		}
+	}
+	return ""
+}
+
+// Properties returns the state's property values by name.
+func (s *State) Properties() map[string]string {
+	props := make(map[string]string, len(s.values))
+	for i, p := range s.Block.Properties {
+		props[p.Name] = p.Values[s.values[i]]
+	}
+	return props
+}
+
+// With returns the state of the same block with one property changed. The
+// state itself is returned if the block has no such property or value.
+func (s *State) With(name, value string) *State {
+	indices := make([]int, len(s.values))
+	copy(indices, s.values)
+
+	for i, p := range s.Block.Properties {
+		if p.Name != name {
+			continue
+		}
+		index := p.index(value)
+		if index < 0 {
+			return s
+		}
+		indices[i] = index
+		return s.Block.state(indices)
+	}
+	return s
+}
+
+// IsDefault reports whether this is the block's default state.
+func (s *State) IsDefault() bool {
+	return s.ID == s.Block.Default
+}
+
+// String formats the state the way vanilla commands do, e.g.
+// minecraft:oak_stairs[facing=north,half=bottom,shape=straight,waterlogged=false]
+func (s *State) String() string {
+	if len(s.values) == 0 {
+		return s.Block.Name
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s.Block.Name)
+	sb.WriteByte('[')
+	for i, p := range s.Block.Properties {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(p.Name)
+		sb.WriteByte('=')
+		sb.WriteString(p.Values[s.values[i]])
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
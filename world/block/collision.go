@@ -0,0 +1,366 @@
+package block
+
+import (
+	"math"
+	"strconv"
+)
+
+// Box is an axis aligned box. In collision shapes its coordinates are
+// relative to the block's lowest corner, a full block spanning 0 to 1.
+type Box struct {
+	MinX, MinY, MinZ float64
+	MaxX, MaxY, MaxZ float64
+}
+
+// Offset returns the box moved by a vector.
+func (b Box) Offset(x, y, z float64) Box {
+	return Box{b.MinX + x, b.MinY + y, b.MinZ + z, b.MaxX + x, b.MaxY + y, b.MaxZ + z}
+}
+
+// Intersects reports whether two boxes overlap. Touching boxes don't.
+func (b Box) Intersects(o Box) bool {
+	return b.MinX < o.MaxX && b.MaxX > o.MinX &&
+		b.MinY < o.MaxY && b.MaxY > o.MinY &&
+		b.MinZ < o.MaxZ && b.MaxZ > o.MinZ
+}
+
+// Expand returns the box stretched by a vector, covering everything it passes
+// moving along it.
+func (b Box) Expand(x, y, z float64) Box {
+	if x < 0 {
+		b.MinX += x
+	} else {
+		b.MaxX += x
+	}
+	if y < 0 {
+		b.MinY += y
+	} else {
+		b.MaxY += y
+	}
+	if z < 0 {
+		b.MinZ += z
+	} else {
+		b.MaxZ += z
+	}
+	return b
+}
+
+// sweepEpsilon is how far boxes may already overlap on the axis they move
+// along and still block each other, for rounding errors.
+const sweepEpsilon = 1e-7
+
+// clip limits a move of the box along one axis so it stops at an obstacle.
+// min and max are the bounds of the box on that axis, and oMin and oMax the
+// obstacle's.
+func clip(min, max, oMin, oMax, d float64) float64 {
+	if d > 0 && oMin >= max-sweepEpsilon {
+		return math.Min(d, oMin-max)
+	}
+	if d < 0 && oMax <= min+sweepEpsilon {
+		return math.Max(d, oMax-min)
+	}
+	return d
+}
+
+// ClipX limits a move of the box along the x axis so it doesn't enter an
+// obstacle.
+func (b Box) ClipX(o Box, dx float64) float64 {
+	if o.MaxY <= b.MinY || o.MinY >= b.MaxY || o.MaxZ <= b.MinZ || o.MinZ >= b.MaxZ {
+		return dx
+	}
+	return clip(b.MinX, b.MaxX, o.MinX, o.MaxX, dx)
+}
+
+// ClipY limits a move of the box along the y axis so it doesn't enter an
+// obstacle.
+func (b Box) ClipY(o Box, dy float64) float64 {
+	if o.MaxX <= b.MinX || o.MinX >= b.MaxX || o.MaxZ <= b.MinZ || o.MinZ >= b.MaxZ {
+		return dy
+	}
+	return clip(b.MinY, b.MaxY, o.MinY, o.MaxY, dy)
+}
+
+// ClipZ limits a move of the box along the z axis so it doesn't enter an
+// obstacle.
+func (b Box) ClipZ(o Box, dz float64) float64 {
+	if o.MaxX <= b.MinX || o.MinX >= b.MaxX || o.MaxY <= b.MinY || o.MinY >= b.MaxY {
+		return dz
+	}
+	return clip(b.MinZ, b.MaxZ, o.MinZ, o.MaxZ, dz)
+}
+
+// Sweep moves a box by a vector, one axis at a time like vanilla, stopping on
+// each axis at the first obstacle in the way. It returns how far the box
+// could move.
+func Sweep(box Box, obstacles []Box, dx, dy, dz float64) (float64, float64, float64) {
+	if dy != 0 {
+		for _, o := range obstacles {
+			dy = box.ClipY(o, dy)
+		}
+		box = box.Offset(0, dy, 0)
+	}
+
+	// the longer horizontal move goes last
+	zFirst := math.Abs(dx) < math.Abs(dz)
+	if zFirst && dz != 0 {
+		for _, o := range obstacles {
+			dz = box.ClipZ(o, dz)
+		}
+		box = box.Offset(0, 0, dz)
+	}
+	if dx != 0 {
+		for _, o := range obstacles {
+			dx = box.ClipX(o, dx)
+		}
+		box = box.Offset(dx, 0, 0)
+	}
+	if !zFirst && dz != 0 {
+		for _, o := range obstacles {
+			dz = box.ClipZ(o, dz)
+		}
+	}
+	return dx, dy, dz
+}
+
+// Block shapes don't come with the reports either, so the common ones are
+// built from the block types and states the way vanilla defines them. Other
+// blocks are full cubes.
+//
+// https://minecraft.wiki/w/Solid_block
+
+// px is a box in sixteenths of a block, like vanilla's Block.box.
+func px(minX, minY, minZ, maxX, maxY, maxZ float64) Box {
+	return Box{minX / 16, minY / 16, minZ / 16, maxX / 16, maxY / 16, maxZ / 16}
+}
+
+var fullCube = []Box{px(0, 0, 0, 16, 16, 16)}
+
+// noCollisionTypeNames are the block types, without namespace, entities pass
+// through.
+var noCollisionTypeNames = []string{
+	"air", "attached_stem", "banner", "bubble_column", "bush", "button", "cave_vines",
+	"cave_vines_plant", "coral", "coral_fan", "coral_wall_fan", "crop", "dead_bush",
+	"double_plant", "end_gateway", "end_portal", "fire", "flower", "fungus", "glow_lichen",
+	"hanging_roots", "hanging_sign", "kelp", "kelp_plant", "lever", "light",
+	"liquid", "mushroom", "nether_portal", "nether_sprouts", "nether_wart", "pink_petals",
+	"pitcher_crop", "powder_snow", "pressure_plate", "rail", "redstone_torch",
+	"redstone_wall_torch", "redstone_wire", "roots", "sapling", "sculk_vein", "seagrass",
+	"sign", "soul_fire", "spore_blossom", "standing_sign", "stem", "structure_void",
+	"sugar_cane", "sweet_berry_bush", "tall_grass", "tall_seagrass", "torch",
+	"torchflower_crop", "tripwire", "tripwire_hook", "twisting_vines", "twisting_vines_plant",
+	"vine", "wall_banner", "wall_hanging_sign", "wall_sign", "wall_torch", "web",
+	"weeping_vines", "weeping_vines_plant", "weighted_pressure_plate", "small_dripleaf",
+	"big_dripleaf_stem", "mangrove_propagule", "bamboo_sapling", "frogspawn",
+	"powered_rail", "detector_rail",
+}
+
+var noCollisionTypes = func() map[string]bool {
+	types := make(map[string]bool, len(noCollisionTypeNames))
+	for _, t := range noCollisionTypeNames {
+		types["minecraft:"+t] = true
+	}
+	return types
+}()
+
+// blockShapes are full width blocks that aren't a full block high, by name.
+var blockShapes = map[string][]Box{
+	"minecraft:soul_sand":               {px(0, 0, 0, 16, 14, 16)},
+	"minecraft:mud":                     {px(0, 0, 0, 16, 14, 16)},
+	"minecraft:farmland":                {px(0, 0, 0, 16, 15, 16)},
+	"minecraft:dirt_path":               {px(0, 0, 0, 16, 15, 16)},
+	"minecraft:end_portal_frame":        {px(0, 0, 0, 16, 13, 16)},
+	"minecraft:enchanting_table":        {px(0, 0, 0, 16, 12, 16)},
+	"minecraft:daylight_detector":       {px(0, 0, 0, 16, 6, 16)},
+	"minecraft:stonecutter":             {px(0, 0, 0, 16, 9, 16)},
+	"minecraft:cactus":                  {px(1, 0, 1, 15, 15, 15)},
+	"minecraft:chest":                   {px(1, 0, 1, 15, 14, 15)},
+	"minecraft:trapped_chest":           {px(1, 0, 1, 15, 14, 15)},
+	"minecraft:ender_chest":             {px(1, 0, 1, 15, 14, 15)},
+	"minecraft:honey_block":             {px(1, 0, 1, 15, 15, 15)},
+	"minecraft:flower_pot":              {px(5, 0, 5, 11, 6, 11)},
+	"minecraft:cake":                    {px(1, 0, 1, 15, 8, 15)},
+	"minecraft:lily_pad":                {px(1, 0, 1, 15, 1.5, 15)},
+	"minecraft:scaffolding":             {px(0, 14, 0, 16, 16, 16)},
+	"minecraft:composter":               {px(0, 0, 0, 16, 2, 16)},
+	"minecraft:bell":                    {px(4, 4, 4, 12, 16, 12)},
+	"minecraft:conduit":                 {px(5, 5, 5, 11, 11, 11)},
+	"minecraft:lectern":                 {px(0, 0, 0, 16, 2, 16), px(4, 2, 4, 12, 14, 12)},
+	"minecraft:brewing_stand":           {px(7, 0, 7, 9, 14, 9), px(1, 0, 1, 15, 2, 15)},
+	"minecraft:hopper":                  {px(0, 10, 0, 16, 16, 16), px(4, 4, 4, 12, 10, 12)},
+	"minecraft:sculk_sensor":            {px(0, 0, 0, 16, 8, 16)},
+	"minecraft:sculk_shrieker":          {px(0, 0, 0, 16, 8, 16)},
+	"minecraft:calibrated_sculk_sensor": {px(0, 0, 0, 16, 8, 16)},
+}
+
+// horizontal are the sides blocks like fences connect to, with the box of
+// their arm towards it in sixteenths of a block from the center line.
+var horizontal = []struct {
+	name string
+	arm  func(half, height float64) Box
+}{
+	{"north", func(h, y float64) Box { return px(8-h, 0, 0, 8+h, y, 8) }},
+	{"south", func(h, y float64) Box { return px(8-h, 0, 8, 8+h, y, 16) }},
+	{"west", func(h, y float64) Box { return px(0, 0, 8-h, 8, y, 8+h) }},
+	{"east", func(h, y float64) Box { return px(8, 0, 8-h, 16, y, 8+h) }},
+}
+
+// connected builds the shape of a post with arms on the sides it connects to.
+func connected(s *State, post, arm, height float64) []Box {
+	shape := []Box{px(8-post, 0, 8-post, 8+post, height, 8+post)}
+	for _, side := range horizontal {
+		if v := s.Get(side.name); v != "" && v != "false" && v != "none" {
+			shape = append(shape, side.arm(arm, height))
+		}
+	}
+	return shape
+}
+
+// thin are boxes of a block three sixteenths thick against a side.
+var thin = map[string]Box{
+	"north": px(0, 0, 13, 16, 16, 16),
+	"south": px(0, 0, 0, 16, 16, 3),
+	"west":  px(13, 0, 0, 16, 16, 16),
+	"east":  px(0, 0, 0, 3, 16, 16),
+}
+
+// doorSide returns the side the box of a door is against. Open doors swing
+// towards their hinge.
+func doorSide(s *State) string {
+	facing := s.Get("facing")
+	if s.Get("open") != "true" {
+		return facing
+	}
+	right := s.Get("hinge") == "right"
+	sides := map[string][2]string{
+		"north": {"east", "west"},
+		"south": {"west", "east"},
+		"west":  {"north", "south"},
+		"east":  {"south", "north"},
+	}[facing]
+	if right {
+		return sides[1]
+	}
+	return sides[0]
+}
+
+// stateShape returns the collision boxes of a state.
+func stateShape(s *State) []Box {
+	if shape, ok := blockShapes[s.Block.Name]; ok {
+		return shape
+	}
+	if noCollisionTypes[s.Block.Type] {
+		return nil
+	}
+
+	switch s.Block.Type {
+	case "minecraft:slab":
+		switch s.Get("type") {
+		case "bottom":
+			return []Box{px(0, 0, 0, 16, 8, 16)}
+		case "top":
+			return []Box{px(0, 8, 0, 16, 16, 16)}
+		}
+	case "minecraft:stair":
+		base, step := px(0, 0, 0, 16, 8, 16), 8.0
+		if s.Get("half") == "top" {
+			base, step = px(0, 8, 0, 16, 16, 16), 0
+		}
+		steps := map[string]Box{
+			"north": px(0, step, 0, 16, step+8, 8),
+			"south": px(0, step, 8, 16, step+8, 16),
+			"west":  px(0, step, 0, 8, step+8, 16),
+			"east":  px(8, step, 0, 16, step+8, 16),
+		}
+		return []Box{base, steps[s.Get("facing")]}
+	case "minecraft:snow_layer":
+		layers, _ := strconv.Atoi(s.Get("layers"))
+		if layers <= 1 {
+			return nil
+		}
+		return []Box{px(0, 0, 0, 16, float64(layers-1)*2, 16)}
+	case "minecraft:carpet", "minecraft:wool_carpet":
+		return []Box{px(0, 0, 0, 16, 1, 16)}
+	case "minecraft:bed":
+		return []Box{px(0, 0, 0, 16, 9, 16)}
+	case "minecraft:fence":
+		return connected(s, 2, 2, 24)
+	case "minecraft:wall":
+		return connected(s, 4, 3, 24)
+	case "minecraft:iron_bars", "minecraft:pane", "minecraft:stained_glass_pane":
+		return connected(s, 1, 1, 16)
+	case "minecraft:fence_gate":
+		if s.Get("open") == "true" {
+			return nil
+		}
+		if f := s.Get("facing"); f == "north" || f == "south" {
+			return []Box{px(0, 0, 6, 16, 24, 10)}
+		}
+		return []Box{px(6, 0, 0, 10, 24, 16)}
+	case "minecraft:door":
+		return []Box{thin[doorSide(s)]}
+	case "minecraft:ladder":
+		return []Box{thin[s.Get("facing")]}
+	case "minecraft:trapdoor":
+		if s.Get("open") == "true" {
+			return []Box{thin[s.Get("facing")]}
+		}
+		if s.Get("half") == "top" {
+			return []Box{px(0, 13, 0, 16, 16, 16)}
+		}
+		return []Box{px(0, 0, 0, 16, 3, 16)}
+	}
+	return fullCube
+}
+
+// CollisionShape returns the boxes entities collide with in a state, nil for
+// blocks they pass through.
+func (r *Registry) CollisionShape(id StateID) []Box {
+	if int(id) >= len(r.shapes) {
+		return fullCube
+	}
+	return r.shapes[id]
+}
+
+// HasCollision reports whether entities collide with a state.
+func (r *Registry) HasCollision(id StateID) bool {
+	return len(r.CollisionShape(id)) > 0
+}
+
+// IsFullCube reports whether the collision shape of a state fills its block.
+func (r *Registry) IsFullCube(id StateID) bool {
+	shape := r.CollisionShape(id)
+	return len(shape) == 1 && shape[0] == fullCube[0]
+}
+
+// afloatTypes are the block types entities can stay up in without standing on
+// anything: fluids, climbable blocks and blocks that slow falling.
+var afloatTypes = map[string]bool{
+	"minecraft:liquid":               true,
+	"minecraft:bubble_column":        true,
+	"minecraft:kelp":                 true,
+	"minecraft:kelp_plant":           true,
+	"minecraft:seagrass":             true,
+	"minecraft:tall_seagrass":        true,
+	"minecraft:ladder":               true,
+	"minecraft:vine":                 true,
+	"minecraft:scaffolding":          true,
+	"minecraft:web":                  true,
+	"minecraft:powder_snow":          true,
+	"minecraft:sweet_berry_bush":     true,
+	"minecraft:twisting_vines":       true,
+	"minecraft:twisting_vines_plant": true,
+	"minecraft:weeping_vines":        true,
+	"minecraft:weeping_vines_plant":  true,
+	"minecraft:cave_vines":           true,
+	"minecraft:cave_vines_plant":     true,
+}
+
+// KeepsAfloat reports whether entities in a state can stay up without
+// standing on anything, like in water, on ladders or in cobwebs.
+func (r *Registry) KeepsAfloat(id StateID) bool {
+	s, ok := r.State(id)
+	if !ok {
+		return false
+	}
+	return afloatTypes[s.Block.Type] || s.Get("waterlogged") == "true"
+}
@@ -0,0 +1,116 @@
+package block
+
+import "math"
+
+// Direction is one of the six directions blocks face, in the order of the face
+// numbers of the protocol.
+type Direction int
+
+const (
+	Down Direction = iota
+	Up
+	North
+	South
+	West
+	East
+)
+
+var directionNames = [...]string{"down", "up", "north", "south", "west", "east"}
+
+var directionOffsets = [...][3]int{
+	{0, -1, 0},
+	{0, 1, 0},
+	{0, 0, -1},
+	{0, 0, 1},
+	{-1, 0, 0},
+	{1, 0, 0},
+}
+
+// Horizontal are the horizontal directions, clockwise from north.
+var Horizontal = [4]Direction{North, East, South, West}
+
+// DirectionNamed returns the direction a facing property value names.
+func DirectionNamed(name string) (Direction, bool) {
+	for d, n := range directionNames {
+		if n == name {
+			return Direction(d), true
+		}
+	}
+	return 0, false
+}
+
+// String returns the name of the direction, as facing properties hold it.
+func (d Direction) String() string {
+	return directionNames[d]
+}
+
+// Valid reports whether d is one of the six directions.
+func (d Direction) Valid() bool {
+	return d >= Down && d <= East
+}
+
+// Offset returns the step to the next block in the direction.
+func (d Direction) Offset() (x, y, z int) {
+	o := directionOffsets[d]
+	return o[0], o[1], o[2]
+}
+
+// Opposite returns the direction pointing the other way.
+func (d Direction) Opposite() Direction {
+	return d ^ 1
+}
+
+// IsHorizontal reports whether the direction is north, south, west or east.
+func (d Direction) IsHorizontal() bool {
+	return d >= North
+}
+
+// Axis returns the axis the direction is along, as axis properties name it.
+func (d Direction) Axis() string {
+	switch d {
+	case Down, Up:
+		return "y"
+	case North, South:
+		return "z"
+	}
+	return "x"
+}
+
+// Clockwise returns the horizontal direction a quarter turn clockwise, seen
+// from above. Vertical directions stay as they are.
+func (d Direction) Clockwise() Direction {
+	switch d {
+	case North:
+		return East
+	case East:
+		return South
+	case South:
+		return West
+	case West:
+		return North
+	}
+	return d
+}
+
+// CounterClockwise returns the horizontal direction a quarter turn counter
+// clockwise, seen from above. Vertical directions stay as they are.
+func (d Direction) CounterClockwise() Direction {
+	return d.Clockwise().Opposite()
+}
+
+// Facing returns the horizontal direction a yaw faces, 0 being south.
+func Facing(yaw float32) Direction {
+	quarter := int(math.Floor(float64(yaw)/90+0.5)) & 3
+	return [4]Direction{South, West, North, East}[quarter]
+}
+
+// Looking returns the direction closest to where a yaw and a pitch look.
+func Looking(yaw, pitch float32) Direction {
+	switch {
+	case pitch > 45:
+		return Down
+	case pitch < -45:
+		return Up
+	}
+	return Facing(yaw)
+}
@@ -0,0 +1,35 @@
+package block
+
+// entityTypes are the types of block entities, in the order of their IDs in
+// 1.21.
+var entityTypes = []string{
+	"minecraft:furnace", "minecraft:chest", "minecraft:trapped_chest",
+	"minecraft:ender_chest", "minecraft:jukebox", "minecraft:dispenser",
+	"minecraft:dropper", "minecraft:sign", "minecraft:hanging_sign",
+	"minecraft:mob_spawner", "minecraft:piston", "minecraft:brewing_stand",
+	"minecraft:enchanting_table", "minecraft:end_portal", "minecraft:beacon",
+	"minecraft:skull", "minecraft:daylight_detector", "minecraft:hopper",
+	"minecraft:comparator", "minecraft:banner", "minecraft:structure_block",
+	"minecraft:end_gateway", "minecraft:command_block", "minecraft:shulker_box",
+	"minecraft:bed", "minecraft:conduit", "minecraft:barrel",
+	"minecraft:smoker", "minecraft:blast_furnace", "minecraft:lectern",
+	"minecraft:bell", "minecraft:jigsaw", "minecraft:campfire",
+	"minecraft:beehive", "minecraft:sculk_sensor", "minecraft:calibrated_sculk_sensor",
+	"minecraft:sculk_catalyst", "minecraft:sculk_shrieker", "minecraft:chiseled_bookshelf",
+	"minecraft:brushable_block", "minecraft:decorated_pot", "minecraft:crafter",
+	"minecraft:trial_spawner", "minecraft:vault",
+}
+
+var entityTypeIDs = func() map[string]int32 {
+	ids := make(map[string]int32, len(entityTypes))
+	for i, name := range entityTypes {
+		ids[name] = int32(i)
+	}
+	return ids
+}()
+
+// EntityTypeID returns the ID clients know a type of block entity by.
+func EntityTypeID(name string) (int32, bool) {
+	id, ok := entityTypeIDs[name]
+	return id, ok
+}
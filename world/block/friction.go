@@ -0,0 +1,26 @@
+package block
+
+// slipperiness is how much of their speed entities sliding on a block keep,
+// for the blocks that differ from most.
+var slipperiness = map[string]float64{
+	"minecraft:ice":         0.98,
+	"minecraft:packed_ice":  0.98,
+	"minecraft:frosted_ice": 0.98,
+	"minecraft:blue_ice":    0.989,
+	"minecraft:slime_block": 0.8,
+}
+
+// DefaultSlipperiness is the slipperiness of most blocks.
+const DefaultSlipperiness = 0.6
+
+// Slipperiness returns how slippery a state is to entities moving on it.
+func (r *Registry) Slipperiness(id StateID) float64 {
+	s, ok := r.State(id)
+	if !ok {
+		return DefaultSlipperiness
+	}
+	if v, ok := slipperiness[s.Block.Name]; ok {
+		return v
+	}
+	return DefaultSlipperiness
+}
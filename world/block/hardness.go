@@ -0,0 +1,125 @@
+package block
+
+import (
+	_ "embed"
+	"encoding/json"
+	"math"
+)
+
+// Kinds of tools blocks are made to be mined with
+const (
+	Pickaxe = "pickaxe"
+	Axe     = "axe"
+	Shovel  = "shovel"
+	Hoe     = "hoe"
+)
+
+// Tiers of tools, the lowest of which harvest anything is needed by some
+// blocks
+const (
+	TierWood = iota
+	TierStone
+	TierIron
+	TierDiamond
+	TierNetherite
+	// TierGold mines as fast as it sounds, but only harvests what wood does.
+	TierGold = TierWood
+)
+
+// Tool is what a block is broken with, as far as breaking it goes.
+type Tool struct {
+	// Kind is Pickaxe, Axe, Shovel or Hoe, empty for anything else.
+	Kind string
+	Tier int
+	// Speed is how many times faster the tool breaks the blocks it is made
+	// for than hands.
+	Speed float64
+}
+
+// Hand is breaking blocks without a tool.
+var Hand = Tool{Tier: -1, Speed: 1}
+
+// mining is how a block is mined.
+type mining struct {
+	Hardness float64 `json:"hardness"` // negative for unbreakable blocks
+	Tool     string  `json:"tool"`
+	// Tier is the lowest tier of the tool harvesting the block, -1 when hands
+	// do too.
+	Tier int `json:"tier"`
+}
+
+// Unbreakable is the hardness of blocks that can't be broken.
+const Unbreakable = -1
+
+// How every vanilla 1.21 block is mined, by block type. Tools and tiers are
+// those of the mineable and needs_*_tool block tags.
+//
+//go:embed mining.json
+var miningData []byte
+
+var minings = func() map[string]mining {
+	m := map[string]mining{}
+	if err := json.Unmarshal(miningData, &m); err != nil {
+		panic("block: invalid bundled mining data: " + err.Error())
+	}
+	return m
+}()
+
+// defaultMining is how blocks that aren't in vanilla are mined.
+var defaultMining = mining{Hardness: 1, Tier: -1}
+
+// miningOf returns how a block is mined.
+func miningOf(name string) mining {
+	if m, ok := minings[name]; ok {
+		return m
+	}
+	return defaultMining
+}
+
+// Hardness returns how long a state takes to break, Unbreakable for states
+// that can't be.
+func (r *Registry) Hardness(id StateID) float64 {
+	s, ok := r.State(id)
+	if !ok {
+		return defaultMining.Hardness
+	}
+	return miningOf(s.Block.Name).Hardness
+}
+
+// CanHarvest reports whether breaking a state with a tool drops anything.
+func (r *Registry) CanHarvest(id StateID, tool Tool) bool {
+	s, ok := r.State(id)
+	if !ok {
+		return true
+	}
+	m := miningOf(s.Block.Name)
+	return m.Tier < 0 || tool.Kind == m.Tool && tool.Tier >= m.Tier
+}
+
+// BreakProgress returns how much of a state is broken every tick with a tool,
+// 1 being all of it, as vanilla computes it. Players off the ground or under
+// water break blocks five times slower.
+func (r *Registry) BreakProgress(id StateID, tool Tool, onGround, underWater bool) float64 {
+	hardness := r.Hardness(id)
+	if hardness < 0 {
+		return 0
+	}
+	if hardness == 0 {
+		return 1
+	}
+
+	speed := 1.0
+	if s, ok := r.State(id); ok && tool.Kind != "" && tool.Kind == miningOf(s.Block.Name).Tool {
+		speed = math.Max(tool.Speed, 1)
+	}
+	if !onGround {
+		speed /= 5
+	}
+	if underWater {
+		speed /= 5
+	}
+	if r.CanHarvest(id, tool) {
+		return speed / hardness / 30
+	}
+	return speed / hardness / 100
+}
@@ -0,0 +1,203 @@
+package block
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MaxLight is the brightest light level.
+const MaxLight = 15
+
+// Block reports don't include light properties, so they are derived from the
+// block names and types the way vanilla assigns them.
+
+// emission is the light given off by blocks that always glow.
+var emission = map[string]uint8{
+	"minecraft:beacon":                  15,
+	"minecraft:conduit":                 15,
+	"minecraft:end_gateway":             15,
+	"minecraft:end_portal":              15,
+	"minecraft:fire":                    15,
+	"minecraft:glowstone":               15,
+	"minecraft:jack_o_lantern":          15,
+	"minecraft:lantern":                 15,
+	"minecraft:lava":                    15,
+	"minecraft:lava_cauldron":           15,
+	"minecraft:ochre_froglight":         15,
+	"minecraft:pearlescent_froglight":   15,
+	"minecraft:verdant_froglight":       15,
+	"minecraft:sea_lantern":             15,
+	"minecraft:shroomlight":             15,
+	"minecraft:end_rod":                 14,
+	"minecraft:torch":                   14,
+	"minecraft:wall_torch":              14,
+	"minecraft:nether_portal":           11,
+	"minecraft:crying_obsidian":         10,
+	"minecraft:soul_fire":               10,
+	"minecraft:soul_lantern":            10,
+	"minecraft:soul_torch":              10,
+	"minecraft:soul_wall_torch":         10,
+	"minecraft:enchanting_table":        7,
+	"minecraft:ender_chest":             7,
+	"minecraft:glow_lichen":             7,
+	"minecraft:sculk_catalyst":          6,
+	"minecraft:amethyst_cluster":        5,
+	"minecraft:large_amethyst_bud":      4,
+	"minecraft:magma_block":             3,
+	"minecraft:medium_amethyst_bud":     2,
+	"minecraft:brewing_stand":           1,
+	"minecraft:brown_mushroom":          1,
+	"minecraft:calibrated_sculk_sensor": 1,
+	"minecraft:dragon_egg":              1,
+	"minecraft:end_portal_frame":        1,
+	"minecraft:sculk_sensor":            1,
+	"minecraft:small_amethyst_bud":      1,
+	"minecraft:trial_spawner":           4,
+	"minecraft:vault":                   6,
+}
+
+// litEmission is the light given off by blocks while their lit property is
+// true.
+var litEmission = map[string]uint8{
+	"minecraft:blast_furnace":               13,
+	"minecraft:campfire":                    15,
+	"minecraft:copper_bulb":                 15,
+	"minecraft:deepslate_redstone_ore":      9,
+	"minecraft:exposed_copper_bulb":         12,
+	"minecraft:furnace":                     13,
+	"minecraft:oxidized_copper_bulb":        4,
+	"minecraft:redstone_lamp":               15,
+	"minecraft:redstone_ore":                9,
+	"minecraft:redstone_torch":              7,
+	"minecraft:redstone_wall_torch":         7,
+	"minecraft:smoker":                      13,
+	"minecraft:soul_campfire":               10,
+	"minecraft:waxed_copper_bulb":           15,
+	"minecraft:waxed_exposed_copper_bulb":   12,
+	"minecraft:waxed_oxidized_copper_bulb":  4,
+	"minecraft:waxed_weathered_copper_bulb": 8,
+	"minecraft:weathered_copper_bulb":       8,
+}
+
+// stateEmission returns the light a state gives off.
+func stateEmission(s *State) uint8 {
+	name := s.Block.Name
+	if level, ok := litEmission[name]; ok {
+		if s.Get("lit") == "true" {
+			return level
+		}
+		return 0
+	}
+
+	switch {
+	case name == "minecraft:light":
+		level, _ := strconv.Atoi(s.Get("level"))
+		return uint8(level)
+	case name == "minecraft:respawn_anchor":
+		charges, _ := strconv.Atoi(s.Get("charges"))
+		return uint8(charges * MaxLight / 4)
+	case name == "minecraft:sea_pickle":
+		if s.Get("waterlogged") != "true" {
+			return 0
+		}
+		pickles, _ := strconv.Atoi(s.Get("pickles"))
+		return uint8(3 + 3*pickles)
+	case name == "minecraft:cave_vines" || name == "minecraft:cave_vines_plant":
+		if s.Get("berries") == "true" {
+			return 14
+		}
+		return 0
+	case strings.HasSuffix(name, "candle") && s.Get("lit") == "true":
+		candles, _ := strconv.Atoi(s.Get("candles"))
+		return uint8(3 * candles)
+	case strings.HasSuffix(name, "candle_cake") && s.Get("lit") == "true":
+		return 3
+	}
+	return emission[name]
+}
+
+// translucentTypes are block types that let light through but dim it by one,
+// like leaves and water.
+var translucentTypes = map[string]bool{
+	"minecraft:bubble_column":            true,
+	"minecraft:cherry_leaves":            true,
+	"minecraft:frosted_ice":              true,
+	"minecraft:half_transparent":         true,
+	"minecraft:honey":                    true,
+	"minecraft:ice":                      true,
+	"minecraft:kelp":                     true,
+	"minecraft:kelp_plant":               true,
+	"minecraft:leaves":                   true,
+	"minecraft:liquid":                   true,
+	"minecraft:mangrove_leaves":          true,
+	"minecraft:seagrass":                 true,
+	"minecraft:slime":                    true,
+	"minecraft:tall_seagrass":            true,
+	"minecraft:untinted_particle_leaves": true,
+	"minecraft:tinted_particle_leaves":   true,
+}
+
+// transparentTypeNames are the block types, without namespace, that don't fill
+// their whole block or are see-through, which light passes freely.
+var transparentTypeNames = []string{
+	"air", "anvil", "azalea", "bamboo", "bamboo_sapling", "banner", "barrier", "bed", "bell",
+	"big_dripleaf", "big_dripleaf_stem", "brewing_stand", "bush", "button", "cactus", "cake",
+	"campfire", "candle", "candle_cake", "carpet", "cauldron", "cave_vines", "cave_vines_plant",
+	"chain", "chest", "chorus_flower", "chorus_plant", "cocoa", "comparator", "conduit", "coral",
+	"coral_fan", "coral_wall_fan", "crop", "daylight_detector", "decorated_pot", "door",
+	"double_plant", "dragon_egg", "end_portal", "end_gateway", "end_rod", "enchantment_table",
+	"ender_chest", "farm", "fence", "fence_gate", "fire", "flower", "flower_pot", "frogspawn",
+	"fungus", "glow_lichen", "grindstone", "hanging_roots", "hanging_sign", "head", "hopper",
+	"iron_bars", "ladder", "lantern", "lectern", "lever", "light", "lightning_rod",
+	"mangrove_propagule", "mushroom", "nether_portal", "nether_sprouts", "nether_wart",
+	"piston_head", "pitcher_crop", "pointed_dripstone", "powder_snow", "pressure_plate",
+	"rail", "redstone_torch", "redstone_wall_torch", "redstone_wire", "repeater", "roots",
+	"sapling", "scaffolding", "sculk_sensor", "sculk_shrieker", "sculk_vein", "sea_pickle",
+	"sign", "skull", "slab", "small_dripleaf", "snow_layer", "spore_blossom", "stained_glass",
+	"stained_glass_pane", "stair", "standing_sign", "stem", "attached_stem", "stonecutter",
+	"sugar_cane", "sweet_berry_bush", "tall_grass", "torch", "torchflower_crop", "transparent",
+	"trapdoor", "tripwire", "tripwire_hook", "turtle_egg", "twisting_vines",
+	"twisting_vines_plant", "vine", "wall", "wall_banner", "wall_hanging_sign", "wall_sign",
+	"wall_skull", "wall_torch", "waterlily", "weeping_vines", "weeping_vines_plant",
+	"weighted_pressure_plate", "web", "dead_bush", "pink_petals", "amethyst_cluster",
+	"beacon", "heavy_core", "pane", "glass", "trial_spawner", "vault", "spawner",
+	"mangrove_roots",
+}
+
+var transparentTypes = func() map[string]bool {
+	types := make(map[string]bool, len(transparentTypeNames))
+	for _, t := range transparentTypeNames {
+		types["minecraft:"+t] = true
+	}
+	return types
+}()
+
+// stateOpacity returns how much light is dimmed passing through a state.
+func stateOpacity(s *State) uint8 {
+	switch {
+	case s.Block.Name == "minecraft:tinted_glass":
+		return MaxLight
+	case s.Get("waterlogged") == "true" || translucentTypes[s.Block.Type]:
+		return 1
+	case transparentTypes[s.Block.Type]:
+		return 0
+	}
+	return MaxLight
+}
+
+// LightEmission returns the light level a state gives off.
+func (r *Registry) LightEmission(id StateID) uint8 {
+	if int(id) >= len(r.emission) {
+		return 0
+	}
+	return r.emission[id]
+}
+
+// LightOpacity returns how many light levels are lost passing through a
+// state, MaxLight for blocks light can't pass at all.
+func (r *Registry) LightOpacity(id StateID) uint8 {
+	if int(id) >= len(r.opacity) {
+		return MaxLight
+	}
+	return r.opacity[id]
+}
@@ -0,0 +1,361 @@
+package block
+
+import "strings"
+
+// replaceableTypes are the block types placed blocks replace, rather than
+// being placed next to.
+var replaceableTypes = map[string]bool{
+	"minecraft:air":            true,
+	"minecraft:liquid":         true,
+	"minecraft:tall_grass":     true,
+	"minecraft:double_plant":   true,
+	"minecraft:dead_bush":      true,
+	"minecraft:vine":           true,
+	"minecraft:glow_lichen":    true,
+	"minecraft:seagrass":       true,
+	"minecraft:tall_seagrass":  true,
+	"minecraft:fire":           true,
+	"minecraft:soul_fire":      true,
+	"minecraft:structure_void": true,
+	"minecraft:light":          true,
+}
+
+// Replaceable reports whether placing a block where a state is replaces it.
+// Snow is replaced only while a single layer thick.
+func (r *Registry) Replaceable(id StateID) bool {
+	s, ok := r.State(id)
+	if !ok {
+		return false
+	}
+	if s.Block.Type == "minecraft:snow_layer" {
+		return s.Get("layers") == "1"
+	}
+	return replaceableTypes[s.Block.Type]
+}
+
+// Getter returns the blocks around a block being placed or updated.
+type Getter interface {
+	Block(x, y, z int) StateID
+}
+
+// Placement is a player placing a block.
+type Placement struct {
+	// X, Y, Z is where the block goes.
+	X, Y, Z int
+	// Face is the face of the clicked block the player placed against.
+	Face Direction
+	// HitX, HitY, HitZ is the point of the face the player clicked, in world
+	// coordinates.
+	HitX, HitY, HitZ float64
+	// ReplacingClicked is whether the block replaces the clicked block rather
+	// than going next to it.
+	ReplacingClicked bool
+	// Yaw and Pitch are where the player looks, in degrees.
+	Yaw, Pitch float32
+	Sneaking   bool
+	// World holds the blocks as they are before the placement.
+	World Getter
+}
+
+// Facing returns the horizontal direction the player faces.
+func (p *Placement) Facing() Direction {
+	return Facing(p.Yaw)
+}
+
+// upperHalf reports whether the player clicked the upper half of where the
+// block goes: the bottom face of the block above it, or high on a side.
+func (p *Placement) upperHalf() bool {
+	return p.Face == Down || p.Face != Up && p.HitY-float64(p.Y) > 0.5
+}
+
+// Change sets a block to a state.
+type Change struct {
+	X, Y, Z int
+	State   StateID
+}
+
+// PlaceRule returns the blocks changed by placing a state, which is the default
+// state of the block held, or nil when it can't be placed there.
+type PlaceRule func(r *Registry, s *State, p *Placement) []Change
+
+// placeRules are how the blocks of a type are placed, when it takes more than
+// facing the player or the clicked face.
+var placeRules = map[string]PlaceRule{
+	"minecraft:stair":          placeStairs,
+	"minecraft:slab":           placeSlab,
+	"minecraft:door":           placeDoor,
+	"minecraft:bed":            placeBed,
+	"minecraft:chest":          placeChest,
+	"minecraft:trapped_chest":  placeChest,
+	"minecraft:torch":          placeTorch,
+	"minecraft:redstone_torch": placeTorch,
+	"minecraft:double_plant":   placeDoublePlant,
+	"minecraft:tall_flower":    placeDoublePlant,
+}
+
+// SetPlaceRule sets how blocks of a type are placed, replacing the rule they
+// had. Rules must be set before the registry is used by the game loop.
+func (r *Registry) SetPlaceRule(blockType string, rule PlaceRule) {
+	r.placeRules[blockType] = rule
+}
+
+// Place returns the blocks changed by placing a state. The blocks are only
+// checked to be replaceable beyond the one the placement is at; placing there
+// is checked with CanReplace.
+func (r *Registry) Place(s *State, p *Placement) []Change {
+	if rule, ok := r.placeRules[s.Block.Type]; ok {
+		return rule(r, s, p)
+	}
+	return single(p, r.orient(s, p))
+}
+
+// CanReplace reports whether placing a state where an existing one is replaces
+// it. Slabs are replaced by slabs of the same kind placed into their empty
+// half, making them double.
+func (r *Registry) CanReplace(existing StateID, s *State, p *Placement) bool {
+	e, ok := r.State(existing)
+	if !ok {
+		return false
+	}
+	if e.Block == s.Block && e.Block.Type == "minecraft:slab" {
+		upper := p.HitY-float64(p.Y) > 0.5
+		switch e.Get("type") {
+		case "bottom":
+			return !p.ReplacingClicked || p.Face == Up || upper && p.Face.IsHorizontal()
+		case "top":
+			return !p.ReplacingClicked || p.Face == Down || !upper && p.Face.IsHorizontal()
+		}
+		return false
+	}
+	return r.Replaceable(existing)
+}
+
+func single(p *Placement, s *State) []Change {
+	return []Change{{p.X, p.Y, p.Z, s.ID}}
+}
+
+// orient sets what most blocks take from how they are placed: facing away from
+// the player, the axis of the clicked face, and whether they are placed into
+// water.
+func (r *Registry) orient(s *State, p *Placement) *State {
+	if prop, ok := s.Block.Property("facing"); ok {
+		if prop.index("up") >= 0 {
+			s = s.With("facing", Looking(p.Yaw, p.Pitch).Opposite().String())
+		} else {
+			s = s.With("facing", p.Facing().Opposite().String())
+		}
+	}
+	s = s.With("axis", p.Face.Axis())
+	return r.waterlog(s, p.World, p.X, p.Y, p.Z)
+}
+
+// waterlog sets whether a state is waterlogged by the water source at where it
+// goes.
+func (r *Registry) waterlog(s *State, g Getter, x, y, z int) *State {
+	if _, ok := s.Block.Property("waterlogged"); !ok {
+		return s
+	}
+	w, ok := r.State(g.Block(x, y, z))
+	if ok && w.Block.Name == "minecraft:water" && w.Get("level") == "0" {
+		return s.With("waterlogged", "true")
+	}
+	return s.With("waterlogged", "false")
+}
+
+// at returns the state of a block, nil for unknown ones.
+func (r *Registry) at(g Getter, x, y, z int) *State {
+	s, _ := r.State(g.Block(x, y, z))
+	return s
+}
+
+// toward returns the state of the block next to one in a direction.
+func (r *Registry) toward(g Getter, x, y, z int, d Direction) *State {
+	dx, dy, dz := d.Offset()
+	return r.at(g, x+dx, y+dy, z+dz)
+}
+
+// replaceableAt reports whether a block placed next to another in a direction
+// can go there.
+func (r *Registry) replaceableAt(g Getter, x, y, z int, d Direction) bool {
+	dx, dy, dz := d.Offset()
+	return r.Replaceable(g.Block(x+dx, y+dy, z+dz))
+}
+
+// supports reports whether the block next to another in a direction holds up
+// what is attached to it.
+func (r *Registry) supports(g Getter, x, y, z int, d Direction) bool {
+	dx, dy, dz := d.Offset()
+	return r.IsFullCube(g.Block(x+dx, y+dy, z+dz))
+}
+
+// facingOf returns the direction the facing property of a state holds.
+func facingOf(s *State) Direction {
+	d, _ := DirectionNamed(s.Get("facing"))
+	return d
+}
+
+// placeStairs faces stairs the way the player looks, upside down when placed
+// against the top of a block. Their shape follows from the stairs around them
+// once placed.
+func placeStairs(r *Registry, s *State, p *Placement) []Change {
+	half := "bottom"
+	if p.upperHalf() {
+		half = "top"
+	}
+	s = s.With("facing", p.Facing().String()).With("half", half)
+	return single(p, r.waterlog(s, p.World, p.X, p.Y, p.Z))
+}
+
+// placeSlab places slabs in the half clicked, or makes a slab already there
+// double.
+func placeSlab(r *Registry, s *State, p *Placement) []Change {
+	if e := r.at(p.World, p.X, p.Y, p.Z); e != nil && e.Block == s.Block {
+		return single(p, e.With("type", "double").With("waterlogged", "false"))
+	}
+	half := "bottom"
+	if p.upperHalf() {
+		half = "top"
+	}
+	return single(p, r.waterlog(s.With("type", half), p.World, p.X, p.Y, p.Z))
+}
+
+// placeDoor places both halves of a door facing the way the player looks, on
+// a full block with room above.
+func placeDoor(r *Registry, s *State, p *Placement) []Change {
+	if !r.replaceableAt(p.World, p.X, p.Y, p.Z, Up) || !r.supports(p.World, p.X, p.Y, p.Z, Down) {
+		return nil
+	}
+	facing := p.Facing()
+	lower := s.With("facing", facing.String()).With("hinge", doorHinge(r, s, p, facing)).
+		With("open", "false").With("powered", "false")
+	return []Change{
+		{p.X, p.Y, p.Z, lower.With("half", "lower").ID},
+		{p.X, p.Y + 1, p.Z, lower.With("half", "upper").ID},
+	}
+}
+
+// doorHinge returns the side of the hinge of a door as vanilla picks it: on
+// the side with more solid blocks and away from a door next to it, making a
+// double door, else on the side of the door further from where the player
+// clicked.
+func doorHinge(r *Registry, s *State, p *Placement, facing Direction) string {
+	left, right := facing.CounterClockwise(), facing.Clockwise()
+	g, x, y, z := p.World, p.X, p.Y, p.Z
+
+	solid := func(d Direction, dy int) int {
+		if r.supports(g, x, y+dy, z, d) {
+			return 1
+		}
+		return 0
+	}
+	door := func(d Direction) bool {
+		n := r.toward(g, x, y, z, d)
+		return n != nil && n.Block == s.Block && n.Get("half") == "lower"
+	}
+	leftDoor, rightDoor := door(left), door(right)
+	balance := solid(right, 0) + solid(right, 1) - solid(left, 0) - solid(left, 1)
+
+	if leftDoor && !rightDoor || balance > 0 {
+		return "right"
+	}
+	if rightDoor && !leftDoor || balance < 0 {
+		return "left"
+	}
+	hitX, hitZ := p.HitX-float64(x), p.HitZ-float64(z)
+	dx, _, dz := facing.Offset()
+	if dx < 0 && hitZ < 0.5 || dx > 0 && hitZ > 0.5 || dz < 0 && hitX > 0.5 || dz > 0 && hitX < 0.5 {
+		return "right"
+	}
+	return "left"
+}
+
+// placeBed places the foot of a bed where the player clicked and its head
+// further along the way they look.
+func placeBed(r *Registry, s *State, p *Placement) []Change {
+	facing := p.Facing()
+	if !r.replaceableAt(p.World, p.X, p.Y, p.Z, facing) {
+		return nil
+	}
+	s = s.With("facing", facing.String()).With("occupied", "false")
+	dx, _, dz := facing.Offset()
+	return []Change{
+		{p.X, p.Y, p.Z, s.With("part", "foot").ID},
+		{p.X + dx, p.Y, p.Z + dz, s.With("part", "head").ID},
+	}
+}
+
+// placeChest faces chests towards the player and joins them with a single
+// chest of the same kind beside them into a double one, as vanilla does.
+// Sneaking players join them only with the chest they clicked, taking its
+// facing. The other chest turns double by its shape following.
+func placeChest(r *Registry, s *State, p *Placement) []Change {
+	facing := p.Facing().Opposite()
+	kind := "single"
+	// partner returns the facing of a single chest of the same kind next to
+	// where the chest goes, or false.
+	partner := func(d Direction) (Direction, bool) {
+		n := r.toward(p.World, p.X, p.Y, p.Z, d)
+		if n == nil || n.Block != s.Block || n.Get("type") != "single" {
+			return 0, false
+		}
+		return facingOf(n), true
+	}
+	if p.Face.IsHorizontal() && p.Sneaking {
+		clicked := p.Face.Opposite()
+		if d, ok := partner(clicked); ok && d.Axis() != p.Face.Axis() {
+			facing, kind = d, "left"
+			if d.CounterClockwise() == clicked {
+				kind = "right"
+			}
+		}
+	}
+	if kind == "single" && !p.Sneaking {
+		if d, ok := partner(facing.Clockwise()); ok && d == facing {
+			kind = "left"
+		} else if d, ok := partner(facing.CounterClockwise()); ok && d == facing {
+			kind = "right"
+		}
+	}
+	s = s.With("facing", facing.String()).With("type", kind)
+	return single(p, r.waterlog(s, p.World, p.X, p.Y, p.Z))
+}
+
+// chestPartner returns the direction of the other half of a double chest.
+func chestPartner(s *State) Direction {
+	if s.Get("type") == "left" {
+		return facingOf(s).Clockwise()
+	}
+	return facingOf(s).CounterClockwise()
+}
+
+// placeTorch stands torches on the top of blocks and hangs wall torches on
+// their sides, each only where a full block holds them up. Torches placed on
+// the bottom of a block go on the wall in front of the player instead.
+func placeTorch(r *Registry, s *State, p *Placement) []Change {
+	if p.Face == Up && r.supports(p.World, p.X, p.Y, p.Z, Down) {
+		return single(p, s)
+	}
+	wall, ok := r.Block(strings.Replace(s.Block.Name, "torch", "wall_torch", 1))
+	if !ok {
+		return nil
+	}
+	facing := p.Face
+	if !facing.IsHorizontal() {
+		facing = p.Facing().Opposite()
+	}
+	if !r.supports(p.World, p.X, p.Y, p.Z, facing.Opposite()) {
+		return nil
+	}
+	return single(p, wall.DefaultState().With("facing", facing.String()))
+}
+
+// placeDoublePlant places both halves of plants two blocks tall.
+func placeDoublePlant(r *Registry, s *State, p *Placement) []Change {
+	if !r.replaceableAt(p.World, p.X, p.Y, p.Z, Up) {
+		return nil
+	}
+	return []Change{
+		{p.X, p.Y, p.Z, s.With("half", "lower").ID},
+		{p.X, p.Y + 1, p.Z, s.With("half", "upper").ID},
+	}
+}
@@ -0,0 +1,332 @@
+package block
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// The vanilla 1.21 blocks.json report, as generated with
+// `java -DbundlerMainClass=net.minecraft.data.Main -jar server.jar --reports`.
+//
+//go:embed blocks.json
+var bundledReport []byte
+
+// Registry holds every block type and state of a blocks.json report.
+type Registry struct {
+	blocks map[string]*Block
+	states []*State
+	air    map[StateID]bool
+
+	// light properties indexed by state ID
+	emission []uint8
+	opacity  []uint8
+	// collision shapes indexed by state ID
+	shapes [][]Box
+
+	// placement and shape rules by block type
+	placeRules map[string]PlaceRule
+	shapeRules map[string]ShapeRule
+}
+
+type reportBlock struct {
+	Definition struct {
+		Type string `json:"type"`
+	} `json:"definition"`
+	Properties map[string][]string `json:"properties"`
+	States     []struct {
+		ID         StateID           `json:"id"`
+		Default    bool              `json:"default"`
+		Properties map[string]string `json:"properties"`
+	} `json:"states"`
+}
+
+// LoadReport builds a registry from a vanilla blocks.json data generator report.
+func LoadReport(r io.Reader) (*Registry, error) {
+	report := map[string]reportBlock{}
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("error decoding block report: %w", err)
+	}
+
+	reg := &Registry{
+		blocks: make(map[string]*Block, len(report)),
+		air:    make(map[StateID]bool),
+
+		placeRules: make(map[string]PlaceRule, len(placeRules)),
+		shapeRules: make(map[string]ShapeRule, len(shapeRules)),
+	}
+	for t, rule := range placeRules {
+		reg.placeRules[t] = rule
+	}
+	for t, rule := range shapeRules {
+		reg.shapeRules[t] = rule
+	}
+	for name, rb := range report {
+		b, err := newBlock(name, rb)
+		if err != nil {
+			return nil, err
+		}
+		reg.blocks[name] = b
+		if name == "minecraft:air" || name == "minecraft:cave_air" || name == "minecraft:void_air" {
+			reg.air[b.Default] = true
+		}
+
+		for _, s := range b.states {
+			for StateID(len(reg.states)) <= s.ID {
+				reg.states = append(reg.states, nil)
+			}
+			if reg.states[s.ID] != nil {
+				return nil, fmt.Errorf("duplicate block state id %d in %s and %s", s.ID, reg.states[s.ID].Block.Name, name)
+			}
+			reg.states[s.ID] = s
+		}
+	}
+
+	// block IDs follow the order of their states
+	var id int32
+	for _, s := range reg.states {
+		if s != nil && s.ID == s.Block.states[0].ID {
+			s.Block.ID = id
+			id++
+		}
+	}
+
+	reg.emission = make([]uint8, len(reg.states))
+	reg.opacity = make([]uint8, len(reg.states))
+	reg.shapes = make([][]Box, len(reg.states))
+	for id, s := range reg.states {
+		if s == nil {
+			reg.opacity[id] = MaxLight
+			reg.shapes[id] = fullCube
+			continue
+		}
+		reg.emission[id] = stateEmission(s)
+		reg.opacity[id] = stateOpacity(s)
+		reg.shapes[id] = stateShape(s)
+	}
+
+	return reg, nil
+}
+
+func newBlock(name string, rb reportBlock) (*Block, error) {
+	if len(rb.States) == 0 {
+		return nil, fmt.Errorf("block %s has no states", name)
+	}
+
+	b := &Block{Name: name, Type: rb.Definition.Type}
+
+	// vanilla orders properties by name when enumerating states
+	names := make([]string, 0, len(rb.Properties))
+	for n := range rb.Properties {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		b.Properties = append(b.Properties, Property{Name: n, Values: rb.Properties[n]})
+	}
+
+	b.states = make([]*State, len(rb.States))
+	for _, rs := range rb.States {
+		s := &State{ID: rs.ID, Block: b, values: make([]int, len(b.Properties))}
+		offset := 0
+		for i, p := range b.Properties {
+			index := p.index(rs.Properties[p.Name])
+			if index < 0 {
+				return nil, fmt.Errorf("block state %d of %s has invalid %s", rs.ID, name, p.Name)
+			}
+			s.values[i] = index
+			offset = offset*len(p.Values) + index
+		}
+
+		if offset >= len(b.states) || rs.ID != rb.States[0].ID+StateID(offset) {
+			return nil, fmt.Errorf("block state %d of %s is out of order", rs.ID, name)
+		}
+		b.states[offset] = s
+		if rs.Default {
+			b.Default = rs.ID
+		}
+	}
+
+	if b.states[0].ID != rb.States[0].ID {
+		return nil, fmt.Errorf("block %s states do not start at the lowest id", name)
+	}
+	return b, nil
+}
+
+// Block returns a block type by name. The minecraft namespace is optional.
+func (r *Registry) Block(name string) (*Block, bool) {
+	b, ok := r.blocks[withNamespace(name)]
+	return b, ok
+}
+
+// Blocks returns every block type, in no particular order.
+func (r *Registry) Blocks() []*Block {
+	blocks := make([]*Block, 0, len(r.blocks))
+	for _, b := range r.blocks {
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// Names returns the name of every block type, ordered by ID.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.blocks))
+	for _, b := range r.blocks {
+		names[b.ID] = b.Name
+	}
+	return names
+}
+
+// State returns the state with a global ID.
+func (r *Registry) State(id StateID) (*State, bool) {
+	if int(id) >= len(r.states) || r.states[id] == nil {
+		return nil, false
+	}
+	return r.states[id], true
+}
+
+// IsAir reports whether a state is one of the air blocks, which don't count
+// towards a chunk section's block count.
+func (r *Registry) IsAir(id StateID) bool {
+	return r.air[id]
+}
+
+// StateCount returns the size of the global palette.
+func (r *Registry) StateCount() int {
+	return len(r.states)
+}
+
+// BitsPerState returns the bits per entry of a direct palette.
+func (r *Registry) BitsPerState() int {
+	return bits.Len(uint(len(r.states) - 1))
+}
+
+// ParseState looks up a state from its string form, e.g.
+// minecraft:oak_stairs[facing=north,half=bottom]. Properties that are left out
+// take the value of the block's default state.
+func (r *Registry) ParseState(s string) (*State, error) {
+	name, props, hasProps := strings.Cut(strings.TrimSpace(s), "[")
+
+	b, ok := r.Block(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown block: %s", name)
+	}
+
+	state := b.DefaultState()
+	if !hasProps {
+		return state, nil
+	}
+
+	props, ok = strings.CutSuffix(props, "]")
+	if !ok {
+		return nil, fmt.Errorf("unterminated block state properties: %s", s)
+	}
+	if props == "" {
+		return state, nil
+	}
+
+	for _, pair := range strings.Split(props, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid block state property: %s", pair)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		p, ok := b.Property(key)
+		if !ok || p.index(value) < 0 {
+			return nil, fmt.Errorf("invalid property %s=%s for %s", key, value, b.Name)
+		}
+		state = state.With(key, value)
+	}
+
+	return state, nil
+}
+
+// StateFromProperties looks up a state from a block name and property values,
+// the layout used by chunk palettes in region files.
+func (r *Registry) StateFromProperties(name string, props map[string]string) (*State, error) {
+	b, ok := r.Block(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown block: %s", name)
+	}
+
+	state := b.DefaultState()
+	for key, value := range props {
+		p, ok := b.Property(key)
+		if !ok || p.index(value) < 0 {
+			return nil, fmt.Errorf("invalid property %s=%s for %s", key, value, b.Name)
+		}
+		state = state.With(key, value)
+	}
+	return state, nil
+}
+
+// StateID is ParseState returning only the global ID.
+func (r *Registry) StateID(s string) (StateID, error) {
+	state, err := r.ParseState(s)
+	if err != nil {
+		return 0, err
+	}
+	return state.ID, nil
+}
+
+func withNamespace(name string) string {
+	if strings.Contains(name, ":") {
+		return name
+	}
+	return "minecraft:" + name
+}
+
+var (
+	defaultRegistry *Registry
+	defaultOnce     sync.Once
+	defaultMutex    sync.RWMutex
+)
+
+// Default returns the registry used by the rest of the server. Until
+// LoadDefault is called it holds the bundled report.
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		reg, err := LoadReport(bytes.NewReader(bundledReport))
+		if err != nil {
+			panic("block: invalid bundled report: " + err.Error())
+		}
+
+		defaultMutex.Lock()
+		if defaultRegistry == nil {
+			defaultRegistry = reg
+		}
+		defaultMutex.Unlock()
+	})
+
+	defaultMutex.RLock()
+	defer defaultMutex.RUnlock()
+	return defaultRegistry
+}
+
+// LoadDefault replaces the default registry with a report read from a file.
+// It should be called before the world is loaded.
+func LoadDefault(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	reg, err := LoadReport(f)
+	if err != nil {
+		return err
+	}
+
+	defaultMutex.Lock()
+	defaultRegistry = reg
+	defaultMutex.Unlock()
+	return nil
+}
@@ -0,0 +1,279 @@
+package block
+
+import "strings"
+
+// ShapeRule returns the state a block takes from the blocks around it, which is
+// air for blocks that can't stay.
+type ShapeRule func(r *Registry, s *State, g Getter, x, y, z int) *State
+
+// shapeRules are how the blocks of a type change with the blocks around them.
+var shapeRules = map[string]ShapeRule{
+	"minecraft:fence":               fenceShape,
+	"minecraft:iron_bars":           paneShape,
+	"minecraft:pane":                paneShape,
+	"minecraft:stained_glass_pane":  paneShape,
+	"minecraft:wall":                wallShape,
+	"minecraft:stair":               stairsShape,
+	"minecraft:chest":               chestShape,
+	"minecraft:trapped_chest":       chestShape,
+	"minecraft:door":                doorShape,
+	"minecraft:bed":                 bedShape,
+	"minecraft:double_plant":        doublePlantShape,
+	"minecraft:tall_flower":         doublePlantShape,
+	"minecraft:torch":               torchShape,
+	"minecraft:redstone_torch":      torchShape,
+	"minecraft:wall_torch":          wallTorchShape,
+	"minecraft:redstone_wall_torch": wallTorchShape,
+}
+
+// SetShapeRule sets how blocks of a type change with the blocks around them,
+// replacing the rule they had. Rules must be set before the registry is used
+// by the game loop.
+func (r *Registry) SetShapeRule(blockType string, rule ShapeRule) {
+	r.shapeRules[blockType] = rule
+}
+
+// UpdateShape returns the state a block takes from the blocks around it.
+func (r *Registry) UpdateShape(g Getter, x, y, z int) StateID {
+	id := g.Block(x, y, z)
+	s, ok := r.State(id)
+	if !ok {
+		return id
+	}
+	rule, ok := r.shapeRules[s.Block.Type]
+	if !ok {
+		return id
+	}
+	return rule(r, s, g, x, y, z).ID
+}
+
+// airState returns the state of blocks that can't stay.
+func (r *Registry) airState() *State {
+	return r.blocks["minecraft:air"].DefaultState()
+}
+
+// connectionExceptions are full blocks fences, walls and panes don't connect
+// to.
+var connectionExceptions = map[string]bool{
+	"minecraft:barrier":        true,
+	"minecraft:pumpkin":        true,
+	"minecraft:carved_pumpkin": true,
+	"minecraft:jack_o_lantern": true,
+	"minecraft:melon":          true,
+}
+
+// connectsSolid reports whether fences, walls and panes connect to a state
+// for being a full block.
+func (r *Registry) connectsSolid(n *State) bool {
+	if connectionExceptions[n.Block.Name] || strings.HasSuffix(n.Block.Name, "_leaves") ||
+		strings.HasSuffix(n.Block.Name, "shulker_box") {
+		return false
+	}
+	return r.IsFullCube(n.ID)
+}
+
+// gateAlong reports whether a state is a fence gate closing the way along a
+// direction, which fences and walls in that direction connect to.
+func gateAlong(n *State, d Direction) bool {
+	return n.Block.Type == "minecraft:fence_gate" && facingOf(n).Axis() == d.Clockwise().Axis()
+}
+
+func isPane(n *State) bool {
+	switch n.Block.Type {
+	case "minecraft:iron_bars", "minecraft:pane", "minecraft:stained_glass_pane":
+		return true
+	}
+	return false
+}
+
+// connections sets the side properties of a state to whether it connects to
+// the block on each side.
+func (r *Registry) connections(s *State, g Getter, x, y, z int, connects func(n *State, d Direction) bool) *State {
+	for _, d := range Horizontal {
+		n := r.toward(g, x, y, z, d)
+		s = s.With(d.String(), boolString(n != nil && connects(n, d)))
+	}
+	return s
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+// fenceShape connects fences to fences of the same kind, gates across them and
+// full blocks. Nether brick fences don't connect to wooden ones.
+func fenceShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	nether := s.Block.Name == "minecraft:nether_brick_fence"
+	return r.connections(s, g, x, y, z, func(n *State, d Direction) bool {
+		if n.Block.Type == "minecraft:fence" {
+			return (n.Block.Name == "minecraft:nether_brick_fence") == nether
+		}
+		return gateAlong(n, d) || r.connectsSolid(n)
+	})
+}
+
+// paneShape connects glass panes and iron bars to each other, walls and full
+// blocks.
+func paneShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	return r.connections(s, g, x, y, z, func(n *State, d Direction) bool {
+		return isPane(n) || n.Block.Type == "minecraft:wall" || r.connectsSolid(n)
+	})
+}
+
+// wallShape connects walls to walls, panes, gates across them and full blocks.
+// Sides are tall under full blocks and walls reaching over them, and walls have
+// a post unless they run straight with nothing on top.
+func wallShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	above := r.at(g, x, y+1, z)
+	connected := make(map[Direction]bool, 4)
+	for _, d := range Horizontal {
+		n := r.toward(g, x, y, z, d)
+		connected[d] = n != nil && (n.Block.Type == "minecraft:wall" || isPane(n) || gateAlong(n, d) || r.connectsSolid(n))
+
+		side := "none"
+		if connected[d] {
+			side = "low"
+			if above != nil && (r.IsFullCube(above.ID) || above.Block.Type == "minecraft:wall" && above.Get(d.String()) != "none") {
+				side = "tall"
+			}
+		}
+		s = s.With(d.String(), side)
+	}
+
+	straight := connected[North] && connected[South] && !connected[West] && !connected[East] ||
+		connected[West] && connected[East] && !connected[North] && !connected[South]
+	up := !straight
+	if above != nil {
+		switch above.Block.Type {
+		case "minecraft:wall":
+			up = up || above.Get("up") == "true"
+		case "minecraft:torch", "minecraft:redstone_torch":
+			up = true
+		}
+	}
+	return s.With("up", boolString(up))
+}
+
+// stairsShape turns stairs into corners against stairs of the same half
+// facing across them, as vanilla does: outer corners against the stairs in
+// front and inner ones against the stairs behind.
+func stairsShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	dir := facingOf(s)
+	stairs := func(n *State) bool {
+		return n != nil && n.Block.Type == "minecraft:stair" && n.Get("half") == s.Get("half")
+	}
+	// canTurn reports whether the stairs next to s in a direction don't
+	// already continue it.
+	canTurn := func(d Direction) bool {
+		n := r.toward(g, x, y, z, d)
+		return !stairs(n) || facingOf(n) != dir
+	}
+
+	shape := "straight"
+	if front := r.toward(g, x, y, z, dir); stairs(front) {
+		if d := facingOf(front); d.Axis() != dir.Axis() && canTurn(d.Opposite()) {
+			shape = "outer_right"
+			if d == dir.CounterClockwise() {
+				shape = "outer_left"
+			}
+			return s.With("shape", shape)
+		}
+	}
+	if back := r.toward(g, x, y, z, dir.Opposite()); stairs(back) {
+		if d := facingOf(back); d.Axis() != dir.Axis() && canTurn(d) {
+			shape = "inner_right"
+			if d == dir.CounterClockwise() {
+				shape = "inner_left"
+			}
+		}
+	}
+	return s.With("shape", shape)
+}
+
+// chestShape turns double chests single when their other half goes, and single
+// chests double when a chest next to them joins them.
+func chestShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	joins := func(n *State) bool {
+		return n != nil && n.Block == s.Block && n.Get("type") != "single" && facingOf(n) == facingOf(s)
+	}
+	if s.Get("type") != "single" {
+		d := chestPartner(s)
+		if n := r.toward(g, x, y, z, d); joins(n) && n.Get("type") != s.Get("type") && chestPartner(n) == d.Opposite() {
+			return s
+		}
+		return s.With("type", "single")
+	}
+	for _, d := range Horizontal {
+		if n := r.toward(g, x, y, z, d); joins(n) && chestPartner(n) == d.Opposite() {
+			other := "left"
+			if n.Get("type") == "left" {
+				other = "right"
+			}
+			return s.With("type", other)
+		}
+	}
+	return s
+}
+
+// halves returns the direction of the other half of a block two blocks long,
+// and the value of the property that half has.
+type halves func(s *State) (Direction, string)
+
+// pairShape removes blocks made of two halves without their other half.
+func (r *Registry) pairShape(s *State, g Getter, x, y, z int, other halves, property string) *State {
+	d, value := other(s)
+	n := r.toward(g, x, y, z, d)
+	if n == nil || n.Block != s.Block || n.Get(property) != value {
+		return r.airState()
+	}
+	return s
+}
+
+func verticalHalves(s *State) (Direction, string) {
+	if s.Get("half") == "lower" {
+		return Up, "upper"
+	}
+	return Down, "lower"
+}
+
+// doorShape removes doors missing a half or the block under them.
+func doorShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	if s.Get("half") == "lower" && !r.supports(g, x, y, z, Down) {
+		return r.airState()
+	}
+	return r.pairShape(s, g, x, y, z, verticalHalves, "half")
+}
+
+// bedShape removes beds missing their head or foot.
+func bedShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	return r.pairShape(s, g, x, y, z, func(s *State) (Direction, string) {
+		if s.Get("part") == "foot" {
+			return facingOf(s), "head"
+		}
+		return facingOf(s).Opposite(), "foot"
+	}, "part")
+}
+
+// doublePlantShape removes tall plants missing a half.
+func doublePlantShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	return r.pairShape(s, g, x, y, z, verticalHalves, "half")
+}
+
+// torchShape removes torches without a full block under them.
+func torchShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	if !r.supports(g, x, y, z, Down) {
+		return r.airState()
+	}
+	return s
+}
+
+// wallTorchShape removes wall torches without the block they hang on.
+func wallTorchShape(r *Registry, s *State, g Getter, x, y, z int) *State {
+	if !r.supports(g, x, y, z, facingOf(s).Opposite()) {
+		return r.airState()
+	}
+	return s
+}
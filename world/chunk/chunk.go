@@ -0,0 +1,460 @@
+// Package chunk holds the in-memory representation of chunk columns and their
+// encoding in the Chunk Data and Update Light packets.
+//
+// https://wiki.vg/Chunk_Format
+package chunk
+
+import (
+	"cmp"
+	"maps"
+	"math/bits"
+	"slices"
+	"sync/atomic"
+
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/world/biome"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// Heightmap types sent to the client
+const (
+	MotionBlocking = "MOTION_BLOCKING"
+	WorldSurface   = "WORLD_SURFACE"
+)
+
+// LightArraySize is the size of a section's nibble array of light levels.
+const LightArraySize = SectionVolume / 2
+
+// Chunk is a 16 block wide column of sections. A chunk is not safe for
+// concurrent use, whoever generated or loaded it hands it over when done. Only
+// whether it is dirty may be checked from any goroutine.
+type Chunk struct {
+	X, Z     int32
+	MinY     int
+	Sections []*Section
+
+	// One nibble array per light section, which includes a section below and
+	// above the world. A nil array has not been calculated yet.
+	SkyLight   [][]byte
+	BlockLight [][]byte
+
+	// Extra holds the tags of the saved chunk the server doesn't use, like its
+	// scheduled ticks and structures, which are saved back unchanged.
+	Extra nbt.Compound
+
+	// highest block + 1 relative to MinY per column, 0 if the column is empty
+	motionBlocking [SectionWidth * SectionWidth]int16
+	worldSurface   [SectionWidth * SectionWidth]int16
+
+	blockEntities map[blockPos]nbt.Compound
+
+	// dirty is set when the chunk changed since it was last saved
+	dirty atomic.Bool
+}
+
+// blockPos is a position in a chunk, x and z relative to it and y in the world.
+type blockPos struct {
+	x, y, z int
+}
+
+// New creates a chunk of air spanning height blocks from minY.
+func New(x, z int32, minY, height int) *Chunk {
+	sections := make([]*Section, height/SectionWidth)
+	for i := range sections {
+		sections[i] = NewSection()
+	}
+
+	return &Chunk{
+		X:          x,
+		Z:          z,
+		MinY:       minY,
+		Sections:   sections,
+		SkyLight:   make([][]byte, len(sections)+2),
+		BlockLight: make([][]byte, len(sections)+2),
+	}
+}
+
+// Height returns the number of blocks the chunk spans vertically.
+func (c *Chunk) Height() int {
+	return len(c.Sections) * SectionWidth
+}
+
+// MaxY returns the highest y coordinate inside the chunk.
+func (c *Chunk) MaxY() int {
+	return c.MinY + c.Height() - 1
+}
+
+// Section returns the section containing a world y coordinate.
+func (c *Chunk) Section(y int) (*Section, bool) {
+	i := (y - c.MinY) >> 4
+	if y < c.MinY || i >= len(c.Sections) {
+		return nil, false
+	}
+	return c.Sections[i], true
+}
+
+// Block returns the state at chunk relative x and z and world y. Positions
+// outside the chunk are air.
+func (c *Chunk) Block(x, y, z int) block.StateID {
+	s, ok := c.Section(y)
+	if !ok {
+		return block.Air
+	}
+	return s.Block(x&15, y&15, z&15)
+}
+
+// SetBlock changes the state at chunk relative x and z and world y and returns
+// the previous state.
+func (c *Chunk) SetBlock(x, y, z int, state block.StateID) block.StateID {
+	s, ok := c.Section(y)
+	if !ok {
+		return block.Air
+	}
+	old := s.SetBlock(x&15, y&15, z&15, state)
+	if old != state {
+		c.updateHeight(x&15, y, z&15, state)
+		c.dirty.Store(true)
+		if !sameBlock(old, state) {
+			c.SetBlockEntity(x, y, z, nil)
+		}
+	}
+	return old
+}
+
+func sameBlock(a, b block.StateID) bool {
+	reg := block.Default()
+	sa, okA := reg.State(a)
+	sb, okB := reg.State(b)
+	return okA && okB && sa.Block == sb.Block
+}
+
+// BlockEntity returns the data of the block entity at chunk relative x and z
+// and world y, in the layout vanilla saves it.
+func (c *Chunk) BlockEntity(x, y, z int) (nbt.Compound, bool) {
+	data, ok := c.blockEntities[blockPos{x & 15, y, z & 15}]
+	return data, ok
+}
+
+// SetBlockEntity stores the data of the block entity at chunk relative x and z
+// and world y, or removes it when data is nil. Its x, y and z tags are set to
+// the world position.
+func (c *Chunk) SetBlockEntity(x, y, z int, data nbt.Compound) {
+	pos := blockPos{x & 15, y, z & 15}
+	if data == nil {
+		if _, ok := c.blockEntities[pos]; ok {
+			delete(c.blockEntities, pos)
+			c.dirty.Store(true)
+		}
+		return
+	}
+	if c.blockEntities == nil {
+		c.blockEntities = make(map[blockPos]nbt.Compound)
+	}
+	data["x"] = c.X<<4 + int32(pos.x)
+	data["y"] = int32(y)
+	data["z"] = c.Z<<4 + int32(pos.z)
+	c.blockEntities[pos] = data
+	c.dirty.Store(true)
+}
+
+// BlockEntities returns the data of every block entity in the chunk, ordered
+// by position.
+func (c *Chunk) BlockEntities() []nbt.Compound {
+	positions := make([]blockPos, 0, len(c.blockEntities))
+	for pos := range c.blockEntities {
+		positions = append(positions, pos)
+	}
+	slices.SortFunc(positions, func(a, b blockPos) int {
+		return cmp.Or(cmp.Compare(a.y, b.y), cmp.Compare(a.z, b.z), cmp.Compare(a.x, b.x))
+	})
+	entities := make([]nbt.Compound, len(positions))
+	for i, pos := range positions {
+		entities[i] = c.blockEntities[pos]
+	}
+	return entities
+}
+
+// MarkDirty marks the chunk as changed since it was last saved.
+func (c *Chunk) MarkDirty() {
+	c.dirty.Store(true)
+}
+
+// Dirty reports whether the chunk changed since it was last saved.
+func (c *Chunk) Dirty() bool {
+	return c.dirty.Load()
+}
+
+// TakeDirty reports whether the chunk changed since it was last saved, and
+// marks it as saved.
+func (c *Chunk) TakeDirty() bool {
+	return c.dirty.Swap(false)
+}
+
+// Biome returns the biome at chunk relative x and z and world y.
+func (c *Chunk) Biome(x, y, z int) biome.ID {
+	s, ok := c.Section(y)
+	if !ok {
+		return biome.Plains
+	}
+	return s.Biome((x&15)/BiomeWidth, (y&15)/BiomeWidth, (z&15)/BiomeWidth)
+}
+
+// SetBiome changes the biome of the 4x4x4 cell containing a position.
+func (c *Chunk) SetBiome(x, y, z int, id biome.ID) {
+	s, ok := c.Section(y)
+	if !ok {
+		return
+	}
+	s.SetBiome((x&15)/BiomeWidth, (y&15)/BiomeWidth, (z&15)/BiomeWidth, id)
+}
+
+// TopY returns the y coordinate above the highest block of a heightmap type in
+// a column, or MinY if the column is empty.
+func (c *Chunk) TopY(heightmap string, x, z int) int {
+	i := (z&15)<<4 | x&15
+	if heightmap == MotionBlocking {
+		return c.MinY + int(c.motionBlocking[i])
+	}
+	return c.MinY + int(c.worldSurface[i])
+}
+
+// blocksMotion decides which blocks end up in the MOTION_BLOCKING heightmap.
+// Without collision shapes every non-air block counts.
+func blocksMotion(state block.StateID) bool {
+	return !block.Default().IsAir(state)
+}
+
+func (c *Chunk) updateHeight(x, y, z int, state block.StateID) {
+	i := z<<4 | x
+	rel := int16(y - c.MinY + 1)
+	reg := block.Default()
+
+	update := func(heights *[256]int16, matches func(block.StateID) bool) {
+		if matches(state) {
+			heights[i] = max(heights[i], rel)
+			return
+		}
+		if heights[i] != rel {
+			return
+		}
+		// the top block was removed, scan down for the next one
+		for y := y - 1; y >= c.MinY; y-- {
+			if matches(c.Block(x, y, z)) {
+				heights[i] = int16(y - c.MinY + 1)
+				return
+			}
+		}
+		heights[i] = 0
+	}
+
+	update(&c.worldSurface, func(s block.StateID) bool { return !reg.IsAir(s) })
+	update(&c.motionBlocking, blocksMotion)
+}
+
+// RecalculateHeightmaps rebuilds the heightmaps from the block data, for
+// chunks whose sections were filled directly.
+func (c *Chunk) RecalculateHeightmaps() {
+	reg := block.Default()
+	for z := 0; z < SectionWidth; z++ {
+		for x := 0; x < SectionWidth; x++ {
+			i := z<<4 | x
+			c.worldSurface[i], c.motionBlocking[i] = 0, 0
+			for y := c.MaxY(); y >= c.MinY; y-- {
+				state := c.Block(x, y, z)
+				if c.worldSurface[i] == 0 && !reg.IsAir(state) {
+					c.worldSurface[i] = int16(y - c.MinY + 1)
+				}
+				if blocksMotion(state) {
+					c.motionBlocking[i] = int16(y - c.MinY + 1)
+					break
+				}
+			}
+		}
+	}
+}
+
+// Heightmaps returns the heightmaps in the NBT layout the client expects.
+func (c *Chunk) Heightmaps() nbt.Compound {
+	bitsPerEntry := bits.Len(uint(c.Height()))
+	return nbt.Compound{
+		MotionBlocking: packHeights(&c.motionBlocking, bitsPerEntry),
+		WorldSurface:   packHeights(&c.worldSurface, bitsPerEntry),
+	}
+}
+
+func packHeights(heights *[256]int16, bitsPerEntry int) []int64 {
+	perLong := 64 / bitsPerEntry
+	longs := make([]int64, longsFor(len(heights), bitsPerEntry))
+	for i, h := range heights {
+		longs[i/perLong] |= int64(h) << ((i % perLong) * bitsPerEntry)
+	}
+	return longs
+}
+
+// EncodeData writes the Chunk Data and Update Light packet body.
+//
+// https://wiki.vg/Protocol#Chunk_Data_and_Update_Light
+func (c *Chunk) EncodeData(w *io.Writer) {
+	w.WriteInt(c.X)
+	w.WriteInt(c.Z)
+	w.WriteNBT(c.Heightmaps())
+
+	data := io.NewWriter()
+	for _, s := range c.Sections {
+		s.Encode(data)
+	}
+	w.WriteByteArray(data.Bytes())
+
+	c.encodeBlockEntities(w)
+	c.EncodeLight(w)
+}
+
+// clientOnlyTags are the tags of block entities that clients aren't sent,
+// their position that is sent packed and contents they have no need of.
+var clientOnlyTags = []string{"id", "x", "y", "z", "Items"}
+
+// encodeBlockEntities writes the block entities of types clients know, with
+// the data they show.
+func (c *Chunk) encodeBlockEntities(w *io.Writer) {
+	type entity struct {
+		pos  blockPos
+		id   int32
+		data nbt.Compound
+	}
+	var entities []entity
+	for _, data := range c.BlockEntities() {
+		name, _ := data.String("id")
+		id, ok := block.EntityTypeID(name)
+		if !ok {
+			continue
+		}
+		x, _ := data.Int("x")
+		y, _ := data.Int("y")
+		z, _ := data.Int("z")
+		shown := maps.Clone(data)
+		for _, tag := range clientOnlyTags {
+			delete(shown, tag)
+		}
+		entities = append(entities, entity{blockPos{int(x) & 15, int(y), int(z) & 15}, id, shown})
+	}
+
+	w.WriteVarInt(int32(len(entities)))
+	for _, e := range entities {
+		w.WriteUbyte(uint8(e.pos.x<<4 | e.pos.z))
+		w.WriteShort(int16(e.pos.y))
+		w.WriteVarInt(e.id)
+		w.WriteNBT(e.data)
+	}
+}
+
+// LightSection returns the index into SkyLight and BlockLight of the light
+// section containing a world y, or -1 outside the light sections.
+func (c *Chunk) LightSection(y int) int {
+	i := (y-c.MinY)>>4 + 1
+	if i < 0 || i >= len(c.SkyLight) {
+		return -1
+	}
+	return i
+}
+
+// SkyLightAt returns the sky light at chunk relative x and z and world y.
+func (c *Chunk) SkyLightAt(x, y, z int) uint8 {
+	return c.lightAt(c.SkyLight, x, y, z)
+}
+
+// SetSkyLight changes the sky light at chunk relative x and z and world y.
+func (c *Chunk) SetSkyLight(x, y, z int, level uint8) {
+	c.setLight(c.SkyLight, x, y, z, level)
+}
+
+// BlockLightAt returns the block light at chunk relative x and z and world y.
+func (c *Chunk) BlockLightAt(x, y, z int) uint8 {
+	return c.lightAt(c.BlockLight, x, y, z)
+}
+
+// SetBlockLight changes the block light at chunk relative x and z and world y.
+func (c *Chunk) SetBlockLight(x, y, z int, level uint8) {
+	c.setLight(c.BlockLight, x, y, z, level)
+}
+
+func nibbleIndex(x, y, z int) int {
+	return (y&15)<<8 | z<<4 | x
+}
+
+func (c *Chunk) lightAt(light [][]byte, x, y, z int) uint8 {
+	i := c.LightSection(y)
+	if i < 0 || light[i] == nil {
+		return 0
+	}
+	n := nibbleIndex(x, y, z)
+	return light[i][n>>1] >> ((n & 1) * 4) & 0xF
+}
+
+func (c *Chunk) setLight(light [][]byte, x, y, z int, level uint8) {
+	i := c.LightSection(y)
+	if i < 0 {
+		return
+	}
+	if light[i] == nil {
+		if level == 0 {
+			return
+		}
+		light[i] = make([]byte, LightArraySize)
+	}
+	n := nibbleIndex(x, y, z)
+	shift := (n & 1) * 4
+	light[i][n>>1] = light[i][n>>1]&^(0xF<<shift) | (level&0xF)<<shift
+}
+
+// EncodeLight writes the light data shared by Chunk Data and Update Light.
+//
+// https://wiki.vg/Protocol#Update_Light
+func (c *Chunk) EncodeLight(w *io.Writer) {
+	skyMask, emptySkyMask, skyArrays := lightMasks(c.SkyLight)
+	blockMask, emptyBlockMask, blockArrays := lightMasks(c.BlockLight)
+
+	w.WriteBitSet(skyMask)
+	w.WriteBitSet(blockMask)
+	w.WriteBitSet(emptySkyMask)
+	w.WriteBitSet(emptyBlockMask)
+
+	w.WriteVarInt(int32(len(skyArrays)))
+	for _, arr := range skyArrays {
+		w.WriteByteArray(arr)
+	}
+	w.WriteVarInt(int32(len(blockArrays)))
+	for _, arr := range blockArrays {
+		w.WriteByteArray(arr)
+	}
+}
+
+// lightMasks splits light sections into those that are sent, those that are
+// known to be completely dark, and the arrays of the sent ones.
+func lightMasks(light [][]byte) (mask, emptyMask []int64, arrays [][]byte) {
+	words := (len(light) + 63) / 64
+	mask = make([]int64, words)
+	emptyMask = make([]int64, words)
+
+	for i, arr := range light {
+		if arr == nil {
+			continue
+		}
+		if isDark(arr) {
+			emptyMask[i/64] |= 1 << (i % 64)
+			continue
+		}
+		mask[i/64] |= 1 << (i % 64)
+		arrays = append(arrays, arr)
+	}
+	return mask, emptyMask, arrays
+}
+
+func isDark(arr []byte) bool {
+	for _, b := range arr {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,202 @@
+package chunk
+
+import (
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/world/biome"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// strategy describes the palette sizes a kind of container switches between.
+//
+// https://wiki.vg/Chunk_Format#Paletted_Container_structure
+type strategy struct {
+	size       int // number of entries
+	minBits    int // bits per entry of the smallest indirect palette
+	maxBits    int // bits per entry of the largest indirect palette
+	directBits func() int
+}
+
+var blockStrategy = &strategy{
+	size:       SectionVolume,
+	minBits:    4,
+	maxBits:    8,
+	directBits: func() int { return block.Default().BitsPerState() },
+}
+
+var biomeStrategy = &strategy{
+	size:       BiomeVolume,
+	minBits:    1,
+	maxBits:    3,
+	directBits: biome.BitsPerEntry,
+}
+
+// PalettedContainer stores a fixed number of registry IDs. It holds a single
+// value while every entry is the same, a local palette while there are few
+// distinct values, and raw global IDs (the direct palette) after that.
+type PalettedContainer struct {
+	strategy *strategy
+	bits     int      // 0 while single valued
+	palette  []uint32 // nil when direct
+	data     []uint64
+}
+
+func newContainer(s *strategy, value uint32) *PalettedContainer {
+	return &PalettedContainer{
+		strategy: s,
+		palette:  []uint32{value},
+	}
+}
+
+// NewBlockContainer creates a container of a section's 4096 block states.
+func NewBlockContainer(state block.StateID) *PalettedContainer {
+	return newContainer(blockStrategy, uint32(state))
+}
+
+// NewBiomeContainer creates a container of a section's 64 biome cells.
+func NewBiomeContainer(id biome.ID) *PalettedContainer {
+	return newContainer(biomeStrategy, uint32(id))
+}
+
+// Len returns the number of entries.
+func (p *PalettedContainer) Len() int {
+	return p.strategy.size
+}
+
+// Get returns the value of an entry.
+func (p *PalettedContainer) Get(i int) uint32 {
+	if p.bits == 0 {
+		return p.palette[0]
+	}
+	v := p.read(i)
+	if p.palette == nil {
+		return v
+	}
+	return p.palette[v]
+}
+
+// Set changes an entry and returns its previous value.
+func (p *PalettedContainer) Set(i int, value uint32) uint32 {
+	old := p.Get(i)
+	if old == value {
+		return old
+	}
+
+	index, ok := p.index(value)
+	if !ok {
+		p.grow()
+		index, _ = p.index(value)
+	}
+	p.write(i, index)
+	return old
+}
+
+// Fill sets every entry to one value.
+func (p *PalettedContainer) Fill(value uint32) {
+	p.bits = 0
+	p.palette = []uint32{value}
+	p.data = nil
+}
+
+// Palette returns the distinct values stored, in palette order for indirect
+// containers.
+func (p *PalettedContainer) Palette() []uint32 {
+	if p.palette != nil {
+		return p.palette
+	}
+	seen := map[uint32]bool{}
+	values := []uint32{}
+	for i := 0; i < p.strategy.size; i++ {
+		v := p.read(i)
+		if !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// index returns the value written to the data array for a value, adding it to
+// the palette if there is room.
+func (p *PalettedContainer) index(value uint32) (uint64, bool) {
+	if p.palette == nil {
+		return uint64(value), true
+	}
+	for i, v := range p.palette {
+		if v == value {
+			return uint64(i), true
+		}
+	}
+	if len(p.palette) >= 1<<p.bits {
+		return 0, false
+	}
+	p.palette = append(p.palette, value)
+	return uint64(len(p.palette) - 1), true
+}
+
+// grow moves the entries into a container with twice the palette room,
+// switching to the direct palette past the strategy's largest indirect one.
+func (p *PalettedContainer) grow() {
+	values := make([]uint32, p.strategy.size)
+	for i := range values {
+		values[i] = p.Get(i)
+	}
+
+	newBits := max(p.bits+1, p.strategy.minBits)
+	if newBits > p.strategy.maxBits {
+		p.bits = p.strategy.directBits()
+		p.palette = nil
+	} else {
+		p.bits = newBits
+	}
+	p.data = make([]uint64, longsFor(p.strategy.size, p.bits))
+
+	for i, v := range values {
+		index, _ := p.index(v)
+		p.write(i, index)
+	}
+}
+
+// entries never span two longs, the high bits of each long stay unused when
+// bits doesn't divide 64.
+func longsFor(size, bits int) int {
+	perLong := 64 / bits
+	return (size + perLong - 1) / perLong
+}
+
+func (p *PalettedContainer) read(i int) uint32 {
+	perLong := 64 / p.bits
+	shift := (i % perLong) * p.bits
+	mask := uint64(1)<<p.bits - 1
+	return uint32(p.data[i/perLong] >> shift & mask)
+}
+
+func (p *PalettedContainer) write(i int, v uint64) {
+	if p.bits == 0 {
+		return
+	}
+	perLong := 64 / p.bits
+	shift := (i % perLong) * p.bits
+	mask := uint64(1)<<p.bits - 1
+	long := &p.data[i/perLong]
+	*long = *long&^(mask<<shift) | (v&mask)<<shift
+}
+
+// Encode writes the container in the network format.
+func (p *PalettedContainer) Encode(w *io.Writer) {
+	w.WriteUbyte(uint8(p.bits))
+
+	switch {
+	case p.bits == 0:
+		w.WriteVarInt(int32(p.palette[0]))
+	case p.palette != nil:
+		w.WriteVarInt(int32(len(p.palette)))
+		for _, v := range p.palette {
+			w.WriteVarInt(int32(v))
+		}
+	}
+
+	w.WriteVarInt(int32(len(p.data)))
+	for _, long := range p.data {
+		w.WriteLong(int64(long))
+	}
+}
@@ -0,0 +1,110 @@
+package chunk
+
+import (
+	"github.com/hunterros-s/algernon/server/protocol/io"
+	"github.com/hunterros-s/algernon/world/biome"
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+const (
+	SectionWidth  = 16
+	SectionVolume = SectionWidth * SectionWidth * SectionWidth
+
+	// biomes are stored in 4x4x4 block cells
+	BiomeWidth  = 4
+	BiomeVolume = BiomeWidth * BiomeWidth * BiomeWidth
+)
+
+// Section is a 16x16x16 part of a chunk.
+type Section struct {
+	blockCount int16
+	Blocks     *PalettedContainer
+	Biomes     *PalettedContainer
+}
+
+// NewSection creates a section of air in the plains biome.
+func NewSection() *Section {
+	return &Section{
+		Blocks: NewBlockContainer(block.Air),
+		Biomes: NewBiomeContainer(biome.Plains),
+	}
+}
+
+// NewSectionFrom creates a section from filled containers.
+func NewSectionFrom(blocks, biomes *PalettedContainer) *Section {
+	s := &Section{Blocks: blocks, Biomes: biomes}
+
+	reg := block.Default()
+	for i := 0; i < SectionVolume; i++ {
+		if !reg.IsAir(block.StateID(blocks.Get(i))) {
+			s.blockCount++
+		}
+	}
+	return s
+}
+
+func blockIndex(x, y, z int) int {
+	return y<<8 | z<<4 | x
+}
+
+func biomeIndex(x, y, z int) int {
+	return y<<4 | z<<2 | x
+}
+
+// Block returns the state at section relative coordinates.
+func (s *Section) Block(x, y, z int) block.StateID {
+	return block.StateID(s.Blocks.Get(blockIndex(x, y, z)))
+}
+
+// SetBlock changes the state at section relative coordinates and returns the
+// previous state.
+func (s *Section) SetBlock(x, y, z int, state block.StateID) block.StateID {
+	old := block.StateID(s.Blocks.Set(blockIndex(x, y, z), uint32(state)))
+
+	reg := block.Default()
+	if reg.IsAir(old) && !reg.IsAir(state) {
+		s.blockCount++
+	} else if !reg.IsAir(old) && reg.IsAir(state) {
+		s.blockCount--
+	}
+	return old
+}
+
+// Fill sets every block in the section to one state.
+func (s *Section) Fill(state block.StateID) {
+	s.Blocks.Fill(uint32(state))
+	if block.Default().IsAir(state) {
+		s.blockCount = 0
+	} else {
+		s.blockCount = SectionVolume
+	}
+}
+
+// Biome returns the biome of a cell, given in 4x4x4 cell coordinates.
+func (s *Section) Biome(x, y, z int) biome.ID {
+	return biome.ID(s.Biomes.Get(biomeIndex(x, y, z)))
+}
+
+// SetBiome changes the biome of a cell, given in 4x4x4 cell coordinates.
+func (s *Section) SetBiome(x, y, z int, id biome.ID) {
+	s.Biomes.Set(biomeIndex(x, y, z), uint32(id))
+}
+
+// BlockCount returns the number of non-air blocks.
+func (s *Section) BlockCount() int {
+	return int(s.blockCount)
+}
+
+// IsEmpty reports whether the section only contains air.
+func (s *Section) IsEmpty() bool {
+	return s.blockCount == 0
+}
+
+// Encode writes the section in the Chunk Data format.
+//
+// https://wiki.vg/Chunk_Format#Chunk_Section_structure
+func (s *Section) Encode(w *io.Writer) {
+	w.WriteShort(s.blockCount)
+	s.Blocks.Encode(w)
+	s.Biomes.Encode(w)
+}
@@ -0,0 +1,69 @@
+package world
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// blocksIn calls f for every block position a box overlaps, stopping when f
+// returns true. It reports whether f did.
+func blocksIn(box block.Box, f func(x, y, z int) bool) bool {
+	minX, maxX := int(math.Floor(box.MinX)), int(math.Ceil(box.MaxX))
+	minY, maxY := int(math.Floor(box.MinY)), int(math.Ceil(box.MaxY))
+	minZ, maxZ := int(math.Floor(box.MinZ)), int(math.Ceil(box.MaxZ))
+	for y := minY; y < maxY; y++ {
+		for z := minZ; z < maxZ; z++ {
+			for x := minX; x < maxX; x++ {
+				if f(x, y, z) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// Collides reports whether a box in world coordinates overlaps the collision
+// shape of any loaded block.
+func (w *World) Collides(box block.Box) bool {
+	registry := block.Default()
+	// shapes like fences stick up into the block above
+	search := box
+	search.MinY -= 0.5
+	return blocksIn(search, func(x, y, z int) bool {
+		for _, b := range registry.CollisionShape(w.Block(x, y, z)) {
+			if b.Offset(float64(x), float64(y), float64(z)).Intersects(box) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// CollisionBoxes returns the collision shapes, in world coordinates, of the
+// loaded blocks overlapping a box.
+func (w *World) CollisionBoxes(box block.Box) []block.Box {
+	registry := block.Default()
+	var boxes []block.Box
+	search := box
+	search.MinY -= 0.5
+	blocksIn(search, func(x, y, z int) bool {
+		for _, b := range registry.CollisionShape(w.Block(x, y, z)) {
+			if b = b.Offset(float64(x), float64(y), float64(z)); b.Intersects(box) {
+				boxes = append(boxes, b)
+			}
+		}
+		return false
+	})
+	return boxes
+}
+
+// KeepsAfloat reports whether a box in world coordinates overlaps a block that
+// keeps entities up, like water or a ladder.
+func (w *World) KeepsAfloat(box block.Box) bool {
+	registry := block.Default()
+	return blocksIn(box, func(x, y, z int) bool {
+		return registry.KeepsAfloat(w.Block(x, y, z))
+	})
+}
@@ -0,0 +1,136 @@
+// Package generator holds the world generators chunks are created with.
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hunterros-s/algernon/world/biome"
+	"github.com/hunterros-s/algernon/world/block"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+// DefaultFlatPreset is vanilla's Classic Flat preset.
+const DefaultFlatPreset = "minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains"
+
+// maxFlatLayers is the total layer height vanilla allows.
+const maxFlatLayers = 4096
+
+// Layer is a run of identical blocks in a flat world.
+type Layer struct {
+	State  block.StateID
+	Height int
+}
+
+// Flat generates superflat worlds, stacking layers from the bottom of the
+// world upwards.
+type Flat struct {
+	Layers []Layer
+	Biome  biome.ID
+}
+
+// ParseFlatPreset parses vanilla's flat preset format, e.g.
+// minecraft:bedrock,2*minecraft:dirt,minecraft:grass_block;minecraft:plains
+// Layers are listed bottom to top and may be block states with properties.
+// The biome is optional, and structure options after a second ; are ignored.
+func ParseFlatPreset(preset string) (*Flat, error) {
+	parts := strings.Split(preset, ";")
+
+	flat := &Flat{Biome: biome.Plains}
+	if len(parts) > 1 && strings.TrimSpace(parts[1]) != "" {
+		id, ok := biome.Lookup(strings.TrimSpace(parts[1]))
+		if !ok {
+			return nil, fmt.Errorf("unknown biome in flat preset: %s", parts[1])
+		}
+		flat.Biome = id
+	}
+
+	total := 0
+	for _, layer := range splitLayers(parts[0]) {
+		if layer == "" {
+			continue
+		}
+
+		height := 1
+		name := layer
+		if count, rest, ok := cutCount(layer); ok {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid layer height in flat preset: %s", layer)
+			}
+			height, name = n, rest
+		}
+
+		state, err := block.Default().ParseState(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid layer in flat preset: %w", err)
+		}
+
+		total += height
+		if total > maxFlatLayers {
+			return nil, fmt.Errorf("flat preset layers exceed %d blocks", maxFlatLayers)
+		}
+		flat.Layers = append(flat.Layers, Layer{State: state.ID, Height: height})
+	}
+
+	return flat, nil
+}
+
+// splitLayers splits on commas outside of block state properties.
+func splitLayers(s string) []string {
+	layers := []string{}
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				layers = append(layers, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(layers, strings.TrimSpace(s[start:]))
+}
+
+// cutCount splits a layer into its height and block, accepting both 2*dirt
+// and the older 2xdirt.
+func cutCount(layer string) (string, string, bool) {
+	for i, r := range layer {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		if i > 0 && (r == '*' || r == 'x') {
+			return layer[:i], layer[i+1:], true
+		}
+		break
+	}
+	return "", "", false
+}
+
+func (f *Flat) Generate(c *chunk.Chunk) {
+	y := c.MinY
+	for _, layer := range f.Layers {
+		for i := 0; i < layer.Height && y <= c.MaxY(); i++ {
+			f.fillLayer(c, y, layer.State)
+			y++
+		}
+	}
+
+	for sy := c.MinY; sy <= c.MaxY(); sy += chunk.SectionWidth {
+		s, _ := c.Section(sy)
+		s.Biomes.Fill(uint32(f.Biome))
+	}
+}
+
+func (f *Flat) fillLayer(c *chunk.Chunk, y int, state block.StateID) {
+	for z := 0; z < chunk.SectionWidth; z++ {
+		for x := 0; x < chunk.SectionWidth; x++ {
+			c.SetBlock(x, y, z, state)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Perlin is Ken Perlin's improved noise with a permutation table shuffled by a
+// seed, so the same seed always produces the same values.
+type Perlin struct {
+	perm [512]uint8
+	// offsets keep different octaves from sampling the same lattice
+	ox, oy, oz float64
+}
+
+// NewPerlin creates a noise source from a random source.
+func NewPerlin(r *rand.Rand) *Perlin {
+	p := &Perlin{
+		ox: r.Float64() * 256,
+		oy: r.Float64() * 256,
+		oz: r.Float64() * 256,
+	}
+	for i := 0; i < 256; i++ {
+		p.perm[i] = uint8(i)
+	}
+	for i := 255; i > 0; i-- {
+		j := r.Intn(i + 1)
+		p.perm[i], p.perm[j] = p.perm[j], p.perm[i]
+	}
+	copy(p.perm[256:], p.perm[:256])
+	return p
+}
+
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(t, a, b float64) float64 {
+	return a + t*(b-a)
+}
+
+func grad(hash uint8, x, y, z float64) float64 {
+	h := hash & 15
+	u := y
+	if h < 8 {
+		u = x
+	}
+	v := z
+	if h < 4 {
+		v = y
+	} else if h == 12 || h == 14 {
+		v = x
+	}
+	if h&1 != 0 {
+		u = -u
+	}
+	if h&2 != 0 {
+		v = -v
+	}
+	return u + v
+}
+
+// Noise3 samples the noise at a point, roughly in [-1, 1].
+func (p *Perlin) Noise3(x, y, z float64) float64 {
+	x, y, z = x+p.ox, y+p.oy, z+p.oz
+
+	fx, fy, fz := math.Floor(x), math.Floor(y), math.Floor(z)
+	X, Y, Z := int(fx)&255, int(fy)&255, int(fz)&255
+	x, y, z = x-fx, y-fy, z-fz
+	u, v, w := fade(x), fade(y), fade(z)
+
+	perm := &p.perm
+	A := int(perm[X]) + Y
+	AA := int(perm[A]) + Z
+	AB := int(perm[A+1]) + Z
+	B := int(perm[X+1]) + Y
+	BA := int(perm[B]) + Z
+	BB := int(perm[B+1]) + Z
+
+	return lerp(w,
+		lerp(v,
+			lerp(u, grad(perm[AA], x, y, z), grad(perm[BA], x-1, y, z)),
+			lerp(u, grad(perm[AB], x, y-1, z), grad(perm[BB], x-1, y-1, z))),
+		lerp(v,
+			lerp(u, grad(perm[AA+1], x, y, z-1), grad(perm[BA+1], x-1, y, z-1)),
+			lerp(u, grad(perm[AB+1], x, y-1, z-1), grad(perm[BB+1], x-1, y-1, z-1))))
+}
+
+// Noise2 samples a 2D slice of the noise.
+func (p *Perlin) Noise2(x, z float64) float64 {
+	return p.Noise3(x, 0, z)
+}
+
+// Octaves sums perlin noise at doubling frequencies and halving amplitudes
+// (fractal Brownian motion), normalized to roughly [-1, 1].
+type Octaves struct {
+	octaves []*Perlin
+	scale   float64 // frequency of the first octave
+}
+
+// NewOctaves creates count octaves whose first one has features about 1/scale
+// blocks wide.
+func NewOctaves(r *rand.Rand, count int, scale float64) *Octaves {
+	o := &Octaves{scale: scale}
+	for i := 0; i < count; i++ {
+		o.octaves = append(o.octaves, NewPerlin(r))
+	}
+	return o
+}
+
+func (o *Octaves) Sample3(x, y, z float64) float64 {
+	total, amplitude, frequency, norm := 0.0, 1.0, o.scale, 0.0
+	for _, p := range o.octaves {
+		total += p.Noise3(x*frequency, y*frequency, z*frequency) * amplitude
+		norm += amplitude
+		amplitude /= 2
+		frequency *= 2
+	}
+	return total / norm
+}
+
+func (o *Octaves) Sample2(x, z float64) float64 {
+	return o.Sample3(x, 0, z)
+}
@@ -0,0 +1,517 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/hunterros-s/algernon/world/biome"
+	"github.com/hunterros-s/algernon/world/block"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+// SeaLevel is the height oceans are filled up to.
+const SeaLevel = 63
+
+// overhang is how far above and below a column's target height the 3D density
+// noise may add or remove blocks.
+const overhang = 12
+
+// lavaLevel is the height caves below which are filled with lava.
+const lavaLevel = -55
+
+// Terrain generates hilly terrain with oceans, biomes, caves, ores and trees.
+// The same seed always produces the same chunks, no matter the order they are
+// generated in. It is safe for concurrent use.
+type Terrain struct {
+	seed int64
+
+	continents  *Octaves
+	erosion     *Octaves
+	hills       *Octaves
+	temperature *Octaves
+	humidity    *Octaves
+	density     *Octaves
+	caveA       *Octaves
+	caveB       *Octaves
+	caverns     *Octaves
+
+	blocks terrainBlocks
+}
+
+type terrainBlocks struct {
+	stone, dirt, grass, sand, sandstone, gravel block.StateID
+	water, lava, bedrock, air                   block.StateID
+	coal, iron, gold, lapis                     block.StateID
+	trees                                       map[treeKind]treeBlocks
+}
+
+type treeKind int
+
+const (
+	oakTree treeKind = iota
+	birchTree
+	spruceTree
+)
+
+type treeBlocks struct {
+	log, leaves block.StateID
+}
+
+// NewTerrain creates a terrain generator. The block registry must contain the
+// blocks it places, which the bundled one does.
+func NewTerrain(seed int64) (*Terrain, error) {
+	r := rand.New(rand.NewSource(seed))
+
+	t := &Terrain{
+		seed:        seed,
+		continents:  NewOctaves(r, 4, 1.0/700),
+		erosion:     NewOctaves(r, 3, 1.0/350),
+		hills:       NewOctaves(r, 4, 1.0/120),
+		temperature: NewOctaves(r, 3, 1.0/500),
+		humidity:    NewOctaves(r, 3, 1.0/400),
+		density:     NewOctaves(r, 3, 1.0/40),
+		caveA:       NewOctaves(r, 2, 1.0/70),
+		caveB:       NewOctaves(r, 2, 1.0/70),
+		caverns:     NewOctaves(r, 3, 1.0/90),
+	}
+
+	reg := block.Default()
+	var err error
+	lookup := func(s string) block.StateID {
+		if err != nil {
+			return block.Air
+		}
+		var id block.StateID
+		id, err = reg.StateID(s)
+		return id
+	}
+
+	t.blocks = terrainBlocks{
+		stone:     lookup("minecraft:stone"),
+		dirt:      lookup("minecraft:dirt"),
+		grass:     lookup("minecraft:grass_block"),
+		sand:      lookup("minecraft:sand"),
+		sandstone: lookup("minecraft:sandstone"),
+		gravel:    lookup("minecraft:gravel"),
+		water:     lookup("minecraft:water"),
+		lava:      lookup("minecraft:lava"),
+		bedrock:   lookup("minecraft:bedrock"),
+		air:       block.Air,
+		coal:      lookup("minecraft:coal_ore"),
+		iron:      lookup("minecraft:iron_ore"),
+		gold:      lookup("minecraft:gold_ore"),
+		lapis:     lookup("minecraft:lapis_ore"),
+		trees: map[treeKind]treeBlocks{
+			oakTree:    {lookup("minecraft:oak_log"), lookup("minecraft:oak_leaves[persistent=true]")},
+			birchTree:  {lookup("minecraft:birch_log"), lookup("minecraft:birch_leaves[persistent=true]")},
+			spruceTree: {lookup("minecraft:spruce_log"), lookup("minecraft:spruce_leaves[persistent=true]")},
+		},
+	}
+	if err != nil {
+		return nil, fmt.Errorf("terrain generator: %w", err)
+	}
+	return t, nil
+}
+
+// column is the 2D shape of the terrain at one x, z.
+type column struct {
+	height int // target surface height before overhangs
+	biome  biome.ID
+}
+
+func (t *Terrain) column(x, z int) column {
+	fx, fz := float64(x), float64(z)
+
+	continent := t.continents.Sample2(fx, fz)
+	erosion := t.erosion.Sample2(fx, fz)
+	hills := t.hills.Sample2(fx, fz)
+
+	// low erosion makes for steep, mountainous hills
+	roughness := 8 + math.Max(0, -erosion)*60
+	height := SeaLevel + 4 + int(continent*90+hills*roughness)
+
+	return column{height: height, biome: t.pickBiome(fx, fz, height)}
+}
+
+// pickBiome is a simple biome source choosing by height, temperature and
+// humidity.
+func (t *Terrain) pickBiome(x, z float64, height int) biome.ID {
+	temperature := t.temperature.Sample2(x, z)
+	humidity := t.humidity.Sample2(x, z)
+
+	switch {
+	case height < SeaLevel-12:
+		if temperature < -0.25 {
+			return biome.MustLookup("deep_frozen_ocean")
+		}
+		return biome.MustLookup("deep_ocean")
+	case height < SeaLevel:
+		switch {
+		case temperature < -0.25:
+			return biome.MustLookup("frozen_ocean")
+		case temperature < -0.1:
+			return biome.MustLookup("cold_ocean")
+		case temperature > 0.25:
+			return biome.MustLookup("warm_ocean")
+		}
+		return biome.MustLookup("ocean")
+	case height <= SeaLevel+2 && temperature <= 0.25:
+		if temperature < -0.25 {
+			return biome.MustLookup("snowy_beach")
+		}
+		return biome.MustLookup("beach")
+	}
+
+	switch {
+	case temperature > 0.25 && humidity < 0:
+		return biome.MustLookup("desert")
+	case temperature > 0.25:
+		return biome.MustLookup("savanna")
+	case temperature < -0.25 && humidity < 0:
+		return biome.MustLookup("snowy_plains")
+	case temperature < -0.25:
+		return biome.MustLookup("snowy_taiga")
+	case temperature < -0.1:
+		return biome.MustLookup("taiga")
+	case humidity > 0.3:
+		return biome.MustLookup("birch_forest")
+	case humidity > 0.1:
+		return biome.MustLookup("forest")
+	}
+	return biome.MustLookup("plains")
+}
+
+// solid decides whether the terrain itself (before caves) is solid.
+func (t *Terrain) solid(x, y, z int, col column) bool {
+	if y < col.height-overhang {
+		return true
+	}
+	if y > col.height+overhang {
+		return false
+	}
+	n := t.density.Sample3(float64(x), float64(y), float64(z))
+	return float64(col.height-y)/float64(overhang)+n*1.5 > 0
+}
+
+// surfaceY returns the y of the highest solid terrain block of a column.
+func (t *Terrain) surfaceY(x, z int, col column) int {
+	for y := col.height + overhang; y >= col.height-overhang; y-- {
+		if t.solid(x, y, z, col) {
+			return y
+		}
+	}
+	return col.height - overhang - 1
+}
+
+// cave decides whether a block is carved out.
+func (t *Terrain) cave(x, y, z int) bool {
+	fx, fy, fz := float64(x), float64(y), float64(z)
+
+	// spaghetti caves are the thin tunnels where two noises are both near zero
+	a := t.caveA.Sample3(fx, fy*1.6, fz)
+	b := t.caveB.Sample3(fx, fy*1.6, fz)
+	if a*a+b*b < 0.0035 {
+		return true
+	}
+
+	// large caverns deep down
+	return y < 24 && t.caverns.Sample3(fx, fy*2, fz) > 0.38
+}
+
+func isSandy(id biome.ID) bool {
+	switch biome.Name(id) {
+	case "minecraft:desert", "minecraft:beach", "minecraft:snowy_beach":
+		return true
+	}
+	return false
+}
+
+func isOcean(id biome.ID) bool {
+	switch biome.Name(id) {
+	case "minecraft:ocean", "minecraft:deep_ocean", "minecraft:warm_ocean", "minecraft:cold_ocean",
+		"minecraft:frozen_ocean", "minecraft:deep_frozen_ocean":
+		return true
+	}
+	return false
+}
+
+// Generate fills a chunk with terrain, carves caves and decorates it with ores
+// and trees.
+func (t *Terrain) Generate(c *chunk.Chunk) {
+	baseX, baseZ := int(c.X)*16, int(c.Z)*16
+
+	var columns [16][16]column
+	for z := 0; z < 16; z++ {
+		for x := 0; x < 16; x++ {
+			columns[x][z] = t.column(baseX+x, baseZ+z)
+		}
+	}
+
+	for z := 0; z < 16; z++ {
+		for x := 0; x < 16; x++ {
+			t.generateColumn(c, x, z, columns[x][z])
+		}
+	}
+
+	// biomes are stored per 4x4x4 cell, sampled at the cell's center column
+	for cz := 0; cz < 4; cz++ {
+		for cx := 0; cx < 4; cx++ {
+			id := columns[cx*4+2][cz*4+2].biome
+			for y := c.MinY; y <= c.MaxY(); y += chunk.BiomeWidth {
+				c.SetBiome(cx*4, y, cz*4, id)
+			}
+		}
+	}
+
+	t.placeOres(c)
+	t.placeTrees(c)
+}
+
+func (t *Terrain) generateColumn(c *chunk.Chunk, x, z int, col column) {
+	b := &t.blocks
+	wx, wz := int(c.X)*16+x, int(c.Z)*16+z
+	top := t.surfaceY(wx, wz, col)
+
+	// caves stay away from the surface so they don't flood or leave holes
+	caveCeiling := top - 6
+	if top < SeaLevel {
+		caveCeiling = top - 12
+	}
+
+	for y := c.MinY; y <= max(top, SeaLevel); y++ {
+		var state block.StateID
+		switch {
+		case y == c.MinY || (y <= c.MinY+4 && positionRandom(t.seed, wx, y, wz)%5 >= y-c.MinY):
+			state = b.bedrock
+		case y > top || !t.solid(wx, y, wz, col):
+			if y <= SeaLevel {
+				state = b.water
+			} else {
+				state = b.air
+			}
+		case y < caveCeiling && t.cave(wx, y, wz):
+			if y <= lavaLevel {
+				state = b.lava
+			} else {
+				state = b.air
+			}
+		default:
+			state = t.surfaceBlock(y, top, col)
+		}
+
+		if state != b.air {
+			c.SetBlock(x, y, z, state)
+		}
+	}
+}
+
+// surfaceBlock applies the surface rules to solid terrain.
+func (t *Terrain) surfaceBlock(y, top int, col column) block.StateID {
+	b := &t.blocks
+	depth := top - y
+
+	switch {
+	case isOcean(col.biome):
+		if depth < 3 {
+			if top > SeaLevel-8 {
+				return b.sand
+			}
+			return b.gravel
+		}
+	case isSandy(col.biome):
+		if depth < 3 {
+			return b.sand
+		}
+		if depth < 5 {
+			return b.sandstone
+		}
+	default:
+		if depth == 0 {
+			if top < SeaLevel {
+				// lake and river beds
+				return b.dirt
+			}
+			return b.grass
+		}
+		if depth < 4 {
+			return b.dirt
+		}
+	}
+	return b.stone
+}
+
+// positionRandom hashes a position, for decisions that must not depend on the
+// order chunks are generated in.
+func positionRandom(seed int64, x, y, z int) int {
+	h := uint64(seed) ^ uint64(x)*0x9E3779B97F4A7C15 ^ uint64(y)*0xC2B2AE3D27D4EB4F ^ uint64(z)*0x165667B19E3779F9
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	return int(h & 0x7FFFFFFF)
+}
+
+// chunkRandom creates the random source of one chunk's features.
+func (t *Terrain) chunkRandom(cx, cz int32, salt int) *rand.Rand {
+	return rand.New(rand.NewSource(int64(positionRandom(t.seed, int(cx), salt, int(cz)))))
+}
+
+type oreVein struct {
+	state      block.StateID
+	veins      int
+	size       int
+	minY, maxY int
+}
+
+func (t *Terrain) placeOres(c *chunk.Chunk) {
+	b := &t.blocks
+	r := t.chunkRandom(c.X, c.Z, 1)
+
+	ores := []oreVein{
+		{b.coal, 20, 12, 0, 128},
+		{b.iron, 10, 8, -24, 56},
+		{b.gold, 4, 8, -64, 32},
+		{b.lapis, 2, 6, -32, 32},
+	}
+
+	for _, ore := range ores {
+		for i := 0; i < ore.veins; i++ {
+			x, z := r.Intn(16), r.Intn(16)
+			y := ore.minY + r.Intn(ore.maxY-ore.minY)
+			for j := 0; j < ore.size; j++ {
+				if c.Block(x, y, z) == b.stone {
+					c.SetBlock(x, y, z, ore.state)
+				}
+				// random walk, staying inside the chunk
+				x = clamp(x+r.Intn(3)-1, 0, 15)
+				y += r.Intn(3) - 1
+				z = clamp(z+r.Intn(3)-1, 0, 15)
+			}
+		}
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	return max(lo, min(v, hi))
+}
+
+// treesPerChunk is how many trees each biome attempts to place per chunk.
+func treesPerChunk(id biome.ID) (treeKind, int) {
+	switch biome.Name(id) {
+	case "minecraft:forest":
+		return oakTree, 8
+	case "minecraft:birch_forest":
+		return birchTree, 8
+	case "minecraft:taiga":
+		return spruceTree, 7
+	case "minecraft:snowy_taiga":
+		return spruceTree, 4
+	case "minecraft:plains", "minecraft:savanna":
+		return oakTree, 1
+	case "minecraft:snowy_plains":
+		return spruceTree, 1
+	}
+	return oakTree, 0
+}
+
+// placeTrees places the parts of every tree rooted in this or a neighboring
+// chunk that reach into this chunk. Trees are regenerated from their origin
+// chunk's seed each time, so they line up across chunk borders.
+func (t *Terrain) placeTrees(c *chunk.Chunk) {
+	for dz := int32(-1); dz <= 1; dz++ {
+		for dx := int32(-1); dx <= 1; dx++ {
+			t.placeChunkTrees(c, c.X+dx, c.Z+dz)
+		}
+	}
+}
+
+func (t *Terrain) placeChunkTrees(c *chunk.Chunk, originX, originZ int32) {
+	r := t.chunkRandom(originX, originZ, 2)
+	center := t.column(int(originX)*16+8, int(originZ)*16+8)
+	kind, count := treesPerChunk(center.biome)
+
+	for i := 0; i < count; i++ {
+		x := int(originX)*16 + r.Intn(16)
+		z := int(originZ)*16 + r.Intn(16)
+		height := 4 + r.Intn(3)
+		seed := r.Int63()
+
+		col := t.column(x, z)
+		if col.biome != center.biome {
+			continue
+		}
+		top := t.surfaceY(x, z, col)
+		if top < SeaLevel {
+			continue
+		}
+
+		t.placeTree(c, kind, x, top+1, z, height, rand.New(rand.NewSource(seed)))
+	}
+}
+
+// placeTree writes the blocks of one tree that fall inside the chunk.
+func (t *Terrain) placeTree(c *chunk.Chunk, kind treeKind, x, y, z, height int, r *rand.Rand) {
+	blocks := t.blocks.trees[kind]
+
+	set := func(bx, by, bz int, state block.StateID) {
+		lx, lz := bx-int(c.X)*16, bz-int(c.Z)*16
+		if lx < 0 || lx > 15 || lz < 0 || lz > 15 || by > c.MaxY() {
+			return
+		}
+		existing := c.Block(lx, by, lz)
+		if existing == t.blocks.air || (state == blocks.log && existing == blocks.leaves) {
+			c.SetBlock(lx, by, lz, state)
+		}
+	}
+
+	if kind == spruceTree {
+		height += 2
+		// a cone of leaves narrowing towards the top
+		radius := 0
+		for ly := y + height; ly >= y+2; ly-- {
+			for dz := -radius; dz <= radius; dz++ {
+				for dx := -radius; dx <= radius; dx++ {
+					if radius > 0 && abs(dx) == radius && abs(dz) == radius {
+						continue
+					}
+					set(x+dx, ly, z+dz, blocks.leaves)
+				}
+			}
+			radius++
+			if radius > 2 {
+				radius = 1
+			}
+		}
+		set(x, y+height+1, z, blocks.leaves)
+	} else {
+		if kind == birchTree {
+			height++
+		}
+		for ly := y + height - 3; ly <= y+height; ly++ {
+			radius := 2
+			if ly >= y+height-1 {
+				radius = 1
+			}
+			for dz := -radius; dz <= radius; dz++ {
+				for dx := -radius; dx <= radius; dx++ {
+					corner := abs(dx) == radius && abs(dz) == radius
+					if corner && (ly == y+height || r.Intn(2) == 0) {
+						continue
+					}
+					set(x+dx, ly, z+dz, blocks.leaves)
+				}
+			}
+		}
+	}
+
+	for ly := y; ly < y+height; ly++ {
+		set(x, ly, z, blocks.log)
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+const (
+	testMinY   = -64
+	testHeight = 384
+)
+
+var testChunks = [][2]int32{{0, 0}, {1, 0}, {-1, 3}, {20, -7}, {-150, 42}}
+
+// digest hashes the blocks and biomes of a chunk.
+func digest(c *chunk.Chunk) string {
+	h := sha256.New()
+	var buf [4]byte
+	for y := c.MinY; y < c.MaxY(); y++ {
+		for z := 0; z < 16; z++ {
+			for x := 0; x < 16; x++ {
+				binary.LittleEndian.PutUint32(buf[:], uint32(c.Block(x, y, z)))
+				h.Write(buf[:])
+			}
+		}
+	}
+	for y := c.MinY; y < c.MaxY(); y += 4 {
+		for z := 0; z < 16; z += 4 {
+			for x := 0; x < 16; x += 4 {
+				binary.LittleEndian.PutUint32(buf[:], uint32(c.Biome(x, y, z)))
+				h.Write(buf[:])
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func generate(t *testing.T, terrain *Terrain, pos [2]int32) string {
+	t.Helper()
+	c := chunk.New(pos[0], pos[1], testMinY, testHeight)
+	terrain.Generate(c)
+	return digest(c)
+}
+
+func TestTerrainDeterministic(t *testing.T) {
+	a, err := NewTerrain(12345)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewTerrain(12345)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := make([]string, len(testChunks))
+	for i, pos := range testChunks {
+		first[i] = generate(t, a, pos)
+	}
+	// generated in reverse so neighbors come first
+	for i := len(testChunks) - 1; i >= 0; i-- {
+		if got := generate(t, b, testChunks[i]); got != first[i] {
+			t.Errorf("chunk %v differs between generators with the same seed", testChunks[i])
+		}
+	}
+	for i, pos := range testChunks {
+		if got := generate(t, a, pos); got != first[i] {
+			t.Errorf("chunk %v differs when generated again", pos)
+		}
+	}
+}
+
+func TestTerrainSnapshot(t *testing.T) {
+	terrain, err := NewTerrain(12345)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// update these only when changing the generator on purpose, as worlds
+	// saved before would get seams at the border of new chunks
+	want := map[[2]int32]string{
+		{0, 0}:     "023b9c4b546ed01040bcdf71f2d2768550564deeab46925053948588cdca473b",
+		{1, 0}:     "01f932e9b25d7116aeb1ea8e86988c7454a06f07790cc760ee9bde02aaeebcdf",
+		{-1, 3}:    "68f07a11f3246ff0ea8d31845c46ace507ab44cecb5ee1f4ae79abd1a691bd20",
+		{20, -7}:   "17002dc8e230a5f2622c9bb168c48423083bbb51f022dad1d64e8b520e57f500",
+		{-150, 42}: "cca1cd24d4ba49691bfdbc313447f97d175e2f18aaa85656be5e58f9aa34d2c1",
+	}
+	for _, pos := range testChunks {
+		if got := generate(t, terrain, pos); got != want[pos] {
+			t.Errorf("chunk %v = %s, want %s", pos, got, want[pos])
+		}
+	}
+}
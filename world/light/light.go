@@ -0,0 +1,337 @@
+// Package light propagates sky light and block light through chunks with
+// breadth first flood fills, across section and chunk borders.
+//
+// https://minecraft.wiki/w/Light
+package light
+
+import (
+	"github.com/hunterros-s/algernon/world/block"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+// Chunks looks up the loaded chunk at chunk coordinates. Light stops at chunks
+// that aren't loaded and is spread into them by Stitch once they are.
+type Chunks func(x, z int32) (*chunk.Chunk, bool)
+
+type kind int
+
+const (
+	skyLight kind = iota
+	blockLight
+)
+
+// node is a queued block position and, for removals, the level it had.
+type node struct {
+	x, y, z int
+	level   uint8
+}
+
+var directions = [6][3]int{
+	{0, -1, 0}, // down first, see spread
+	{0, 1, 0},
+	{-1, 0, 0},
+	{1, 0, 0},
+	{0, 0, -1},
+	{0, 0, 1},
+}
+
+// propagator runs the flood fills of one kind of light.
+type propagator struct {
+	kind     kind
+	chunks   Chunks
+	registry *block.Registry
+
+	cache   map[[2]int32]*chunk.Chunk
+	changed map[*chunk.Chunk]bool
+
+	increase []node
+	decrease []node
+}
+
+func newPropagator(k kind, chunks Chunks, changed map[*chunk.Chunk]bool) *propagator {
+	return &propagator{
+		kind:     k,
+		chunks:   chunks,
+		registry: block.Default(),
+		cache:    make(map[[2]int32]*chunk.Chunk),
+		changed:  changed,
+	}
+}
+
+// chunk returns the loaded chunk containing a block position, or nil.
+func (p *propagator) chunk(x, z int) *chunk.Chunk {
+	key := [2]int32{int32(x >> 4), int32(z >> 4)}
+	c, ok := p.cache[key]
+	if !ok {
+		var loaded bool
+		if c, loaded = p.chunks(key[0], key[1]); !loaded {
+			c = nil
+		}
+		p.cache[key] = c
+	}
+	return c
+}
+
+func (p *propagator) get(c *chunk.Chunk, x, y, z int) uint8 {
+	if p.kind == skyLight {
+		return c.SkyLightAt(x&15, y, z&15)
+	}
+	return c.BlockLightAt(x&15, y, z&15)
+}
+
+func (p *propagator) set(c *chunk.Chunk, x, y, z int, level uint8) {
+	if p.kind == skyLight {
+		c.SetSkyLight(x&15, y, z&15, level)
+	} else {
+		c.SetBlockLight(x&15, y, z&15, level)
+	}
+	p.changed[c] = true
+}
+
+// opacity returns how much light is lost entering a block. Outside the world
+// is air.
+func (p *propagator) opacity(c *chunk.Chunk, x, y, z int) uint8 {
+	return p.registry.LightOpacity(c.Block(x&15, y, z&15))
+}
+
+func (p *propagator) emission(c *chunk.Chunk, x, y, z int) uint8 {
+	if p.kind == skyLight {
+		return 0
+	}
+	return p.registry.LightEmission(c.Block(x&15, y, z&15))
+}
+
+// spread floods light outwards from the increase queue.
+func (p *propagator) spread() {
+	for head := 0; head < len(p.increase); head++ {
+		n := p.increase[head]
+		c := p.chunk(n.x, n.z)
+		if c == nil {
+			continue
+		}
+		level := p.get(c, n.x, n.y, n.z)
+		if level <= 1 {
+			continue
+		}
+
+		for i, d := range directions {
+			x, y, z := n.x+d[0], n.y+d[1], n.z+d[2]
+			nc := p.chunk(x, z)
+			if nc == nil || nc.LightSection(y) < 0 {
+				continue
+			}
+
+			opacity := p.opacity(nc, x, y, z)
+			var next uint8
+			switch {
+			case p.kind == skyLight && i == 0 && level == block.MaxLight && opacity == 0:
+				// full sky light goes straight down without dimming
+				next = block.MaxLight
+			case opacity >= level:
+				continue
+			default:
+				next = level - max(1, opacity)
+			}
+
+			if next > p.get(nc, x, y, z) {
+				p.set(nc, x, y, z, next)
+				p.increase = append(p.increase, node{x: x, y: y, z: z})
+			}
+		}
+	}
+	p.increase = p.increase[:0]
+}
+
+// remove darkens the light that came from the decrease queue, queueing the
+// brighter light around the darkened area to fill it back in.
+func (p *propagator) remove() {
+	for head := 0; head < len(p.decrease); head++ {
+		n := p.decrease[head]
+
+		for i, d := range directions {
+			x, y, z := n.x+d[0], n.y+d[1], n.z+d[2]
+			c := p.chunk(x, z)
+			if c == nil || c.LightSection(y) < 0 {
+				continue
+			}
+
+			level := p.get(c, x, y, z)
+			if level == 0 {
+				continue
+			}
+
+			fromAbove := p.kind == skyLight && i == 0 && n.level == block.MaxLight && level == block.MaxLight
+			if level >= n.level && !fromAbove {
+				// lit by something else
+				p.increase = append(p.increase, node{x: x, y: y, z: z})
+				continue
+			}
+
+			p.set(c, x, y, z, 0)
+			p.decrease = append(p.decrease, node{x: x, y: y, z: z, level: level})
+			if e := p.emission(c, x, y, z); e > 0 {
+				p.set(c, x, y, z, e)
+				p.increase = append(p.increase, node{x: x, y: y, z: z})
+			}
+		}
+	}
+	p.decrease = p.decrease[:0]
+}
+
+// Compute calculates the light of a chunk on its own, ignoring its neighbors.
+// Stitch spreads it across the borders once the chunk is placed in the world.
+func Compute(c *chunk.Chunk) {
+	only := func(x, z int32) (*chunk.Chunk, bool) {
+		return c, x == c.X && z == c.Z
+	}
+	changed := map[*chunk.Chunk]bool{}
+
+	computeSky(c, newPropagator(skyLight, only, changed))
+	computeBlock(c, newPropagator(blockLight, only, changed))
+}
+
+func computeSky(c *chunk.Chunk, p *propagator) {
+	top := -1
+	for i := len(c.Sections) - 1; i >= 0; i-- {
+		if !c.Sections[i].IsEmpty() {
+			top = i
+			break
+		}
+	}
+
+	// light sections are offset by one for the section below the world
+	full := top + 2
+	for i := range c.SkyLight {
+		c.SkyLight[i] = make([]byte, chunk.LightArraySize)
+		if i >= full {
+			for j := range c.SkyLight[i] {
+				c.SkyLight[i][j] = 0xFF
+			}
+		}
+	}
+
+	// light pours down from the lowest fully lit layer
+	y := c.MinY + (full-1)*chunk.SectionWidth
+	baseX, baseZ := int(c.X)*16, int(c.Z)*16
+	for z := 0; z < 16; z++ {
+		for x := 0; x < 16; x++ {
+			p.increase = append(p.increase, node{x: baseX + x, y: y, z: baseZ + z})
+		}
+	}
+	p.spread()
+}
+
+func computeBlock(c *chunk.Chunk, p *propagator) {
+	for i := range c.BlockLight {
+		c.BlockLight[i] = nil
+	}
+
+	baseX, baseZ := int(c.X)*16, int(c.Z)*16
+	for i, s := range c.Sections {
+		glows := false
+		for _, id := range s.Blocks.Palette() {
+			if p.registry.LightEmission(block.StateID(id)) > 0 {
+				glows = true
+				break
+			}
+		}
+		if !glows {
+			continue
+		}
+
+		minY := c.MinY + i*chunk.SectionWidth
+		for y := minY; y < minY+chunk.SectionWidth; y++ {
+			for z := 0; z < 16; z++ {
+				for x := 0; x < 16; x++ {
+					if e := p.registry.LightEmission(c.Block(x, y, z)); e > 0 {
+						c.SetBlockLight(x, y, z, e)
+						p.increase = append(p.increase, node{x: baseX + x, y: y, z: baseZ + z})
+					}
+				}
+			}
+		}
+	}
+	p.spread()
+}
+
+// Stitch spreads light between a chunk and its loaded neighbors, both ways.
+// It returns every chunk whose light changed.
+func Stitch(c *chunk.Chunk, chunks Chunks) []*chunk.Chunk {
+	changed := map[*chunk.Chunk]bool{}
+
+	baseX, baseZ := int(c.X)*16, int(c.Z)*16
+	minY, maxY := c.MinY-chunk.SectionWidth, c.MaxY()+chunk.SectionWidth
+
+	for _, k := range []kind{skyLight, blockLight} {
+		p := newPropagator(k, chunks, changed)
+
+		// pairs of columns on either side of each border
+		for i := 0; i < 16; i++ {
+			pairs := [4][4]int{
+				{baseX, baseZ + i, baseX - 1, baseZ + i},
+				{baseX + 15, baseZ + i, baseX + 16, baseZ + i},
+				{baseX + i, baseZ, baseX + i, baseZ - 1},
+				{baseX + i, baseZ + 15, baseX + i, baseZ + 16},
+			}
+			for _, pair := range pairs {
+				neighbor := p.chunk(pair[2], pair[3])
+				if neighbor == nil {
+					continue
+				}
+				for y := minY; y <= maxY; y++ {
+					// only light brighter than what it would spread to moves
+					a := p.get(c, pair[0], y, pair[1])
+					b := p.get(neighbor, pair[2], y, pair[3])
+					if a > b+1 {
+						p.increase = append(p.increase, node{x: pair[0], y: y, z: pair[1]})
+					} else if b > a+1 {
+						p.increase = append(p.increase, node{x: pair[2], y: y, z: pair[3]})
+					}
+				}
+			}
+		}
+		p.spread()
+	}
+
+	return keys(changed)
+}
+
+// Update relights the area around a block that changed. It returns every chunk
+// whose light changed.
+func Update(x, y, z int, chunks Chunks) []*chunk.Chunk {
+	changed := map[*chunk.Chunk]bool{}
+
+	for _, k := range []kind{skyLight, blockLight} {
+		p := newPropagator(k, chunks, changed)
+		c := p.chunk(x, z)
+		if c == nil || c.LightSection(y) < 0 {
+			return nil
+		}
+
+		if level := p.get(c, x, y, z); level > 0 {
+			p.set(c, x, y, z, 0)
+			p.decrease = append(p.decrease, node{x: x, y: y, z: z, level: level})
+			p.remove()
+		}
+
+		if e := p.emission(c, x, y, z); e > 0 {
+			p.set(c, x, y, z, e)
+			p.increase = append(p.increase, node{x: x, y: y, z: z})
+		}
+		// light may now pass through the block from any side
+		for _, d := range directions {
+			p.increase = append(p.increase, node{x: x + d[0], y: y + d[1], z: z + d[2]})
+		}
+		p.spread()
+	}
+
+	return keys(changed)
+}
+
+func keys(set map[*chunk.Chunk]bool) []*chunk.Chunk {
+	chunks := make([]*chunk.Chunk, 0, len(set))
+	for c := range set {
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
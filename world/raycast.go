@@ -0,0 +1,60 @@
+package world
+
+import (
+	"math"
+
+	"github.com/hunterros-s/algernon/world/block"
+)
+
+// FirstObstruction returns the first block filling its whole cube that the
+// segment between two points passes through, not counting the block it starts
+// in. It walks through the blocks the segment crosses in order.
+func (w *World) FirstObstruction(x0, y0, z0, x1, y1, z1 float64) (x, y, z int, ok bool) {
+	registry := block.Default()
+	dx, dy, dz := x1-x0, y1-y0, z1-z0
+	x, y, z = int(math.Floor(x0)), int(math.Floor(y0)), int(math.Floor(z0))
+	endX, endY, endZ := int(math.Floor(x1)), int(math.Floor(y1)), int(math.Floor(z1))
+
+	stepX, nextX, deltaX := traversal(x0, dx)
+	stepY, nextY, deltaY := traversal(y0, dy)
+	stepZ, nextZ, deltaZ := traversal(z0, dz)
+	for x != endX || y != endY || z != endZ {
+		switch {
+		case nextX <= nextY && nextX <= nextZ:
+			if nextX > 1 {
+				return 0, 0, 0, false
+			}
+			x += stepX
+			nextX += deltaX
+		case nextY <= nextZ:
+			if nextY > 1 {
+				return 0, 0, 0, false
+			}
+			y += stepY
+			nextY += deltaY
+		default:
+			if nextZ > 1 {
+				return 0, 0, 0, false
+			}
+			z += stepZ
+			nextZ += deltaZ
+		}
+		if registry.IsFullCube(w.Block(x, y, z)) {
+			return x, y, z, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// traversal returns the direction a segment crosses blocks in on an axis, the
+// fraction of the segment after which it crosses the first block boundary,
+// and the fraction it takes to cross a whole block.
+func traversal(start, d float64) (step int, next, delta float64) {
+	switch {
+	case d > 0:
+		return 1, (math.Floor(start) + 1 - start) / d, 1 / d
+	case d < 0:
+		return -1, (start - math.Floor(start)) / -d, 1 / -d
+	}
+	return 0, math.Inf(1), math.Inf(1)
+}
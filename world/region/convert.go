@@ -0,0 +1,290 @@
+package region
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/world/biome"
+	"github.com/hunterros-s/algernon/world/block"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+// DataVersion is the data version of 1.21 chunks, written on save.
+const DataVersion = 3953
+
+// minDataVersion is the first version using the 1.18 chunk layout without the
+// Level compound.
+const minDataVersion = 2860
+
+// statusFull marks chunks that finished generating. Chunks in any other
+// status are treated as missing so they get generated again.
+const statusFull = "minecraft:full"
+
+// convertedTags are the tags of saved chunks ChunkFromNBT reads into the chunk
+// and ChunkToNBT writes from it. The others are kept in Chunk.Extra.
+var convertedTags = []string{
+	"DataVersion", "xPos", "zPos", "yPos", "Status", "sections", "Heightmaps", "isLightOn", "block_entities",
+}
+
+// ChunkFromNBT converts the NBT of a saved chunk. Chunks with blocks or biomes
+// the registries don't know, or saved by a newer version, fail to load rather
+// than lose them when saved again.
+func ChunkFromNBT(root nbt.Compound, minY, height int) (*chunk.Chunk, error) {
+	version, _ := root.Int("DataVersion")
+	if version < minDataVersion {
+		return nil, fmt.Errorf("region: chunk data version %d is older than 1.18", version)
+	}
+	if version > DataVersion {
+		return nil, fmt.Errorf("region: chunk data version %d is newer than 1.21", version)
+	}
+	if status, _ := root.String("Status"); status != statusFull && status != "full" {
+		return nil, ErrNotExist
+	}
+
+	x, _ := root.Int("xPos")
+	z, _ := root.Int("zPos")
+	c := chunk.New(x, z, minY, height)
+
+	c.Extra = make(nbt.Compound, len(root))
+	for k, v := range root {
+		c.Extra[k] = v
+	}
+	for _, k := range convertedTags {
+		delete(c.Extra, k)
+	}
+
+	sections, _ := root.Compounds("sections")
+	minSection := minY >> 4
+	for _, tag := range sections {
+		y, _ := tag.Byte("Y")
+		i := int(y) - minSection
+
+		// light sections reach one section further than block sections
+		if i+1 >= 0 && i+1 < len(c.SkyLight) {
+			if light, ok := tag.ByteArray("SkyLight"); ok && len(light) == chunk.LightArraySize {
+				c.SkyLight[i+1] = light
+			}
+			if light, ok := tag.ByteArray("BlockLight"); ok && len(light) == chunk.LightArraySize {
+				c.BlockLight[i+1] = light
+			}
+		}
+
+		if i < 0 || i >= len(c.Sections) {
+			continue
+		}
+
+		blocks, err := blocksFromNBT(tag)
+		if err != nil {
+			return nil, fmt.Errorf("region: section %d of chunk %d, %d: %w", y, x, z, err)
+		}
+		biomes, err := biomesFromNBT(tag)
+		if err != nil {
+			return nil, fmt.Errorf("region: section %d of chunk %d, %d: %w", y, x, z, err)
+		}
+		c.Sections[i] = chunk.NewSectionFrom(blocks, biomes)
+	}
+
+	entities, _ := root.Compounds("block_entities")
+	for _, data := range entities {
+		bx, okX := data.Int("x")
+		by, okY := data.Int("y")
+		bz, okZ := data.Int("z")
+		if !okX || !okY || !okZ || bx>>4 != x || bz>>4 != z {
+			return nil, fmt.Errorf("region: block entity of chunk %d, %d outside of it", x, z)
+		}
+		c.SetBlockEntity(int(bx), int(by), int(bz), data)
+	}
+
+	c.RecalculateHeightmaps()
+	c.TakeDirty()
+	return c, nil
+}
+
+func blocksFromNBT(section nbt.Compound) (*chunk.PalettedContainer, error) {
+	states, ok := section.Compound("block_states")
+	if !ok {
+		return chunk.NewBlockContainer(block.Air), nil
+	}
+
+	tags, _ := states.Compounds("palette")
+	if len(tags) == 0 {
+		return chunk.NewBlockContainer(block.Air), nil
+	}
+
+	reg := block.Default()
+	palette := make([]uint32, len(tags))
+	for i, tag := range tags {
+		name, _ := tag.String("Name")
+		props := map[string]string{}
+		if properties, ok := tag.Compound("Properties"); ok {
+			for k, v := range properties {
+				props[k], _ = v.(string)
+			}
+		}
+
+		state, err := reg.StateFromProperties(name, props)
+		if err != nil {
+			return nil, err
+		}
+		palette[i] = uint32(state.ID)
+	}
+
+	data, _ := states.LongArray("data")
+	return unpack(chunk.NewBlockContainer(block.StateID(palette[0])), palette, data, 4)
+}
+
+func biomesFromNBT(section nbt.Compound) (*chunk.PalettedContainer, error) {
+	biomes, ok := section.Compound("biomes")
+	if !ok {
+		return chunk.NewBiomeContainer(biome.Plains), nil
+	}
+
+	names, _ := biomes.List("palette")
+	if len(names) == 0 {
+		return chunk.NewBiomeContainer(biome.Plains), nil
+	}
+
+	palette := make([]uint32, len(names))
+	for i, v := range names {
+		name, _ := v.(string)
+		id, ok := biome.Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown biome: %s", name)
+		}
+		palette[i] = uint32(id)
+	}
+
+	data, _ := biomes.LongArray("data")
+	return unpack(chunk.NewBiomeContainer(biome.ID(palette[0])), palette, data, 0)
+}
+
+// unpack fills a container from the storage layout, where entries index a
+// palette with at least minBits bits each.
+func unpack(p *chunk.PalettedContainer, palette []uint32, data []int64, minBits int) (*chunk.PalettedContainer, error) {
+	if len(palette) == 1 {
+		return p, nil
+	}
+
+	bitsPerEntry := max(minBits, bits.Len(uint(len(palette)-1)))
+	perLong := 64 / bitsPerEntry
+	if len(data) < (p.Len()+perLong-1)/perLong {
+		return nil, fmt.Errorf("expected %d longs of paletted data, got %d", (p.Len()+perLong-1)/perLong, len(data))
+	}
+
+	mask := uint64(1)<<bitsPerEntry - 1
+	for i := 0; i < p.Len(); i++ {
+		index := int(uint64(data[i/perLong]) >> ((i % perLong) * bitsPerEntry) & mask)
+		if index >= len(palette) {
+			return nil, fmt.Errorf("palette index %d out of range", index)
+		}
+		p.Set(i, palette[index])
+	}
+	return p, nil
+}
+
+// ChunkToNBT converts a chunk to the layout vanilla saves, along with the tags
+// it was loaded with that the server doesn't use.
+func ChunkToNBT(c *chunk.Chunk) nbt.Compound {
+	reg := block.Default()
+	minSection := c.MinY >> 4
+
+	sections := []nbt.Compound{}
+	for i := -1; i <= len(c.Sections); i++ {
+		tag := nbt.Compound{"Y": int8(minSection + i)}
+
+		if c.SkyLight[i+1] != nil {
+			tag["SkyLight"] = c.SkyLight[i+1]
+		}
+		if c.BlockLight[i+1] != nil {
+			tag["BlockLight"] = c.BlockLight[i+1]
+		}
+
+		if i >= 0 && i < len(c.Sections) {
+			s := c.Sections[i]
+
+			states, blockData := pack(s.Blocks, 4)
+			palette := make([]nbt.Compound, len(states))
+			for j, id := range states {
+				palette[j] = stateToNBT(reg, block.StateID(id))
+			}
+			blockStates := nbt.Compound{"palette": palette}
+			if blockData != nil {
+				blockStates["data"] = blockData
+			}
+			tag["block_states"] = blockStates
+
+			ids, biomeData := pack(s.Biomes, 0)
+			names := make([]string, len(ids))
+			for j, id := range ids {
+				names[j] = biome.Name(biome.ID(id))
+			}
+			biomes := nbt.Compound{"palette": names}
+			if biomeData != nil {
+				biomes["data"] = biomeData
+			}
+			tag["biomes"] = biomes
+		} else if len(tag) == 1 {
+			continue
+		}
+
+		sections = append(sections, tag)
+	}
+
+	root := nbt.Compound{
+		"LastUpdate":    int64(0),
+		"InhabitedTime": int64(0),
+	}
+	for k, v := range c.Extra {
+		root[k] = v
+	}
+	root["DataVersion"] = int32(DataVersion)
+	root["xPos"] = c.X
+	root["zPos"] = c.Z
+	root["yPos"] = int32(minSection)
+	root["Status"] = statusFull
+	root["isLightOn"] = int8(1)
+	root["sections"] = sections
+	root["Heightmaps"] = c.Heightmaps()
+	root["block_entities"] = c.BlockEntities()
+	return root
+}
+
+func stateToNBT(reg *block.Registry, id block.StateID) nbt.Compound {
+	state, ok := reg.State(id)
+	if !ok {
+		return nbt.Compound{"Name": "minecraft:air"}
+	}
+
+	tag := nbt.Compound{"Name": state.Block.Name}
+	if props := state.Properties(); len(props) > 0 {
+		properties := nbt.Compound{}
+		for k, v := range props {
+			properties[k] = v
+		}
+		tag["Properties"] = properties
+	}
+	return tag
+}
+
+// pack converts a container to the storage layout, a palette and the entries
+// indexing it with at least minBits bits each. data is nil for a single value.
+func pack(p *chunk.PalettedContainer, minBits int) (palette []uint32, data []int64) {
+	palette = p.Palette()
+	if len(palette) == 1 {
+		return palette, nil
+	}
+
+	indices := make(map[uint32]int, len(palette))
+	for i, v := range palette {
+		indices[v] = i
+	}
+
+	bitsPerEntry := max(minBits, bits.Len(uint(len(palette)-1)))
+	perLong := 64 / bitsPerEntry
+	data = make([]int64, (p.Len()+perLong-1)/perLong)
+	for i := 0; i < p.Len(); i++ {
+		data[i/perLong] |= int64(indices[p.Get(i)]) << ((i % perLong) * bitsPerEntry)
+	}
+	return palette, data
+}
@@ -0,0 +1,132 @@
+package region
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Chunks compressed with LZ4 (since 1.20.5) use the framing of lz4-java's
+// LZ4BlockOutputStream: a sequence of blocks, each with a header, ended by an
+// empty block.
+const (
+	lz4Magic      = "LZ4Block"
+	lz4HeaderSize = len(lz4Magic) + 1 + 4 + 4 + 4
+
+	lz4MethodRaw = 0x10
+	lz4MethodLZ4 = 0x20
+)
+
+// decompressLZ4Stream reads every block of an LZ4BlockOutputStream.
+func decompressLZ4Stream(data []byte) ([]byte, error) {
+	var out bytes.Buffer
+
+	for len(data) > 0 {
+		if len(data) < lz4HeaderSize || string(data[:len(lz4Magic)]) != lz4Magic {
+			return nil, errors.New("region: invalid lz4 block header")
+		}
+
+		token := data[len(lz4Magic)]
+		compressedLen := int(binary.LittleEndian.Uint32(data[9:13]))
+		decompressedLen := int(binary.LittleEndian.Uint32(data[13:17]))
+		// data[17:21] is an xxhash32 checksum, which the zlib compressed
+		// formats don't have either, so it isn't verified
+		data = data[lz4HeaderSize:]
+
+		if decompressedLen == 0 {
+			break
+		}
+		if compressedLen < 0 || compressedLen > len(data) {
+			return nil, errors.New("region: truncated lz4 block")
+		}
+		block := data[:compressedLen]
+		data = data[compressedLen:]
+
+		switch token & 0xF0 {
+		case lz4MethodRaw:
+			out.Write(block)
+		case lz4MethodLZ4:
+			decompressed, err := decompressLZ4Block(block, decompressedLen)
+			if err != nil {
+				return nil, err
+			}
+			out.Write(decompressed)
+		default:
+			return nil, fmt.Errorf("region: unknown lz4 block method %#x", token&0xF0)
+		}
+	}
+
+	return out.Bytes(), nil
+}
+
+// decompressLZ4Block decodes a single raw LZ4 block.
+//
+// https://github.com/lz4/lz4/blob/dev/doc/lz4_Block_format.md
+func decompressLZ4Block(src []byte, size int) ([]byte, error) {
+	dst := make([]byte, 0, size)
+	i := 0
+
+	readLength := func(n int) (int, error) {
+		if n != 15 {
+			return n, nil
+		}
+		for {
+			if i >= len(src) {
+				return 0, errors.New("region: truncated lz4 length")
+			}
+			b := src[i]
+			i++
+			n += int(b)
+			if b != 255 {
+				return n, nil
+			}
+		}
+	}
+
+	for i < len(src) {
+		token := src[i]
+		i++
+
+		literals, err := readLength(int(token >> 4))
+		if err != nil {
+			return nil, err
+		}
+		if i+literals > len(src) {
+			return nil, errors.New("region: truncated lz4 literals")
+		}
+		dst = append(dst, src[i:i+literals]...)
+		i += literals
+
+		// the last sequence only has literals
+		if i >= len(src) {
+			break
+		}
+
+		if i+2 > len(src) {
+			return nil, errors.New("region: truncated lz4 offset")
+		}
+		offset := int(src[i]) | int(src[i+1])<<8
+		i += 2
+		if offset == 0 || offset > len(dst) {
+			return nil, errors.New("region: invalid lz4 offset")
+		}
+
+		match, err := readLength(int(token & 0x0F))
+		if err != nil {
+			return nil, err
+		}
+		match += 4
+
+		// matches may overlap the bytes they produce, so copy one at a time
+		start := len(dst) - offset
+		for j := 0; j < match; j++ {
+			dst = append(dst, dst[start+j])
+		}
+	}
+
+	if len(dst) != size {
+		return nil, fmt.Errorf("region: lz4 block decompressed to %d bytes, expected %d", len(dst), size)
+	}
+	return dst, nil
+}
@@ -0,0 +1,369 @@
+// Package region reads and writes chunks stored in vanilla's Anvil region
+// files (.mca).
+//
+// https://minecraft.wiki/w/Region_file_format
+package region
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Chunk compression types
+const (
+	CompressionGzip = 1
+	CompressionZlib = 2
+	CompressionNone = 3
+	CompressionLZ4  = 4
+
+	// set on the compression type when the chunk is stored in a .mcc file
+	externalFlag = 0x80
+)
+
+const (
+	sectorSize    = 4096
+	headerSectors = 2
+	chunksPerSide = 32
+	chunkCount    = chunksPerSide * chunksPerSide
+
+	// a chunk's sector count is stored in a single byte
+	maxSectors = 255
+)
+
+// ErrNotExist is returned when a chunk was never saved.
+var ErrNotExist = errors.New("region: chunk does not exist")
+
+// Region is a region file holding 32x32 chunks. The file is opened lazily and
+// may be closed at any time to free the handle, it is reopened when needed.
+type Region struct {
+	path   string
+	x, z   int32 // region coordinates
+	mutex  sync.Mutex
+	file   *os.File
+	loaded bool
+
+	locations  [chunkCount]uint32 // sector offset << 8 | sector count
+	timestamps [chunkCount]uint32
+	used       []bool // sectors in use, including the header
+}
+
+func newRegion(path string, x, z int32) *Region {
+	return &Region{path: path, x: x, z: z}
+}
+
+// Open opens a region file, creating it if it doesn't exist.
+func Open(path string, x, z int32) (*Region, error) {
+	r := newRegion(path, x, z)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Region) open() error {
+	if r.file != nil {
+		return nil
+	}
+
+	f, err := os.OpenFile(r.path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	r.file = f
+
+	if r.loaded {
+		return nil
+	}
+	if err := r.readHeader(); err != nil {
+		f.Close()
+		r.file = nil
+		return fmt.Errorf("error reading region header %s: %w", r.path, err)
+	}
+	r.loaded = true
+	return nil
+}
+
+func (r *Region) readHeader() error {
+	info, err := r.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if info.Size() < headerSectors*sectorSize {
+		// new or truncated file, start with an empty header
+		if _, err := r.file.WriteAt(make([]byte, headerSectors*sectorSize), 0); err != nil {
+			return err
+		}
+		r.used = []bool{true, true}
+		return nil
+	}
+
+	header := make([]byte, headerSectors*sectorSize)
+	if _, err := r.file.ReadAt(header, 0); err != nil {
+		return err
+	}
+
+	totalSectors := int((info.Size() + sectorSize - 1) / sectorSize)
+	r.used = make([]bool, totalSectors)
+	r.used[0], r.used[1] = true, true
+
+	for i := 0; i < chunkCount; i++ {
+		r.locations[i] = binary.BigEndian.Uint32(header[i*4:])
+		r.timestamps[i] = binary.BigEndian.Uint32(header[sectorSize+i*4:])
+
+		offset, count := r.sectors(i)
+		if offset < headerSectors || offset+count > totalSectors {
+			// points outside the file, treat the chunk as missing
+			r.locations[i] = 0
+			continue
+		}
+		for s := offset; s < offset+count; s++ {
+			r.used[s] = true
+		}
+	}
+	return nil
+}
+
+func (r *Region) sectors(i int) (offset, count int) {
+	return int(r.locations[i] >> 8), int(r.locations[i] & 0xFF)
+}
+
+func index(x, z int) int {
+	return (x & 31) + (z&31)*chunksPerSide
+}
+
+// Close closes the file handle. The region can still be used afterwards.
+func (r *Region) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Timestamp returns when a chunk was last saved.
+func (r *Region) Timestamp(x, z int) time.Time {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return time.Unix(int64(r.timestamps[index(x, z)]), 0)
+}
+
+// ReadChunk returns the decompressed NBT of a chunk, given in region relative
+// coordinates.
+func (r *Region) ReadChunk(x, z int) ([]byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	offset, count := r.sectors(index(x, z))
+	if offset == 0 {
+		return nil, ErrNotExist
+	}
+
+	data := make([]byte, count*sectorSize)
+	if _, err := r.file.ReadAt(data, int64(offset)*sectorSize); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	length := int(binary.BigEndian.Uint32(data))
+	if length < 1 || length > len(data)-4 {
+		return nil, fmt.Errorf("region: invalid length %d for chunk %d, %d", length, x, z)
+	}
+	compression := data[4]
+	payload := data[5 : 4+length]
+
+	if compression&externalFlag != 0 {
+		external, err := os.ReadFile(r.externalPath(x, z))
+		if err != nil {
+			return nil, fmt.Errorf("region: reading oversized chunk: %w", err)
+		}
+		payload = external
+		compression &^= externalFlag
+	}
+
+	return decompress(compression, payload)
+}
+
+// externalPath is the .mcc file of a chunk too large for the region file,
+// named after its absolute chunk coordinates.
+func (r *Region) externalPath(x, z int) string {
+	cx := r.x*chunksPerSide + int32(x&31)
+	cz := r.z*chunksPerSide + int32(z&31)
+	return filepath.Join(filepath.Dir(r.path), fmt.Sprintf("c.%d.%d.mcc", cx, cz))
+}
+
+func decompress(compression byte, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case CompressionZlib:
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case CompressionNone:
+		return data, nil
+	case CompressionLZ4:
+		return decompressLZ4Stream(data)
+	default:
+		return nil, fmt.Errorf("region: unsupported compression type %d", compression)
+	}
+}
+
+// WriteChunk stores the NBT of a chunk, given in region relative coordinates.
+// Chunks are always written zlib compressed, like vanilla does by default.
+//
+// The chunk is written to free sectors before the header points at them, so
+// the previous version stays intact until the new one is complete.
+func (r *Region) WriteChunk(x, z int, data []byte) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.open(); err != nil {
+		return err
+	}
+
+	compression := byte(CompressionZlib)
+	payload := compressed.Bytes()
+	external := r.externalPath(x, z)
+
+	oversized := sectorsFor(len(payload)) > maxSectors
+	if oversized {
+		// replaced whole so a crash leaves either version
+		if err := writeFileAtomic(external, payload); err != nil {
+			return err
+		}
+		compression |= externalFlag
+		payload = nil
+	}
+
+	count := sectorsFor(len(payload))
+	offset := r.allocate(count)
+
+	buf := make([]byte, count*sectorSize)
+	binary.BigEndian.PutUint32(buf, uint32(len(payload)+1))
+	buf[4] = compression
+	copy(buf[5:], payload)
+	if _, err := r.file.WriteAt(buf, int64(offset)*sectorSize); err != nil {
+		r.free(offset, count)
+		return err
+	}
+
+	i := index(x, z)
+	previous, previousTime := r.locations[i], r.timestamps[i]
+	r.locations[i] = uint32(offset)<<8 | uint32(count)
+	r.timestamps[i] = uint32(time.Now().Unix())
+	if err := r.writeHeaderEntry(i); err != nil {
+		r.locations[i], r.timestamps[i] = previous, previousTime
+		r.free(offset, count)
+		return err
+	}
+	r.free(int(previous>>8), int(previous&0xFF))
+
+	if !oversized {
+		if err := os.Remove(external); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func sectorsFor(payloadLen int) int {
+	return (payloadLen + 5 + sectorSize - 1) / sectorSize
+}
+
+// writeFileAtomic replaces a file by renaming a temporary one over it.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// free marks sectors as unused.
+func (r *Region) free(offset, count int) {
+	if offset < headerSectors {
+		return
+	}
+	for s := offset; s < offset+count && s < len(r.used); s++ {
+		r.used[s] = false
+	}
+}
+
+// allocate finds the first run of free sectors, growing the file if needed.
+func (r *Region) allocate(count int) int {
+	run := 0
+	for s := headerSectors; s < len(r.used); s++ {
+		if r.used[s] {
+			run = 0
+			continue
+		}
+		run++
+		if run == count {
+			start := s - count + 1
+			for j := start; j <= s; j++ {
+				r.used[j] = true
+			}
+			return start
+		}
+	}
+
+	// extend the file, reusing any free sectors at its end
+	start := len(r.used) - run
+	for len(r.used) < start+count {
+		r.used = append(r.used, false)
+	}
+	for j := start; j < start+count; j++ {
+		r.used[j] = true
+	}
+	return start
+}
+
+func (r *Region) writeHeaderEntry(i int) error {
+	entry := make([]byte, 4)
+
+	binary.BigEndian.PutUint32(entry, r.locations[i])
+	if _, err := r.file.WriteAt(entry, int64(i*4)); err != nil {
+		return err
+	}
+
+	binary.BigEndian.PutUint32(entry, r.timestamps[i])
+	_, err := r.file.WriteAt(entry, int64(sectorSize+i*4))
+	return err
+}
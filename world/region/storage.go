@@ -0,0 +1,161 @@
+package region
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/world/chunk"
+)
+
+// DefaultMaxOpen is how many region files a Storage keeps open by default.
+const DefaultMaxOpen = 256
+
+// Storage loads and saves chunks in a directory of region files, e.g.
+// world/region. It is safe for concurrent use.
+//
+// Only the maxOpen most recently used regions are kept, with their headers
+// and open files. The others are closed and forgotten once no chunk is being
+// read from or written to them.
+type Storage struct {
+	dir     string
+	minY    int
+	height  int
+	maxOpen int
+
+	mutex   sync.Mutex
+	regions map[[2]int32]*list.Element // values are *cachedRegion
+	lru     *list.List                 // most recently used at the front
+}
+
+// cachedRegion is a region of a Storage and how many reads and writes are
+// using it.
+type cachedRegion struct {
+	key    [2]int32
+	region *Region
+	users  int
+}
+
+// NewStorage creates a storage for a dimension spanning height blocks from
+// minY. At most maxOpen region files are kept open at once.
+func NewStorage(dir string, minY, height, maxOpen int) (*Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Storage{
+		dir:     dir,
+		minY:    minY,
+		height:  height,
+		maxOpen: max(maxOpen, 1),
+		regions: make(map[[2]int32]*list.Element),
+		lru:     list.New(),
+	}, nil
+}
+
+func (s *Storage) regionPath(key [2]int32) string {
+	return filepath.Join(s.dir, fmt.Sprintf("r.%d.%d.mca", key[0], key[1]))
+}
+
+// region returns the region containing a chunk, which must be handed back
+// with done once used.
+func (s *Storage) region(chunkX, chunkZ int32) *cachedRegion {
+	key := [2]int32{chunkX >> 5, chunkZ >> 5}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	elem, ok := s.regions[key]
+	if ok {
+		s.lru.MoveToFront(elem)
+	} else {
+		elem = s.lru.PushFront(&cachedRegion{key: key, region: newRegion(s.regionPath(key), key[0], key[1])})
+		s.regions[key] = elem
+	}
+	r := elem.Value.(*cachedRegion)
+	r.users++
+	return r
+}
+
+// done hands back a region, closing and forgetting the least recently used
+// regions nothing is using while more than maxOpen are kept.
+func (s *Storage) done(r *cachedRegion) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	r.users--
+	for elem := s.lru.Back(); elem != nil && s.lru.Len() > s.maxOpen; {
+		prev := elem.Prev()
+		if old := elem.Value.(*cachedRegion); old.users == 0 {
+			s.lru.Remove(elem)
+			delete(s.regions, old.key)
+			old.region.Close()
+		}
+		elem = prev
+	}
+}
+
+// LoadChunk reads a chunk. ErrNotExist is returned for chunks that were never
+// saved or didn't finish generating.
+func (s *Storage) LoadChunk(x, z int32) (*chunk.Chunk, error) {
+	if _, err := os.Stat(s.regionPath([2]int32{x >> 5, z >> 5})); os.IsNotExist(err) {
+		// don't create empty region files just by looking for chunks
+		return nil, ErrNotExist
+	}
+
+	r := s.region(x, z)
+	data, err := r.region.ReadChunk(int(x), int(z))
+	s.done(r)
+	if err != nil {
+		return nil, err
+	}
+
+	_, root, err := nbt.Unmarshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("region: decoding chunk %d, %d: %w", x, z, err)
+	}
+	return ChunkFromNBT(root, s.minY, s.height)
+}
+
+// SaveChunk writes a chunk to its region file.
+func (s *Storage) SaveChunk(c *chunk.Chunk) error {
+	data, err := EncodeChunk(c)
+	if err != nil {
+		return err
+	}
+	return s.WriteChunk(c.X, c.Z, data)
+}
+
+// EncodeChunk encodes a chunk as it is saved, a snapshot WriteChunk can write
+// while the chunk keeps changing.
+func EncodeChunk(c *chunk.Chunk) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := nbt.Encode(&buf, "", ChunkToNBT(c)); err != nil {
+		return nil, fmt.Errorf("region: encoding chunk %d, %d: %w", c.X, c.Z, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteChunk writes a chunk encoded with EncodeChunk to its region file.
+func (s *Storage) WriteChunk(x, z int32, data []byte) error {
+	r := s.region(x, z)
+	defer s.done(r)
+	return r.region.WriteChunk(int(x), int(z), data)
+}
+
+// Close closes every region file.
+func (s *Storage) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var firstErr error
+	for _, elem := range s.regions {
+		if err := elem.Value.(*cachedRegion).region.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
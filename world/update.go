@@ -0,0 +1,55 @@
+package world
+
+import "github.com/hunterros-s/algernon/world/block"
+
+// maxShapeUpdates is how many blocks one change updates the shape of at most,
+// so shapes changing each other can't go on forever.
+const maxShapeUpdates = 512
+
+// SetBlocks changes blocks, then updates the shape of the blocks around them,
+// like fences connecting to a placed block or the other half of a broken door
+// going, and around those that change in turn. It returns every block changed,
+// once each with the state it ended with. Nothing changes unless every block
+// is in a loaded chunk and within the height of the world. It must be called
+// from the goroutine changing blocks.
+func (w *World) SetBlocks(changes ...block.Change) ([]block.Change, bool) {
+	for _, c := range changes {
+		if !w.Loaded(c.X, c.Z) || c.Y < MinY || c.Y >= MinY+Height {
+			return nil, false
+		}
+	}
+
+	registry := block.Default()
+	var changed []block.Change
+	index := make(map[[3]int]int)
+	var queue [][3]int
+	set := func(x, y, z int, state block.StateID) {
+		if old, _ := w.SetBlock(x, y, z, state); old == state {
+			return
+		}
+		pos := [3]int{x, y, z}
+		if i, ok := index[pos]; ok {
+			changed[i].State = state
+		} else {
+			index[pos] = len(changed)
+			changed = append(changed, block.Change{X: x, Y: y, Z: z, State: state})
+		}
+		queue = append(queue, pos)
+		for d := block.Down; d <= block.East; d++ {
+			dx, dy, dz := d.Offset()
+			queue = append(queue, [3]int{x + dx, y + dy, z + dz})
+		}
+	}
+
+	for _, c := range changes {
+		set(c.X, c.Y, c.Z, c.State)
+	}
+	for i := 0; i < len(queue) && i < maxShapeUpdates; i++ {
+		x, y, z := queue[i][0], queue[i][1], queue[i][2]
+		if !w.Loaded(x, z) || y < MinY || y >= MinY+Height {
+			continue
+		}
+		set(x, y, z, registry.UpdateShape(w, x, y, z))
+	}
+	return changed, true
+}
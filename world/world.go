@@ -0,0 +1,496 @@
+// Package world ties chunk storage and generation together into the blocks
+// players interact with.
+package world
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hunterros-s/algernon/nbt"
+	"github.com/hunterros-s/algernon/world/block"
+	"github.com/hunterros-s/algernon/world/chunk"
+	"github.com/hunterros-s/algernon/world/light"
+	"github.com/hunterros-s/algernon/world/region"
+)
+
+// Overworld dimensions
+const (
+	MinY   = -64
+	Height = 384
+)
+
+// Generator fills newly created chunks.
+type Generator interface {
+	Generate(c *chunk.Chunk)
+}
+
+// ChunkPos is the position of a chunk column.
+type ChunkPos struct {
+	X, Z int32
+}
+
+// ChunkPosAt returns the chunk containing a block position.
+func ChunkPosAt(x, z int) ChunkPos {
+	return ChunkPos{int32(x >> 4), int32(z >> 4)}
+}
+
+// queueSize is how many chunk requests may wait for a worker.
+const queueSize = 4096
+
+// ChunkResult is a chunk requested with Request, or the error loading it. Save
+// errors of unloaded chunks are delivered as results without a chunk too.
+type ChunkResult struct {
+	Pos   ChunkPos
+	Chunk *chunk.Chunk
+	Err   error
+}
+
+// World holds the loaded chunks of a dimension. Chunks are loaded from storage
+// when it has them and generated otherwise, either synchronously with Chunk or
+// on a pool of workers with Request.
+//
+// Chunks stay in memory while they are retained, e.g. by being in view of a
+// player, and are saved and unloaded once the last one releases them. Without
+// storage, chunks that changed stay in memory instead.
+type World struct {
+	generator Generator
+	storage   *region.Storage // nil when chunks are only kept in memory
+
+	mutex     sync.Mutex
+	chunks    map[ChunkPos]*chunk.Chunk
+	pending   map[ChunkPos]bool
+	refs      map[ChunkPos]int
+	unloading map[ChunkPos]*chunk.Chunk // released chunks still being saved
+	kept      map[ChunkPos]*chunk.Chunk // released chunks that changed, without storage
+	saving    sync.WaitGroup
+
+	// the latest snapshot of each chunk waiting to be written, older ones are
+	// dropped. writeMutex orders the writes and is taken before mutex.
+	saves      map[ChunkPos]uint64
+	saveCount  uint64
+	writeMutex sync.Mutex
+
+	// newly loaded chunks whose light hasn't crossed into their neighbors, and
+	// chunks whose light changed since UpdateLight was last called
+	unstitched   map[ChunkPos]bool
+	lightChanged map[ChunkPos]bool
+
+	requests chan ChunkPos
+	ready    chan ChunkResult
+	done     chan struct{}
+	workers  sync.WaitGroup
+}
+
+// New creates a world with workers goroutines loading and generating
+// requested chunks. storage may be nil to never load or save chunks.
+func New(generator Generator, storage *region.Storage, workers int) *World {
+	w := &World{
+		generator: generator,
+		storage:   storage,
+		chunks:    make(map[ChunkPos]*chunk.Chunk),
+		pending:   make(map[ChunkPos]bool),
+		refs:      make(map[ChunkPos]int),
+		unloading: make(map[ChunkPos]*chunk.Chunk),
+		kept:      make(map[ChunkPos]*chunk.Chunk),
+		saves:     make(map[ChunkPos]uint64),
+
+		unstitched:   make(map[ChunkPos]bool),
+		lightChanged: make(map[ChunkPos]bool),
+		requests:     make(chan ChunkPos, queueSize),
+		ready:        make(chan ChunkResult, queueSize),
+		done:         make(chan struct{}),
+	}
+
+	for i := 0; i < max(workers, 1); i++ {
+		w.workers.Add(1)
+		go w.work()
+	}
+	return w
+}
+
+func (w *World) work() {
+	defer w.workers.Done()
+
+	for {
+		select {
+		case pos := <-w.requests:
+			c, err := w.loadOrGenerate(pos)
+
+			w.mutex.Lock()
+			delete(w.pending, pos)
+			if err == nil {
+				if existing, ok := w.loaded(pos); ok {
+					c = existing
+				} else if w.refs[pos] > 0 {
+					// chunks released while generating aren't kept
+					w.add(pos, c)
+				}
+			}
+			w.mutex.Unlock()
+
+			select {
+			case w.ready <- ChunkResult{Pos: pos, Chunk: c, Err: err}:
+			case <-w.done:
+				return
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// loaded returns a loaded chunk, taking back a chunk that is being unloaded.
+// The caller must hold the mutex.
+func (w *World) loaded(pos ChunkPos) (*chunk.Chunk, bool) {
+	if c, ok := w.chunks[pos]; ok {
+		return c, true
+	}
+	if c, ok := w.unloading[pos]; ok {
+		delete(w.unloading, pos)
+		w.chunks[pos] = c
+		return c, true
+	}
+	if c, ok := w.kept[pos]; ok {
+		delete(w.kept, pos)
+		w.chunks[pos] = c
+		return c, true
+	}
+	return nil, false
+}
+
+// Retain keeps a chunk in memory until it is released as many times as it was
+// retained. It doesn't load the chunk, see Request.
+func (w *World) Retain(pos ChunkPos) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.refs[pos]++
+}
+
+// Release undoes a Retain, unloading the chunk once nothing retains it anymore.
+// Chunks that changed since they were loaded or last saved are saved first, or
+// kept in memory without storage.
+func (w *World) Release(pos ChunkPos) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.refs[pos] > 1 {
+		w.refs[pos]--
+		return
+	}
+	delete(w.refs, pos)
+
+	c, ok := w.chunks[pos]
+	if !ok {
+		return
+	}
+	delete(w.chunks, pos)
+	if w.storage == nil {
+		// the changes would be lost when generating it again
+		if c.Dirty() {
+			w.kept[pos] = c
+		}
+		return
+	}
+
+	_, saving := w.saves[pos]
+	dirty := c.TakeDirty()
+	if !dirty && !saving {
+		return
+	}
+
+	// kept until written so it can be taken back instead of read from storage
+	// before the save lands
+	w.unloading[pos] = c
+	if !dirty {
+		return
+	}
+
+	// encoded now, as the chunk may be taken back and changed while writing
+	data, err := region.EncodeChunk(c)
+	w.saveCount++
+	count := w.saveCount
+	w.saves[pos] = count
+
+	w.saving.Add(1)
+	go func() {
+		defer w.saving.Done()
+
+		if err == nil {
+			err = w.write(pos, count, data)
+		}
+
+		w.mutex.Lock()
+		if w.unloading[pos] == c {
+			delete(w.unloading, pos)
+		}
+		w.mutex.Unlock()
+
+		if err != nil {
+			c.MarkDirty()
+			select {
+			case w.ready <- ChunkResult{Pos: pos, Err: fmt.Errorf("error saving chunk %d, %d: %w", pos.X, pos.Z, err)}:
+			case <-w.done:
+			}
+		}
+	}()
+}
+
+// write stores a snapshot of a chunk unless a newer one replaced it.
+func (w *World) write(pos ChunkPos, count uint64, data []byte) error {
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+
+	w.mutex.Lock()
+	latest := w.saves[pos] == count
+	w.mutex.Unlock()
+	if !latest {
+		return nil
+	}
+
+	err := w.storage.WriteChunk(pos.X, pos.Z, data)
+
+	w.mutex.Lock()
+	if w.saves[pos] == count {
+		delete(w.saves, pos)
+	}
+	w.mutex.Unlock()
+	return err
+}
+
+// Request asks the workers for a chunk without waiting for it. Loaded chunks
+// are returned straight away, others are delivered on Ready once available.
+// false is returned while the chunk isn't loaded, including when the queue is
+// full and the request has to be made again later.
+func (w *World) Request(pos ChunkPos) (*chunk.Chunk, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if c, ok := w.loaded(pos); ok {
+		return c, true
+	}
+	if w.pending[pos] {
+		return nil, false
+	}
+
+	select {
+	case w.requests <- pos:
+		w.pending[pos] = true
+	default:
+	}
+	return nil, false
+}
+
+// Ready delivers the chunks asked for with Request.
+func (w *World) Ready() <-chan ChunkResult {
+	return w.ready
+}
+
+// Chunk returns a chunk, loading or generating it if needed.
+func (w *World) Chunk(pos ChunkPos) (*chunk.Chunk, error) {
+	if c, ok := w.LoadedChunk(pos); ok {
+		return c, nil
+	}
+
+	// generated without the lock so other chunks can be used meanwhile
+	c, err := w.loadOrGenerate(pos)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if existing, ok := w.loaded(pos); ok {
+		return existing, nil
+	}
+	w.add(pos, c)
+	return c, nil
+}
+
+// add stores a newly loaded chunk. The caller must hold the mutex.
+func (w *World) add(pos ChunkPos, c *chunk.Chunk) {
+	w.chunks[pos] = c
+	w.unstitched[pos] = true
+}
+
+func (w *World) loadOrGenerate(pos ChunkPos) (*chunk.Chunk, error) {
+	if w.storage != nil {
+		c, err := w.storage.LoadChunk(pos.X, pos.Z)
+		if err == nil {
+			if c.SkyLight[0] == nil {
+				// saved without light
+				light.Compute(c)
+			}
+			return c, nil
+		}
+		if !errors.Is(err, region.ErrNotExist) {
+			return nil, fmt.Errorf("error loading chunk %d, %d: %w", pos.X, pos.Z, err)
+		}
+	}
+
+	c := chunk.New(pos.X, pos.Z, MinY, Height)
+	w.generator.Generate(c)
+	light.Compute(c)
+	// saved, while without storage they are only kept once they change, as
+	// they can be generated again until then
+	if w.storage != nil {
+		c.MarkDirty()
+	} else {
+		c.TakeDirty()
+	}
+	return c, nil
+}
+
+// Spawn returns the block position players spawn at, on top of the highest
+// block at the origin. It loads the chunk there if needed.
+func (w *World) Spawn() (x, y, z int, err error) {
+	c, err := w.Chunk(ChunkPos{0, 0})
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return 0, c.TopY(chunk.MotionBlocking, 0, 0), 0, nil
+}
+
+// LoadedChunk returns a chunk only if it is already loaded.
+func (w *World) LoadedChunk(pos ChunkPos) (*chunk.Chunk, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.loaded(pos)
+}
+
+// Loaded reports whether the chunk containing a block column is loaded.
+func (w *World) Loaded(x, z int) bool {
+	_, ok := w.LoadedChunk(ChunkPosAt(x, z))
+	return ok
+}
+
+// Block returns the state at a block position. Unloaded chunks read as air.
+func (w *World) Block(x, y, z int) block.StateID {
+	c, ok := w.LoadedChunk(ChunkPosAt(x, z))
+	if !ok {
+		return block.Air
+	}
+	return c.Block(x&15, y, z&15)
+}
+
+// SetBlock changes the state at a block position in a loaded chunk and returns
+// the previous state. The light around the block is updated.
+func (w *World) SetBlock(x, y, z int, state block.StateID) (block.StateID, bool) {
+	c, ok := w.LoadedChunk(ChunkPosAt(x, z))
+	if !ok {
+		return block.Air, false
+	}
+
+	old := c.SetBlock(x&15, y, z&15, state)
+	if old != state {
+		changed := light.Update(x, y, z, w.lightChunks)
+		for _, c := range changed {
+			c.MarkDirty()
+		}
+		w.markLightChanged(changed)
+	}
+	return old, true
+}
+
+// BlockEntity returns the data of the block entity at a block position in a
+// loaded chunk.
+func (w *World) BlockEntity(x, y, z int) (nbt.Compound, bool) {
+	c, ok := w.LoadedChunk(ChunkPosAt(x, z))
+	if !ok {
+		return nil, false
+	}
+	return c.BlockEntity(x&15, y, z&15)
+}
+
+// SetBlockEntity stores the data of the block entity at a block position in a
+// loaded chunk, or removes it when data is nil. It reports whether the chunk
+// was loaded.
+func (w *World) SetBlockEntity(x, y, z int, data nbt.Compound) bool {
+	c, ok := w.LoadedChunk(ChunkPosAt(x, z))
+	if !ok {
+		return false
+	}
+	c.SetBlockEntity(x&15, y, z&15, data)
+	return true
+}
+
+// lightChunks gives the light engine the loaded chunks.
+func (w *World) lightChunks(x, z int32) (*chunk.Chunk, bool) {
+	return w.LoadedChunk(ChunkPos{x, z})
+}
+
+func (w *World) markLightChanged(chunks []*chunk.Chunk) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	for _, c := range chunks {
+		w.lightChanged[ChunkPos{c.X, c.Z}] = true
+	}
+}
+
+// UpdateLight spreads the light of newly loaded chunks into their neighbors and
+// returns the chunks whose light changed since the last call, which players
+// need Update Light for. It must be called from the goroutine changing blocks.
+func (w *World) UpdateLight() []ChunkPos {
+	w.mutex.Lock()
+	stitch := make([]ChunkPos, 0, len(w.unstitched))
+	for pos := range w.unstitched {
+		stitch = append(stitch, pos)
+	}
+	clear(w.unstitched)
+	w.mutex.Unlock()
+
+	for _, pos := range stitch {
+		if c, ok := w.LoadedChunk(pos); ok {
+			w.markLightChanged(light.Stitch(c, w.lightChunks))
+		}
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	changed := make([]ChunkPos, 0, len(w.lightChanged))
+	for pos := range w.lightChanged {
+		changed = append(changed, pos)
+	}
+	clear(w.lightChanged)
+	return changed
+}
+
+// Save writes the loaded chunks that changed since they were last saved.
+func (w *World) Save() error {
+	if w.storage == nil {
+		return nil
+	}
+
+	w.writeMutex.Lock()
+	defer w.writeMutex.Unlock()
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for pos, c := range w.chunks {
+		if !c.TakeDirty() {
+			continue
+		}
+		// supersedes snapshots still waiting to be written
+		delete(w.saves, pos)
+		if err := w.storage.SaveChunk(c); err != nil {
+			c.MarkDirty()
+			return fmt.Errorf("error saving chunk %d, %d: %w", pos.X, pos.Z, err)
+		}
+	}
+	return nil
+}
+
+// Close stops the workers, saves the world and closes its storage.
+func (w *World) Close() error {
+	close(w.done)
+	w.workers.Wait()
+	w.saving.Wait()
+
+	if err := w.Save(); err != nil {
+		return err
+	}
+	if w.storage != nil {
+		return w.storage.Close()
+	}
+	return nil
+}